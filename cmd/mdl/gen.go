@@ -71,7 +71,7 @@ func gen(pkg string, out string, debug bool) error {
 	if err != nil && len(o) > 0 {
 		err = fmt.Errorf("%s, output:\n%s", err.Error(), o)
 	}
-	if debug {
+	if debug || err == nil && len(o) > 0 {
 		fmt.Fprintln(os.Stderr, o)
 	}
 
@@ -95,6 +95,9 @@ const mainT = `func main() {
 		fmt.Fprintf(os.Stderr, err.Error())
 		os.Exit(1)
 	}
+	for _, w := range expr.Root.Model.Warnings {
+		fmt.Fprintln(os.Stderr, "warning: "+w.Error())
+	}
 
 	// Render the views and serialize them
 	views := mdl.Render(expr.Root)
@@ -24,6 +24,7 @@ const DefaultTemplate = `<!DOCTYPE html>
 <body>
 	<div id="diagram"></div>
 	<div class="footer">
+		{{- if not .HideTitle }}
 		<div class="title">
 			{{ .Title }}
 			<div class="description">
@@ -33,12 +34,15 @@ const DefaultTemplate = `<!DOCTYPE html>
 				{{ .Version }}
 			</div>
 		</div>
+		{{- end }}
+		{{- if .MermaidLegendSource }}
 		<div class="legend">
 			<div class="legend-title">
 				Legend <span id="toggle">≚</span>
 			</div>
 			<div id="legend-diagram" style="display:none"></div>
 		</div>
+		{{- end }}
 	</div>
 	<script src="http://localhost:35729/livereload.js"></script>
 	<script src="https://cdn.jsdelivr.net/npm/mermaid/dist/mermaid.min.js"></script>
@@ -56,13 +60,15 @@ const DefaultTemplate = `<!DOCTYPE html>
 		});
 		var diagramSrc = ` + "`{{ .MermaidSource }}`;" + `
 		renderSvg(diagramSrc, "diagram")
+		{{- if .MermaidLegendSource }}
 		var legendSrc = ` + "`{{ .MermaidLegendSource }}`;" + `
 		renderSvg(legendSrc, "legend-diagram")
+		{{- end }}
 	</script>
 	<script>
 		var toggle = document.getElementById("toggle");
 		var legend = document.getElementById("legend-diagram");
-		toggle.addEventListener('click', function (event) {
+		toggle && toggle.addEventListener('click', function (event) {
 			if (legend.style.display == "") {
 				legend.style.display = "none";
 				toggle.innerHTML = "≚";
@@ -191,6 +197,8 @@ type ViewData struct {
 	Key string
 	// Title of view
 	Title string
+	// HideTitle is true if the title should not be rendered.
+	HideTitle bool
 	// Description of view
 	Description string
 	// Version of design
@@ -252,6 +260,7 @@ func render(pkg, config, out string, debug bool) error {
 		}
 		data := &ViewData{
 			Title:               view.Title,
+			HideTitle:           view.HideTitle,
 			Description:         view.Description,
 			Version:             view.Version,
 			MermaidSource:       template.JS(view.Mermaid),
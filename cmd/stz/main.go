@@ -110,6 +110,7 @@ func gen(pkg string, out string, debug bool) error {
 			codegen.SimpleImport("io/ioutil"),
 			codegen.SimpleImport("encoding/json"),
 			codegen.SimpleImport("os"),
+			codegen.SimpleImport("goa.design/model/expr"),
 			codegen.SimpleImport("goa.design/model/stz"),
 			codegen.NewImport("_", pkg),
 		}
@@ -135,7 +136,7 @@ func gen(pkg string, out string, debug bool) error {
 	// Run program
 	out, _ = filepath.Abs(out)
 	o, err := runCmd(filepath.Join(tmpDir, "stz"), tmpDir, "-out", out)
-	if debug {
+	if debug || err == nil && len(o) > 0 {
 		fmt.Fprintln(os.Stderr, o)
 	}
 	return err
@@ -253,6 +254,9 @@ const mainT = `func main() {
         fmt.Fprint(os.Stderr, err.Error())
         os.Exit(1)
 	}
+	for _, w := range expr.Root.Model.Warnings {
+		fmt.Fprintln(os.Stderr, "warning: "+w.Error())
+	}
 	b, err := json.MarshalIndent(w, "", "    ")
     if err != nil {
         fmt.Fprintf(os.Stderr, "failed to encode into JSON: %s", err.Error())
@@ -1,6 +1,7 @@
 package dsl
 
 import (
+	"strconv"
 	"strings"
 
 	"goa.design/goa/v3/eval"
@@ -121,6 +122,7 @@ func DeploymentNode(name string, args ...interface{}) *expr.DeploymentNode {
 			Description: description,
 			Technology:  technology,
 			DSLFunc:     dsl,
+			DSLLocation: callerLocation(),
 		},
 		Instances:   &one,
 		Environment: env,
@@ -190,6 +192,7 @@ func InfrastructureNode(name string, args ...interface{}) *expr.InfrastructureNo
 			Description: description,
 			Technology:  technology,
 			DSLFunc:     dsl,
+			DSLLocation: callerLocation(),
 		},
 		Environment: d.Environment,
 		Parent:      d,
@@ -247,7 +250,8 @@ func InfrastructureNode(name string, args ...interface{}) *expr.InfrastructureNo
 func ContainerInstance(container interface{}, dsl ...func()) *expr.ContainerInstance {
 	d, ok := eval.Current().(*expr.DeploymentNode)
 	if !ok {
-		eval.IncompatibleDSL()
+		eval.ReportError("ContainerInstance must be defined within a DeploymentNode")
+		return nil
 	}
 	var cont *expr.Container
 	switch c := container.(type) {
@@ -282,6 +286,7 @@ func ContainerInstance(container interface{}, dsl ...func()) *expr.ContainerInst
 			URL:         cont.URL,
 			Technology:  cont.Technology,
 			DSLFunc:     f,
+			DSLLocation: callerLocation(),
 		},
 		Parent:      d,
 		Environment: d.Environment,
@@ -341,6 +346,47 @@ func InstanceID(n int) {
 	node.InstanceID = n
 }
 
+// Scaling records the minimum and maximum number of replicas of a container
+// instance, for example as defined by a Kubernetes horizontal pod autoscaler.
+// The values are stored in the "scaling.min" and "scaling.max" properties of
+// the container instance.
+//
+// Scaling must appear in a ContainerInstance expression.
+//
+// Scaling accepts two arguments: the minimum and maximum number of replicas.
+// The minimum must be lower or equal to the maximum.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        DeploymentEnvironment("Production", func() {
+//            ContainerInstance(Container, func() {
+//                Scaling(2, 10)
+//            })
+//        })
+//    })
+//
+func Scaling(min, max int) {
+	ci, ok := eval.Current().(*expr.ContainerInstance)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if min < 0 || max < 0 {
+		eval.ReportError("Scaling: minimum and maximum must be non-negative, got %d and %d", min, max)
+		return
+	}
+	if min > max {
+		eval.ReportError("Scaling: minimum %d is greater than maximum %d", min, max)
+		return
+	}
+	if ci.Properties == nil {
+		ci.Properties = make(map[string]string)
+	}
+	ci.Properties[expr.ScalingMinProperty] = strconv.Itoa(min)
+	ci.Properties[expr.ScalingMaxProperty] = strconv.Itoa(max)
+}
+
 // HealthCheck defines a HTTP-based health check for a container instance.
 //
 // HealthCheck must appear in a ContainerInstance expression.
@@ -2,6 +2,8 @@ package dsl
 
 import (
 	"net/url"
+	"regexp"
+	"strings"
 
 	"goa.design/goa/v3/eval"
 	"goa.design/model/expr"
@@ -110,7 +112,12 @@ func Version(v string) {
 // Landscape and System Context diagrams, an enterprise is represented as a
 // dashed box. Only a single enterprise can be defined within a model.
 //
-// Enterprise must appear in a Design expression.
+// When used in a SoftwareSystem expression Enterprise assigns the software
+// system to the given enterprise instead. The enterprise name is stored in the
+// "Enterprise" property of the software system and is used by System
+// Landscape views that make use of GroupByEnterprise.
+//
+// Enterprise must appear in a Design or SoftwareSystem expression.
 //
 // Enterprise takes exactly one argument: the enterprise name.
 //
@@ -118,14 +125,26 @@ func Version(v string) {
 //
 //    var _ = Design(func() {
 //        Enterprise("Goa Design")
+//        SoftwareSystem("Partner System", func() {
+//            Enterprise("Partner")
+//        })
 //    })
 //
 func Enterprise(e string) {
-	w, ok := eval.Current().(*expr.Design)
-	if !ok {
+	switch v := eval.Current().(type) {
+	case *expr.Design:
+		v.Model.Enterprise = e
+	case *expr.SoftwareSystem:
+		if e == "" {
+			eval.ReportError("Enterprise: name cannot be empty")
+			return
+		}
+		if v.Properties == nil {
+			v.Properties = make(map[string]string)
+		}
+		v.Properties[expr.EnterpriseProperty] = e
+	default:
 		eval.IncompatibleDSL()
-	} else {
-		w.Model.Enterprise = e
 	}
 }
 
@@ -140,6 +159,10 @@ func Enterprise(e string) {
 //    * Container 1 to Component 2
 //    * Container 1 to Container 2
 //
+// Similarly a relationship from a person or a software system to a container
+// implies a relationship from the person or software system to the parent
+// software system of the container.
+//
 // AddImpliedRelationships must appear in Design.
 func AddImpliedRelationships() {
 	w, ok := eval.Current().(*expr.Design)
@@ -150,6 +173,464 @@ func AddImpliedRelationships() {
 	}
 }
 
+// SeparateImpliedRelationships adds implied relationships like
+// AddImpliedRelationships but records them separately from the relationships
+// defined explicitly in the design. The implied relationships are only merged
+// into the element relationships when serializing the workspace. This makes it
+// possible to review the implied relationships in isolation.
+//
+// SeparateImpliedRelationships must appear in Design.
+//
+// SeparateImpliedRelationships takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SeparateImpliedRelationships()
+//    })
+//
+func SeparateImpliedRelationships() {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.AddImpliedRelationships = true
+	w.Model.SeparateImpliedRelationships = true
+}
+
+// ImpliedModeKind is the enum used to define which implied relationships are
+// added.
+type ImpliedModeKind int
+
+const (
+	// ImpliedFull adds implied relationships between all the parents of
+	// the relationship source and destination.
+	ImpliedFull ImpliedModeKind = iota + 1
+	// ImpliedDirectParentOnly only adds implied relationships one level
+	// up from the relationship source or destination.
+	ImpliedDirectParentOnly
+)
+
+// ImpliedMode sets which implied relationships are added and enables the
+// generation of implied relationships (see AddImpliedRelationships). With
+// ImpliedFull (the default) a relationship from Component 1 to Component 2
+// implies relationships between all their parents. With
+// ImpliedDirectParentOnly it only implies the relationships from Container 1
+// to Component 2 and from Component 1 to Container 2.
+//
+// ImpliedMode must appear in Design.
+//
+// ImpliedMode takes one argument: ImpliedFull or ImpliedDirectParentOnly.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        ImpliedMode(ImpliedDirectParentOnly)
+//    })
+//
+func ImpliedMode(kind ImpliedModeKind) {
+	if kind != ImpliedFull && kind != ImpliedDirectParentOnly {
+		eval.InvalidArgError("ImpliedFull or ImpliedDirectParentOnly", kind)
+		return
+	}
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.AddImpliedRelationships = true
+	w.Model.ImpliedMode = expr.ImpliedModeKind(kind - 1)
+}
+
+// AppendTechnologyToLabels appends the relationship technology in brackets to
+// the relationship labels rendered by exporters, for example "Reads from
+// [SQL]". The Structurizr workspace is not affected as the Structurizr service
+// renders the technology separately.
+//
+// AppendTechnologyToLabels must appear in Design.
+//
+// AppendTechnologyToLabels takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        AppendTechnologyToLabels()
+//    })
+//
+func AppendTechnologyToLabels() {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.AppendTechnologyToLabels = true
+}
+
+// DefaultTechnology sets the technology of the containers and components that
+// do not define one explicitly. Technologies set explicitly are never
+// overridden.
+//
+// DefaultTechnology must appear in Design.
+//
+// DefaultTechnology takes one argument: the default technology.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        DefaultTechnology("Go")
+//        SoftwareSystem("System", func() {
+//            Container("API")                                // Technology is "Go"
+//            Container("Web App", "Front-end", "TypeScript") // Technology is "TypeScript"
+//        })
+//    })
+//
+func DefaultTechnology(technology string) {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.DefaultTechnology = technology
+}
+
+// DescriptionTemplate sets the template used to compute the description of the
+// relationships marked with Templated that have no description. The
+// "{source}" and "{destination}" placeholders are replaced with the names of
+// the relationship source and destination.
+//
+// DescriptionTemplate must appear in Design.
+//
+// DescriptionTemplate takes one argument: the template.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        DescriptionTemplate("{source} calls {destination}")
+//        SoftwareSystem("Payments")
+//        SoftwareSystem("Shop", func() {
+//            Uses("Payments", "", func() { // Description is "Shop calls Payments"
+//                Templated()
+//            })
+//        })
+//    })
+//
+func DescriptionTemplate(template string) {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.DescriptionTemplate = template
+}
+
+// PrefixIDs prefixes the IDs of elements and relationships with their type:
+// "person-", "sys-", "cont-", "comp-", "node-", "infra-", "inst-" and "rel-".
+// This makes the generated JSON easier to read and to diff.
+//
+// PrefixIDs must appear in Design before any element is defined.
+//
+// PrefixIDs takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        PrefixIDs()
+//        SoftwareSystem("System")
+//    })
+//
+func PrefixIDs() {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(expr.Registry) > 0 {
+		eval.ReportError("PrefixIDs must appear before any element or relationship is defined")
+		return
+	}
+	w.Model.PrefixIDs = true
+}
+
+// PlaceholderPatterns sets the patterns that should not appear in element and
+// relationship descriptions and technologies. Patterns match whole words case
+// insensitively, so "TODO" matches "todo: describe" but not "Mastodon". The
+// default patterns are "TODO" and "FIXME", calling PlaceholderPatterns with no
+// argument disables the check. Matches are reported as warnings, see
+// WarningsAreErrors.
+//
+// PlaceholderPatterns must appear in Design.
+//
+// PlaceholderPatterns accepts any number of patterns.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        PlaceholderPatterns("TODO", "FIXME", "TBD")
+//    })
+//
+func PlaceholderPatterns(patterns ...string) {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.PlaceholderPatterns = append([]string{}, patterns...)
+}
+
+// TechnologyNormalizer sets the function used to normalize technology names
+// before they are compared or aggregated, for example so that "Go" and
+// "golang" are considered the same technology. The default normalizer trims
+// and lowercases technology names. The technologies of elements and
+// relationships are serialized unchanged.
+//
+// TechnologyNormalizer must appear in Design.
+//
+// TechnologyNormalizer takes one argument: the normalizer function.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        TechnologyNormalizer(func(tech string) string {
+//            tech = strings.ToLower(strings.TrimSpace(tech))
+//            if tech == "golang" {
+//                return "go"
+//            }
+//            return tech
+//        })
+//    })
+//
+func TechnologyNormalizer(fn func(string) string) {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.TechnologyNormalizer = fn
+}
+
+// ElementKind identifies a kind of element, see NameConvention.
+type ElementKind int
+
+const (
+	// PersonKind identifies people.
+	PersonKind ElementKind = iota + 1
+	// SoftwareSystemKind identifies software systems.
+	SoftwareSystemKind
+	// ContainerKind identifies containers.
+	ContainerKind
+	// ComponentKind identifies components.
+	ComponentKind
+)
+
+// NameConvention defines a pattern that element names must match. A
+// validation error is reported for each element whose name does not match.
+// The convention applies to the given kinds of elements or to people,
+// software systems, containers and components if no kind is given. Different
+// conventions may be applied to different kinds of elements by using
+// NameConvention multiple times.
+//
+// NameConvention must appear in Design.
+//
+// NameConvention takes a regular expression as first argument followed by
+// any number of element kinds: PersonKind, SoftwareSystemKind, ContainerKind
+// or ComponentKind.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        NameConvention(regexp.MustCompile(`^([A-Z][a-z0-9]*)( [A-Z][a-z0-9]*)*$`), ContainerKind, ComponentKind)
+//    })
+//
+func NameConvention(re *regexp.Regexp, kinds ...ElementKind) {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if re == nil {
+		eval.ReportError("NameConvention: regular expression cannot be nil")
+		return
+	}
+	if len(kinds) == 0 {
+		kinds = []ElementKind{PersonKind, SoftwareSystemKind, ContainerKind, ComponentKind}
+	}
+	for _, k := range kinds {
+		switch k {
+		case PersonKind:
+			w.Model.PersonNameConvention = re
+		case SoftwareSystemKind:
+			w.Model.SystemNameConvention = re
+		case ContainerKind:
+			w.Model.ContainerNameConvention = re
+		case ComponentKind:
+			w.Model.ComponentNameConvention = re
+		default:
+			eval.InvalidArgError("PersonKind, SoftwareSystemKind, ContainerKind or ComponentKind", k)
+		}
+	}
+}
+
+// NormalizeNames causes a warning to be reported for each element whose name
+// has leading or trailing whitespace and for each element whose name only
+// differs from the name of another element in the same scope by case or
+// whitespace, for example "Payment Service" and "Payment  Service". The names
+// are not modified.
+//
+// NormalizeNames must appear in Design.
+//
+// NormalizeNames takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        NormalizeNames()
+//    })
+//
+func NormalizeNames() {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.NormalizeNames = true
+}
+
+// WarnDuplicateUses causes a warning to be reported for each relationship
+// declared more than once in the same file with the same source, destination
+// and description. This helps catch copy-paste mistakes. Identical
+// relationships declared in different files are not reported as element
+// definitions may be merged across files.
+//
+// WarnDuplicateUses must appear in Design.
+//
+// WarnDuplicateUses takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        WarnDuplicateUses()
+//    })
+//
+func WarnDuplicateUses() {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.WarnDuplicateUses = true
+}
+
+// WarnUnstyledRelationshipTags causes a warning to be reported for each tag of
+// a relationship for which no relationship style is defined, as such tags do
+// not change how the relationship is rendered. This complements the detection
+// of styles that match no element or relationship (see expr.Model.UnusedStyles).
+//
+// WarnUnstyledRelationshipTags must appear in Design.
+//
+// WarnUnstyledRelationshipTags takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        WarnUnstyledRelationshipTags()
+//    })
+//
+func WarnUnstyledRelationshipTags() {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.WarnUnstyledRelationshipTags = true
+}
+
+// RequireRelationshipTechnology causes the validation to fail for each
+// relationship between containers or components that does not define a
+// technology. Relationships from or to people and software systems are exempt.
+//
+// RequireRelationshipTechnology must appear in Design.
+//
+// RequireRelationshipTechnology takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        RequireRelationshipTechnology()
+//        SoftwareSystem("System", func() {
+//            Container("Database")
+//            Container("API", func() {
+//                Uses("Database", "Reads from", "SQL") // Technology is required
+//            })
+//        })
+//    })
+//
+func RequireRelationshipTechnology() {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.RequireRelationshipTechnology = true
+}
+
+// WarningsAreErrors causes the warnings reported when validating the design
+// (e.g. descriptions that are too long or relationships to deprecated
+// elements) to be reported as errors so that the validation fails. This makes
+// it possible for continuous integration checks to enforce a stricter hygiene.
+// Warnings do not cause the validation to fail by default.
+//
+// WarningsAreErrors must appear in Design.
+//
+// WarningsAreErrors takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        WarningsAreErrors()
+//    })
+//
+func WarningsAreErrors() {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	w.Model.WarningsAreErrors = true
+}
+
+// DescriptionMaxLength sets the maximum length of element descriptions. A
+// warning is reported for each element whose description is longer. The
+// default maximum length is 256 characters.
+//
+// DescriptionMaxLength must appear in Design.
+//
+// DescriptionMaxLength takes one argument: the maximum length. A value of 0
+// disables the check.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        DescriptionMaxLength(120)
+//    })
+//
+func DescriptionMaxLength(max int) {
+	w, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if max < 0 {
+		eval.InvalidArgError("positive integer", max)
+		return
+	}
+	if max == 0 {
+		w.Model.SkipDescriptionLength = true
+		return
+	}
+	w.Model.DescriptionMaxLength = max
+}
+
 // Tag defines a set of tags on the given element. Tags are used in views to
 // identify group of elements that should be rendered together for example.
 //
@@ -180,6 +661,153 @@ func Tag(first string, t ...string) {
 	}
 }
 
+// Alias defines a short name for the element that can be used in place of its
+// path wherever an element path is accepted, for example in Uses or in views.
+// Aliases must be unique across the model and may not be the name of another
+// element.
+//
+// Alias may appear in Person, SoftwareSystem, Container, Component,
+// DeploymentNode or InfrastructureNode.
+//
+// Alias takes one argument: the alias.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("Payment Processing System", func() {
+//            Container("Payment Gateway API", func() {
+//                Alias("gateway")
+//            })
+//        })
+//        Person("Customer", func() {
+//            Uses("gateway", "Pays with")
+//        })
+//    })
+//
+func Alias(name string) {
+	if strings.Contains(name, "/") {
+		eval.ReportError("Alias: alias cannot contain slashes")
+		return
+	}
+	eh, ok := eval.Current().(expr.ElementHolder)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if _, ok := eh.(*expr.ContainerInstance); ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	eh.GetElement().Alias = name
+	expr.Aliases[name] = eh
+}
+
+// Deprecated marks the element as deprecated. Deprecated elements are tagged
+// with "Deprecated" and rendered grayed out with a dashed border unless the
+// design defines a style for the "Deprecated" tag. A warning is reported for
+// each relationship from an element that is not deprecated to a deprecated
+// element.
+//
+// Deprecated may appear in Person, SoftwareSystem, Container, Component,
+// DeploymentNode, InfrastructureNode or ContainerInstance.
+//
+// Deprecated takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("Legacy System", func() {
+//            Deprecated()
+//        })
+//    })
+//
+func Deprecated() {
+	eh, ok := eval.Current().(expr.ElementHolder)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	eh.GetElement().Deprecate()
+}
+
+// Size sets the size in pixels of the element as a hint for the renderers that
+// compute their own layout: the SVG renderer draws the element box with the
+// given size and the DOT exporter sets the width and height of the node
+// accordingly. Structurizr ignores the hint and computes its own sizes.
+//
+// Size may appear in Person, SoftwareSystem, Container, Component,
+// DeploymentNode, InfrastructureNode or ContainerInstance.
+//
+// Size takes two arguments: the width and the height. Both values must be
+// strictly positive.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("My system", func() {
+//            Size(600, 200)
+//        })
+//    })
+//
+func Size(width, height int) {
+	eh, ok := eval.Current().(expr.ElementHolder)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if width <= 0 || height <= 0 {
+		eval.ReportError("Size: width and height must be strictly positive, got %d and %d", width, height)
+		return
+	}
+	eh.GetElement().Size = &expr.Dimensions{Width: width, Height: height}
+}
+
+// Group adds the element to a group. Groups are rendered as boundaries around
+// their elements by Structurizr. Groups may be nested by separating the names
+// of the groups with slashes, e.g. "Team A/Backend". The name of a group
+// cannot be the name of an element at the same level (e.g. the name of a
+// software system for groups of people and software systems or the name of a
+// container of the same software system for groups of containers).
+//
+// Group may appear in Person, SoftwareSystem, Container or Component.
+//
+// Group takes one argument: the group path.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("My system", func() {
+//            Container("API", func() {
+//                Group("Team A/Backend")
+//            })
+//        })
+//    })
+//
+func Group(path string) {
+	var e *expr.Element
+	switch eh := eval.Current().(type) {
+	case *expr.Person:
+		e = eh.Element
+	case *expr.SoftwareSystem:
+		e = eh.Element
+	case *expr.Container:
+		e = eh.Element
+	case *expr.Component:
+		e = eh.Element
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	segments := strings.Split(path, expr.GroupSeparator)
+	for i, seg := range segments {
+		if segments[i] = strings.TrimSpace(seg); segments[i] == "" {
+			eval.ReportError("Group: invalid group %q, group names cannot be empty", path)
+			return
+		}
+	}
+	e.Group = strings.Join(segments, expr.GroupSeparator)
+}
+
 // URL where more information about this element can be found.
 // Or URL of health check when used within a HealthCheck expression.
 //
@@ -220,7 +848,10 @@ func URL(u string) {
 	}
 }
 
-// External indicates the element is external to the enterprise.
+// External indicates the element is external to the enterprise. External
+// sets the element location to LocationExternal and adds the "External" tag.
+// External is kept for backwards compatibility, new designs should use
+// Location instead.
 //
 // External may appear in Person or SoftwareSystem.
 //
@@ -237,13 +868,88 @@ func External() {
 	switch e := eval.Current().(type) {
 	case *expr.Person:
 		e.Location = ext
+		e.MergeTags(expr.TagExternal)
 	case *expr.SoftwareSystem:
 		e.Location = ext
+		e.MergeTags(expr.TagExternal)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// LocationKind is the enum used to define the location of people and software
+// systems relative to the enterprise.
+type LocationKind int
+
+const (
+	// LocationInternal indicates the element is inside the enterprise.
+	LocationInternal LocationKind = iota + 1
+	// LocationExternal indicates the element is outside the enterprise.
+	LocationExternal
+)
+
+// Location sets the location of the element relative to the enterprise. The
+// location determines on which side of the enterprise boundary the element is
+// rendered. A warning is recorded if an element located internally is also
+// declared External.
+//
+// Location may appear in Person or SoftwareSystem.
+//
+// Location takes one argument: LocationInternal or LocationExternal.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("Partner System", func() {
+//            Location(LocationExternal)
+//        })
+//    })
+//
+func Location(kind LocationKind) {
+	if kind != LocationInternal && kind != LocationExternal {
+		eval.InvalidArgError("LocationInternal or LocationExternal", kind)
+		return
+	}
+	loc := expr.LocationKind(kind)
+	switch e := eval.Current().(type) {
+	case *expr.Person:
+		e.Location = loc
+	case *expr.SoftwareSystem:
+		e.Location = loc
 	default:
 		eval.IncompatibleDSL()
 	}
 }
 
+// InFocus marks the software system as the focus of the model. Software
+// systems in focus are broken down into containers and components by
+// GenerateFocusedViews while all other software systems are rendered as plain
+// boxes.
+//
+// InFocus must appear in SoftwareSystem.
+//
+// InFocus takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("My system", func() {
+//            InFocus()
+//        })
+//        Views(func() {
+//            GenerateFocusedViews()
+//        })
+//    })
+//
+func InFocus() {
+	s, ok := eval.Current().(*expr.SoftwareSystem)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	s.InFocus = true
+}
+
 // Prop defines arbitrary key-value pairs. They are shown in the diagram
 // tooltip and can be used to store metadata (e.g. team name).
 //
@@ -0,0 +1,131 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/model/expr"
+)
+
+func TestPrefixIDs(t *testing.T) {
+	defer func(reg map[string]interface{}, m *expr.Model, vs *expr.Views, errs error) {
+		expr.Registry, expr.Root.Model, expr.Root.Views, eval.Context.Errors = reg, m, vs, errs
+	}(expr.Registry, expr.Root.Model, expr.Root.Views, eval.Context.Errors)
+	expr.Registry = make(map[string]interface{})
+	expr.Root.Model, expr.Root.Views = &expr.Model{}, &expr.Views{}
+	eval.Context.Errors = nil
+
+	eval.Execute(func() {
+		PrefixIDs()
+		sys := SoftwareSystem("System", func() {
+			Container("API", func() {
+				Uses("Database", "Reads from")
+				Component("Handler", func() {
+					Uses("System/Database", "Queries")
+				})
+			})
+			Container("Database")
+		})
+		Person("User", func() {
+			Uses(sys, "Uses")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Server", func() {
+				InfrastructureNode("Gateway")
+				ContainerInstance("System/API")
+				ContainerInstance("System/Database")
+			})
+		})
+	}, expr.Root)
+	expr.Root.WalkSets(func(set eval.ExpressionSet) error {
+		for _, e := range set {
+			if src, ok := e.(eval.Source); ok {
+				eval.Execute(src.DSL(), e)
+			}
+		}
+		return nil
+	})
+	if eval.Context.Errors != nil {
+		t.Fatalf("unexpected error: %s", eval.Context.Errors)
+	}
+	m := expr.Root.Model
+	if err := m.Validate(); len(err.(*eval.ValidationErrors).Errors) != 0 {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	m.Finalize()
+
+	prefixes := map[string]int{"person-": 0, "sys-": 0, "cont-": 0, "comp-": 0, "node-": 0, "infra-": 0, "inst-": 0, "rel-": 0}
+	var linked int
+	expr.Iterate(func(e interface{}) {
+		var id string
+		switch x := e.(type) {
+		case *expr.Person:
+			id = checkPrefix(t, x.ID, "person-")
+		case *expr.SoftwareSystem:
+			id = checkPrefix(t, x.ID, "sys-")
+		case *expr.Container:
+			id = checkPrefix(t, x.ID, "cont-")
+		case *expr.Component:
+			id = checkPrefix(t, x.ID, "comp-")
+		case *expr.DeploymentNode:
+			id = checkPrefix(t, x.ID, "node-")
+		case *expr.InfrastructureNode:
+			id = checkPrefix(t, x.ID, "infra-")
+		case *expr.ContainerInstance:
+			id = checkPrefix(t, x.ID, "inst-")
+			checkRef(t, x.ContainerID, "container")
+		case *expr.Relationship:
+			id = checkPrefix(t, x.ID, "rel-")
+			checkRef(t, x.Destination.ID, "destination")
+			if x.LinkedRelationshipID != "" {
+				linked++
+				checkRef(t, x.LinkedRelationshipID, "linked relationship")
+			}
+		}
+		if id != "" {
+			prefixes[id]++
+		}
+	})
+	for prefix, n := range prefixes {
+		if n == 0 {
+			t.Errorf("got no ID with prefix %q", prefix)
+		}
+	}
+	if linked == 0 {
+		t.Error("got no relationship linked to a container relationship")
+	}
+
+	t.Run("after-element", func(t *testing.T) {
+		eval.Context.Errors = nil
+		expr.Root.Model.PrefixIDs = false
+
+		eval.Execute(PrefixIDs, expr.Root)
+
+		if eval.Context.Errors == nil || !strings.Contains(eval.Context.Errors.Error(), "PrefixIDs must appear before") {
+			t.Errorf("got error %v, want PrefixIDs error", eval.Context.Errors)
+		}
+		if expr.Root.Model.PrefixIDs {
+			t.Error("got IDs prefixed after elements were defined")
+		}
+	})
+}
+
+// checkPrefix reports an error if id does not start with prefix and returns
+// prefix.
+func checkPrefix(t *testing.T, id, prefix string) string {
+	t.Helper()
+	if !strings.HasPrefix(id, prefix) {
+		t.Errorf("got ID %q, want prefix %q", id, prefix)
+	}
+	return prefix
+}
+
+// checkRef reports an error if id is not the ID of a registered element or
+// relationship.
+func checkRef(t *testing.T, id, ref string) {
+	t.Helper()
+	if _, ok := expr.Registry[id]; !ok {
+		t.Errorf("got %s ID %q, want the ID of a registered element", ref, id)
+	}
+}
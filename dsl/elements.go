@@ -57,6 +57,7 @@ func SoftwareSystem(name string, args ...interface{}) *expr.SoftwareSystem {
 	s := &expr.SoftwareSystem{
 		Element: &expr.Element{
 			DSLFunc:     dsl,
+			DSLLocation: callerLocation(),
 			Name:        name,
 			Description: description,
 		},
@@ -117,7 +118,7 @@ func SoftwareSystem(name string, args ...interface{}) *expr.SoftwareSystem {
 func Container(args ...interface{}) *expr.Container {
 	system, ok := eval.Current().(*expr.SoftwareSystem)
 	if !ok {
-		eval.IncompatibleDSL()
+		eval.ReportError("Container must be defined within a SoftwareSystem")
 		return nil
 	}
 	if len(args) == 0 {
@@ -164,6 +165,7 @@ func Container(args ...interface{}) *expr.Container {
 	c := &expr.Container{
 		Element: &expr.Element{
 			DSLFunc:     dsl,
+			DSLLocation: callerLocation(),
 			Name:        name,
 			Description: description,
 			Technology:  technology,
@@ -215,7 +217,7 @@ func Container(args ...interface{}) *expr.Container {
 func Component(name string, args ...interface{}) *expr.Component {
 	container, ok := eval.Current().(*expr.Container)
 	if !ok {
-		eval.IncompatibleDSL()
+		eval.ReportError("Component must be defined within a Container")
 		return nil
 	}
 	if strings.Contains(name, "/") {
@@ -232,12 +234,164 @@ func Component(name string, args ...interface{}) *expr.Component {
 			Description: description,
 			Technology:  technology,
 			DSLFunc:     dsl,
+			DSLLocation: callerLocation(),
 		},
 		Container: container,
 	}
 	return container.AddComponent(c)
 }
 
+// Responsibilities lists the responsibilities of a component. The
+// responsibilities are serialized as the "Responsibilities" property (one per
+// line) and appended to the description by exporters that do not support
+// lists.
+//
+// Responsibilities must appear in Component.
+//
+// Responsibilities accepts one or more non-empty strings. Responsibilities may
+// appear multiple times in which case the responsibilities accumulate.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("My system", func() {
+//            Container("My container", func() {
+//                Component("My component", func() {
+//                    Responsibilities("Validates requests", "Persists orders")
+//                })
+//            })
+//        })
+//    })
+//
+func Responsibilities(items ...string) {
+	c, ok := eval.Current().(*expr.Component)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item) == "" {
+			eval.ReportError("Responsibilities: responsibilities cannot be empty")
+			return
+		}
+	}
+	c.Responsibilities = append(c.Responsibilities, items...)
+}
+
+// Responsibility adds a single responsibility to a component. It is
+// equivalent to Responsibilities called with one argument and reads better
+// when responsibilities are listed one per line.
+//
+// Responsibility must appear in Component.
+//
+// Responsibility accepts a non-empty string. Responsibility may appear
+// multiple times in which case the responsibilities accumulate in order.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("My system", func() {
+//            Container("My container", func() {
+//                Component("My component", func() {
+//                    Responsibility("Validates requests")
+//                    Responsibility("Persists orders")
+//                })
+//            })
+//        })
+//    })
+//
+func Responsibility(item string) {
+	c, ok := eval.Current().(*expr.Component)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if strings.TrimSpace(item) == "" {
+		eval.ReportError("Responsibility: responsibility cannot be empty")
+		return
+	}
+	c.Responsibilities = append(c.Responsibilities, item)
+}
+
+// Code links a component to a code element (e.g. a class) that implements
+// it. Code elements are serialized in the "code" array of the Structurizr
+// component.
+//
+// Code must appear in Component.
+//
+// Code takes three arguments: the name of the code element (e.g. the fully
+// qualified class name), its type which must be one of the types listed in
+// expr.CodeElementTypes and the URL to its source code which may be empty.
+// Code may appear multiple times.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("My system", func() {
+//            Container("My container", func() {
+//                Component("My component", func() {
+//                    Code("com.example.OrderService", "Class", "https://github.com/example/shop/OrderService.java")
+//                })
+//            })
+//        })
+//    })
+//
+func Code(name, typ, url string) {
+	c, ok := eval.Current().(*expr.Component)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if strings.TrimSpace(name) == "" {
+		eval.ReportError("Code: name cannot be empty")
+		return
+	}
+	for _, t := range expr.CodeElementTypes {
+		if t == typ {
+			c.CodeElements = append(c.CodeElements, &expr.CodeElement{Name: name, Type: typ, URL: url})
+			return
+		}
+	}
+	eval.ReportError("Code: invalid code element type %q, supported types are %s", typ, strings.Join(expr.CodeElementTypes, ", "))
+}
+
+// Interface defines an interface exposed by a component, for example an API or
+// a message queue. Relationships whose destination is the component may
+// target the interface with ToInterface. Interfaces are serialized in the
+// "Interfaces" property of the Structurizr component.
+//
+// Interface must appear in Component.
+//
+// Interface takes two arguments: the name of the interface which must be
+// unique within the component and the technology used to implement it which
+// may be empty. Interface may appear multiple times.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("My system", func() {
+//            Container("My container", func() {
+//                Component("My component", func() {
+//                    Interface("Orders API", "REST")
+//                    Interface("Order Events", "Kafka")
+//                })
+//            })
+//        })
+//    })
+//
+func Interface(name, tech string) {
+	c, ok := eval.Current().(*expr.Component)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if strings.TrimSpace(name) == "" {
+		eval.ReportError("Interface: name cannot be empty")
+		return
+	}
+	c.Interfaces = append(c.Interfaces, &expr.Interface{Name: name, Technology: tech})
+}
+
 // parseElement is a helper function that parses the given element DSL
 // arguments. Accepted syntax are:
 //
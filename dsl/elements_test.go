@@ -0,0 +1,51 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/model/expr"
+)
+
+func TestMisplacedElements(t *testing.T) {
+	var (
+		sys  = &expr.SoftwareSystem{Element: &expr.Element{Name: "Misplaced System"}}
+		env  = &expr.DeploymentEnvironment{Name: "Misplaced Environment"}
+		errs = eval.Context.Errors
+	)
+	defer func() { eval.Context.Errors = errs }()
+
+	tests := []struct {
+		name  string
+		scope eval.Expression
+		dsl   func() bool
+		want  string
+	}{
+		{"container-in-design", expr.Root, func() bool { return Container("API") == nil }, "Container must be defined within a SoftwareSystem"},
+		{"component-in-system", sys, func() bool { return Component("Handler") == nil }, "Component must be defined within a Container"},
+		{"container-instance-in-environment", env, func() bool { return ContainerInstance("Misplaced System/API") == nil }, "ContainerInstance must be defined within a DeploymentNode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval.Context.Errors = nil
+			var isNil bool
+
+			eval.Execute(func() { isNil = tt.dsl() }, tt.scope)
+
+			if eval.Context.Errors == nil {
+				t.Fatalf("got no error, want %q", tt.want)
+			}
+			merr := eval.Context.Errors.(eval.MultiError)
+			if len(merr) != 1 {
+				t.Errorf("got %d errors, want 1: %v", len(merr), merr)
+			}
+			if !strings.Contains(merr.Error(), tt.want) {
+				t.Errorf("got error %q, want it to contain %q", merr.Error(), tt.want)
+			}
+			if !isNil {
+				t.Error("got an expression, want nil")
+			}
+		})
+	}
+}
@@ -78,6 +78,7 @@ func Person(name string, args ...interface{}) *expr.Person {
 			Name:        name,
 			Description: desc,
 			DSLFunc:     dsl,
+			DSLLocation: callerLocation(),
 		},
 	}
 	return w.Model.AddPerson(p)
@@ -2,6 +2,9 @@ package dsl
 
 import (
 	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
 
 	"goa.design/goa/v3/eval"
 	"goa.design/model/expr"
@@ -19,14 +22,18 @@ const (
 
 // Uses adds a uni-directional relationship between two elements.
 //
-// Uses may appear in Person, SoftwareSystem, Container or Component.
+// Uses may appear in Person, SoftwareSystem, Container, Component or
+// ContainerInstance.
 //
-// Uses takes 2 to 5 arguments. The first argument identifies the target of the
-// relationship. The following argument is a short description for the
+// Uses takes 2 or more arguments. The first argument identifies the target of
+// the relationship. The following argument is a short description for the
 // relationship. The description may optionally be followed by the technology
-// used by the relationship and/or the type of relationship: Synchronous or
-// Asynchronous. Finally Uses accepts an optional func() as last argument to
-// define additional properties on the relationship.
+// used by the relationship, the technology may be followed by tags which are
+// merged with the default "Relationship" tag so that simple relationships can
+// be tagged without a DSL function. The tags may be followed by the type of
+// relationship: Synchronous or Asynchronous. Finally Uses accepts an optional
+// func() as last argument to define additional properties on the
+// relationship.
 //
 // The target of the relationship is identified by providing an element (person,
 // software system, container or component) or the path of an element. The path
@@ -57,6 +64,8 @@ const (
 //
 //    Uses(Element, "<description>", "[technology]", Synchronous|Asynchronous, func())
 //
+//    Uses(Element, "<description>", "<technology>", "<tag>", "[tag]"..., [Synchronous|Asynchronous], [func()])
+//
 // Where Element is one of:
 //
 //    - Person, SoftwareSystem, Container or Component
@@ -65,6 +74,14 @@ const (
 //    - "<Component>" (if component is a sibling of the source)
 //    - "<Container>/<Component>" (if container is a sibling of the source)
 //
+// When Uses appears in a ContainerInstance the target must be another
+// ContainerInstance of the same deployment environment. Such relationships
+// are kept in addition to the relationships replicated from the containers.
+//
+// When Uses appears in a DeploymentNode or an InfrastructureNode the target
+// must be a DeploymentNode or an InfrastructureNode of the same deployment
+// environment unless the relationship is marked with CrossEnvironment.
+//
 // Example:
 //
 //     var _ = Design("my workspace", "a great architecture model", func() {
@@ -79,10 +96,19 @@ const (
 //         })
 //         Person("Customer", "Customers of enterprise", func () {
 //            Uses(SystemA, "Access", "HTTP", Synchronous)
+//            Uses("SystemB", "Reports issues", "HTTP", "Support", "Ticketing")
 //         })
 //         Person("Staff", "Back office staff", func() {
 //            InteractsWith("Customer", "Sends invoices to", Synchronous)
 //         })
+//         DeploymentEnvironment("Production", func() {
+//             DeploymentNode("Cluster", func() {
+//                 var Primary = ContainerInstance("SystemA/ContainerA")
+//                 ContainerInstance("SystemA/ContainerB", func() {
+//                     Uses(Primary, "Reads from", "TCP")
+//                 })
+//             })
+//         })
 //     })
 //
 func Uses(element interface{}, description string, args ...interface{}) {
@@ -96,6 +122,24 @@ func Uses(element interface{}, description string, args ...interface{}) {
 		src = e.Element
 	case *expr.Component:
 		src = e.Element
+	case *expr.ContainerInstance:
+		if _, ok := element.(*expr.ContainerInstance); !ok {
+			eval.InvalidArgError("container instance", element)
+			return
+		}
+		src = e.Element
+	case *expr.DeploymentNode:
+		if !isNode(element) {
+			eval.InvalidArgError("deployment node or infrastructure node", element)
+			return
+		}
+		src = e.Element
+	case *expr.InfrastructureNode:
+		if !isNode(element) {
+			eval.InvalidArgError("deployment node or infrastructure node", element)
+			return
+		}
+		src = e.Element
 	default:
 		eval.IncompatibleDSL()
 		return
@@ -257,47 +301,395 @@ func Description(desc string) {
 	v.Description = desc
 }
 
+// NoImply excludes the relationship from the generation of implied
+// relationships: no relationship is added between the parents of the source and
+// destination elements even if AddImpliedRelationships is used. NoImply is
+// useful to keep low-level relationships from bubbling up to the software
+// system level.
+//
+// NoImply must appear in Uses, Delivers or InteractsWith.
+//
+// NoImply takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        AddImpliedRelationships()
+//        SoftwareSystem("System", func() {
+//            Container("Cache")
+//            Container("API", func() {
+//                Uses("Cache", "Reads from", func() {
+//                    NoImply()
+//                })
+//            })
+//        })
+//    })
+//
+func NoImply() {
+	r, ok := eval.Current().(*expr.Relationship)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	r.NoImply = true
+}
+
+// Templated causes the description of the relationship to be computed from the
+// template set with DescriptionTemplate. The relationship description must be
+// empty.
+//
+// Templated must appear in Uses, Delivers or InteractsWith.
+//
+// Templated takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        DescriptionTemplate("{source} calls {destination}")
+//        SoftwareSystem("System", func() {
+//            Container("Cache")
+//            Container("API", func() {
+//                Uses("Cache", "", func() {
+//                    Templated()
+//                })
+//            })
+//        })
+//    })
+//
+func Templated() {
+	r, ok := eval.Current().(*expr.Relationship)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if r.Description != "" {
+		eval.ReportError("templated relationships must not define a description, got %q", r.Description)
+		return
+	}
+	r.Templated = true
+}
+
+// CrossEnvironment allows a relationship between deployment nodes or
+// infrastructure nodes to cross deployment environments, for example to
+// describe a replication link between the production and the disaster recovery
+// environments.
+//
+// CrossEnvironment must appear in Uses.
+//
+// CrossEnvironment takes no argument.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        var DR *expr.DeploymentNode
+//        DeploymentEnvironment("DR", func() {
+//            DR = DeploymentNode("Region")
+//        })
+//        DeploymentEnvironment("Production", func() {
+//            DeploymentNode("Region", func() {
+//                Uses(DR, "Replicates to", func() {
+//                    CrossEnvironment()
+//                })
+//            })
+//        })
+//    })
+//
+func CrossEnvironment() {
+	r, ok := eval.Current().(*expr.Relationship)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	r.CrossEnvironment = true
+}
+
+// Order sets the position of a relationship relative to the other
+// relationships between the same elements. Views list relationships with an
+// explicit order first followed by the others sorted by description. When
+// used in a relationship view of a dynamic view Order sets the order of the
+// interaction in the view.
+//
+// Order may appear in Uses, Delivers, InteractsWith or Link.
+//
+// Order takes one argument: the position starting at 1.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        var System = SoftwareSystem("System")
+//        Person("User", func() {
+//            Uses(System, "Reads data from", func() {
+//                Order(1)
+//            })
+//            Uses(System, "Writes data to", func() {
+//                Order(2)
+//            })
+//        })
+//    })
+//
+func Order(n int) {
+	if n < 1 {
+		eval.InvalidArgError("positive integer", n)
+		return
+	}
+	switch a := eval.Current().(type) {
+	case *expr.Relationship:
+		a.Order = n
+	case *expr.RelationshipView:
+		a.Order = strconv.Itoa(n)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Weight sets the weight of a relationship, for example to express the volume
+// of calls it carries. Exporters may use the weight to render the relationship
+// with a thicker line and Model.Metrics computes weighted fan-in and fan-out
+// from it. The weight is serialized in the "weight" property of Structurizr
+// relationships. Relationships that do not define a weight have a weight of 1.
+//
+// Weight may appear in Uses, Delivers or InteractsWith.
+//
+// Weight takes one argument: the weight which cannot be negative.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        var System = SoftwareSystem("System")
+//        Person("User", func() {
+//            Uses(System, "Reads data from", func() {
+//                Weight(10)
+//            })
+//        })
+//    })
+//
+func Weight(n int) {
+	if n < 0 {
+		eval.InvalidArgError("non-negative integer", n)
+		return
+	}
+	r, ok := eval.Current().(*expr.Relationship)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	r.Weight = n
+}
+
+// ToInterface makes the relationship target an interface of its destination
+// component defined with Interface. The name of the interface is serialized
+// in the "interface" property of the Structurizr relationship.
+//
+// ToInterface may appear in Uses.
+//
+// ToInterface takes one argument: the name of the interface.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("My system", func() {
+//            Container("My container", func() {
+//                Component("Orders", func() {
+//                    Interface("Orders API", "REST")
+//                })
+//                Component("Web", func() {
+//                    Uses("Orders", "Places orders using", func() {
+//                        ToInterface("Orders API")
+//                    })
+//                })
+//            })
+//        })
+//    })
+//
+func ToInterface(name string) {
+	r, ok := eval.Current().(*expr.Relationship)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if strings.TrimSpace(name) == "" {
+		eval.ReportError("ToInterface: name cannot be empty")
+		return
+	}
+	r.Interface = name
+}
+
+// ExternalRef references an element defined in another workspace by URN. It
+// returns a placeholder software system that can be used as the destination of
+// Uses without requiring the element to be defined in the design. The
+// placeholder is named after the URN, is external, is tagged with "External
+// Reference" and records the URN in its "urn" property so that exporters render
+// it as an external stub. Referencing the same URN multiple times returns the
+// same placeholder.
+//
+// ExternalRef may appear wherever Uses may appear.
+//
+// ExternalRef takes one argument: the URN of the referenced element which
+// must be of the form "urn:<namespace>:<name>".
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("Billing", func() {
+//            Uses(ExternalRef("urn:acme:payments:gateway"), "Charges cards using")
+//        })
+//    })
+//
+func ExternalRef(urn string) *expr.SoftwareSystem {
+	s, err := expr.Root.Model.ExternalRef(urn)
+	if err != nil {
+		eval.ReportError("ExternalRef: %s", err)
+		return nil
+	}
+	return s
+}
+
+// Flow groups the relationships declared in the given function into a logical
+// flow, for example an end-to-end scenario that spans multiple views. Flow tags
+// each relationship declared in the function with "flow:<name>" (see
+// expr.FlowTagPrefix). The relationships of a flow can be retrieved with the
+// Model Flow method, for example to generate a dynamic view per flow.
+//
+// Flow may appear in Design or in an expression that may declare
+// relationships: Person, SoftwareSystem, Container, Component,
+// ContainerInstance, DeploymentNode or InfrastructureNode. When used in Design
+// Flow tags the relationships declared by the people, software systems and
+// deployment nodes defined in the function as well as the relationships
+// declared by the elements they contain. Flow may be used multiple times with
+// the same name to build a flow that spans multiple elements.
+//
+// Flow takes two arguments: the name of the flow and the function declaring
+// the relationships or elements.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        var Shop = SoftwareSystem("Shop")
+//        var Payments = SoftwareSystem("Payments")
+//        Flow("checkout", func() {
+//            Person("Customer", func() {
+//                Uses(Shop, "Places order")
+//            })
+//        })
+//        SoftwareSystem("Orders", func() {
+//            Flow("checkout", func() {
+//                Uses(Payments, "Charges customer")
+//            })
+//        })
+//    })
+//
+func Flow(name string, dsl func()) {
+	if name == "" {
+		eval.ReportError("Flow: name cannot be empty")
+		return
+	}
+	switch current := eval.Current().(type) {
+	case *expr.Design:
+		m := current.Model
+		np, ns, nd := len(m.People), len(m.Systems), len(m.DeploymentNodes)
+		eval.Execute(dsl, current)
+		for _, p := range m.People[np:] {
+			flowDSL(name, p)
+		}
+		for _, s := range m.Systems[ns:] {
+			flowDSL(name, s)
+		}
+		for _, n := range m.DeploymentNodes[nd:] {
+			flowDSL(name, n)
+		}
+	case expr.ElementHolder:
+		flows = append(flows, name)
+		eval.Execute(dsl, eval.Current())
+		flows = flows[:len(flows)-1]
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// flows lists the names of the flows enclosing the DSL being executed, the
+// relationships created by uses are tagged with each flow.
+var flows []string
+
+// flowDSL wraps the DSL of the given element so that the relationships it
+// declares, and the relationships declared by the elements it contains, belong
+// to the flow with the given name.
+func flowDSL(name string, eh expr.ElementHolder) {
+	elem := eh.GetElement()
+	dsl := elem.DSLFunc
+	elem.DSLFunc = func() {
+		flows = append(flows, name)
+		if dsl != nil {
+			dsl()
+		}
+		flows = flows[:len(flows)-1]
+		var children []expr.ElementHolder
+		switch e := eh.(type) {
+		case *expr.SoftwareSystem:
+			for _, c := range e.Containers {
+				children = append(children, c)
+			}
+		case *expr.Container:
+			for _, c := range e.Components {
+				children = append(children, c)
+			}
+		case *expr.DeploymentNode:
+			for _, c := range e.Children {
+				children = append(children, c)
+			}
+			for _, i := range e.InfrastructureNodes {
+				children = append(children, i)
+			}
+			for _, ci := range e.ContainerInstances {
+				children = append(children, ci)
+			}
+		}
+		for _, c := range children {
+			flowDSL(name, c)
+		}
+	}
+}
+
 // uses adds a relationship between the given source and destination. The caller
 // must make sure that the relationship is valid.
 func uses(src *expr.Element, dest interface{}, desc string, args ...interface{}) error {
 	var (
 		technology string
+		tags       []string
 		style      InteractionStyleKind
 		dsl        func()
 	)
-	if len(args) > 0 {
-		switch a := args[0].(type) {
+	for i, arg := range args {
+		if dsl != nil {
+			return fmt.Errorf("function DSL must be last argument")
+		}
+		switch a := arg.(type) {
 		case string:
-			technology = a
+			if style != 0 {
+				return fmt.Errorf("technology and tags must appear before Synchronous or Asynchronous")
+			}
+			if i == 0 {
+				technology = a
+				continue
+			}
+			if strings.TrimSpace(a) == "" {
+				return fmt.Errorf("tags cannot be empty")
+			}
+			if strings.Contains(a, ",") {
+				return fmt.Errorf("tag %q cannot contain commas, use one argument per tag", a)
+			}
+			tags = append(tags, a)
 		case InteractionStyleKind:
+			if style != 0 {
+				return fmt.Errorf("too many arguments")
+			}
 			style = a
 		case func():
 			dsl = a
 		default:
-			return fmt.Errorf("expected description, Synchronous or Asynchronous, got %T", args[0])
-		}
-		if len(args) > 1 {
-			if dsl != nil {
-				return fmt.Errorf("function DSL must be last argument")
-			}
-			switch a := args[1].(type) {
-			case InteractionStyleKind:
-				style = a
-			case func():
-				dsl = a
-			default:
-				return fmt.Errorf("expected Synchronous or Asynchronous, got %T", args[1])
-			}
-			if len(args) > 2 {
-				if d, ok := args[2].(func()); ok {
-					dsl = d
-				} else {
-					return fmt.Errorf("expected DSL function, got %T", args[2])
-				}
-				if len(args) > 3 {
-					return fmt.Errorf("too many arguments")
-				}
+			if i == 0 {
+				return fmt.Errorf("expected description, Synchronous or Asynchronous, got %T", arg)
 			}
+			return fmt.Errorf("expected tag, Synchronous or Asynchronous, got %T", arg)
 		}
 	}
 	rel := &expr.Relationship{
@@ -306,6 +698,7 @@ func uses(src *expr.Element, dest interface{}, desc string, args ...interface{})
 		Technology:       technology,
 		InteractionStyle: expr.InteractionStyleKind(style),
 	}
+	rel.MergeTags(tags...)
 	// Note: we need to check the types explicitly below because
 	// (*expr.Person)(nil) != (expr.ElementHolder)(nil) for example.
 	switch d := dest.(type) {
@@ -329,6 +722,21 @@ func uses(src *expr.Element, dest interface{}, desc string, args ...interface{})
 			return fmt.Errorf("Component reference is nil")
 		}
 		rel.Destination = d.Element
+	case *expr.ContainerInstance:
+		if d == nil {
+			return fmt.Errorf("ContainerInstance reference is nil")
+		}
+		rel.Destination = d.Element
+	case *expr.DeploymentNode:
+		if d == nil {
+			return fmt.Errorf("DeploymentNode reference is nil")
+		}
+		rel.Destination = d.Element
+	case *expr.InfrastructureNode:
+		if d == nil {
+			return fmt.Errorf("InfrastructureNode reference is nil")
+		}
+		rel.Destination = d.Element
 	case string:
 		rel.DestinationPath = d
 	default:
@@ -337,8 +745,41 @@ func uses(src *expr.Element, dest interface{}, desc string, args ...interface{})
 	if dsl != nil {
 		eval.Execute(dsl, rel)
 	}
+	for _, f := range flows {
+		rel.MergeTags(expr.FlowTagPrefix + f)
+	}
+	rel.DSLLocation = callerLocation()
+	rel.RecordDeclaration()
 	expr.Identify(rel)
 	src.Relationships = append(src.Relationships, rel)
 
 	return nil
 }
+
+// isNode returns true if element is a deployment node or an infrastructure
+// node.
+func isNode(element interface{}) bool {
+	switch element.(type) {
+	case *expr.DeploymentNode, *expr.InfrastructureNode:
+		return true
+	}
+	return false
+}
+
+// callerLocation returns the file:line of the first caller that is not part of
+// the DSL or eval packages, i.e. the location of the user DSL.
+func callerLocation() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "goa.design/model/dsl.") &&
+			!strings.HasPrefix(f.Function, "goa.design/goa/v3/eval.") {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package dsl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/model/expr"
+	"goa.design/model/stz"
+)
+
+func TestUsesInlineTags(t *testing.T) {
+	var (
+		user = &expr.Person{Element: &expr.Element{Name: "Inline Tags User"}}
+		sys  = &expr.SoftwareSystem{Element: &expr.Element{Name: "Inline Tags System"}}
+	)
+	for _, e := range []interface{}{user, sys} {
+		expr.Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, sys.ID} {
+			delete(expr.Registry, id)
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		args    []interface{}
+		want    string
+		wantErr string
+	}{
+		{"tags", []interface{}{"HTTP", "Support", "Ticketing"}, `"tags":"Support,Ticketing,Relationship"`, ""},
+		{"tags-and-style", []interface{}{"HTTP", "Support", Asynchronous}, `"tags":"Support,Relationship,Asynchronous"`, ""},
+		{"empty-tag", []interface{}{"HTTP", " "}, "", "tags cannot be empty"},
+		{"comma-tag", []interface{}{"HTTP", "Support,Ticketing"}, "", `tag "Support,Ticketing" cannot contain commas`},
+		{"tag-after-style", []interface{}{"HTTP", Synchronous, "Support"}, "", "technology and tags must appear before"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user.Relationships = nil
+
+			err := uses(user.Element, sys, "Reports issues", tt.args...)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want it to contain %q", err, tt.wantErr)
+				}
+				if len(user.Relationships) != 0 {
+					t.Errorf("got %d relationships, want none", len(user.Relationships))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			rel := user.Relationships[0]
+			defer delete(expr.Registry, rel.ID)
+			rel.Finalize()
+			d := &expr.Design{
+				Model: &expr.Model{People: expr.People{user}, Systems: expr.SoftwareSystems{sys}},
+				Views: &expr.Views{Styles: &expr.Styles{}},
+			}
+
+			js, err := json.Marshal(stz.WorkspaceFromDesign(d).Model.People[0].Relationships)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(js), tt.want) {
+				t.Errorf("got %s, want it to contain %s", js, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlow(t *testing.T) {
+	ids := make(map[string]bool, len(expr.Registry))
+	for id := range expr.Registry {
+		ids[id] = true
+	}
+	defer func(m *expr.Model) {
+		expr.Root.Model = m
+		for id := range expr.Registry {
+			if !ids[id] {
+				delete(expr.Registry, id)
+			}
+		}
+	}(expr.Root.Model)
+	defer func(errs error) { eval.Context.Errors = errs }(eval.Context.Errors)
+	expr.Root.Model = &expr.Model{}
+	eval.Context.Errors = nil
+
+	eval.Execute(func() {
+		shop := SoftwareSystem("Flow Shop")
+		Flow("checkout", func() {
+			Person("Flow Customer", func() {
+				Uses(shop, "Places order")
+			})
+			SoftwareSystem("Flow Orders", func() {
+				Container("Flow API", func() {
+					Uses(shop, "Reads catalog")
+				})
+			})
+		})
+		Person("Flow Admin", func() {
+			Uses(shop, "Manages")
+			Flow("restock", func() {
+				Uses(shop, "Restocks")
+			})
+		})
+	}, expr.Root)
+	m := expr.Root.Model
+	for _, p := range m.People {
+		eval.Execute(p.DSLFunc, p)
+	}
+	for _, s := range m.Systems {
+		eval.Execute(s.DSLFunc, s)
+	}
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			eval.Execute(c.DSLFunc, c)
+		}
+	}
+	if eval.Context.Errors != nil {
+		t.Fatalf("unexpected error: %s", eval.Context.Errors)
+	}
+
+	tests := []struct {
+		flow string
+		want []string
+	}{
+		{"checkout", []string{"Places order", "Reads catalog"}},
+		{"restock", []string{"Restocks"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flow, func(t *testing.T) {
+			var got []string
+			for _, r := range m.Flow(tt.flow) {
+				got = append(got, r.Description)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("got relationships %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package dsl
 
 import (
+	"fmt"
 	"regexp"
 
 	"goa.design/goa/v3/eval"
@@ -13,6 +14,9 @@ type (
 
 	// BorderKind is the enum used to represent element border styles.
 	BorderKind int
+
+	// ExcludedTags lists tags excluded from a style match, see Not.
+	ExcludedTags []string
 )
 
 const (
@@ -132,6 +136,46 @@ func ElementStyle(tag string, dsl func()) {
 	cfg.Elements = append(cfg.Elements, es)
 }
 
+// ElementStyleFor defines element styles that apply to the elements that have
+// all the given tags and none of the tags given via Not. ElementStyleFor styles
+// take precedence over styles defined with ElementStyle.
+//
+// ElementStyleFor must appear in Styles.
+//
+// ElementStyleFor accepts two or three arguments: the list of tags the
+// elements must have, an optional list of tags the elements must not have
+// (using Not) and a function describing the style properties.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         // ...
+//         Views(func() {
+//             // ...
+//             Styles(func() {
+//                 ElementStyleFor([]string{"Container"}, Not("Database"), func() {
+//                     Shape(ShapeRoundedBox)
+//                 })
+//             })
+//         })
+//     })
+//
+func ElementStyleFor(tags []string, args ...interface{}) {
+	cfg, ok := eval.Current().(*expr.Styles)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	excluded, dsl, err := parseStyleForArgs(tags, args)
+	if err != nil {
+		eval.ReportError("ElementStyleFor: " + err.Error())
+		return
+	}
+	es := &expr.ElementStyle{RequiredTags: tags, ExcludedTags: excluded}
+	eval.Execute(dsl, es)
+	cfg.Elements = append(cfg.Elements, es)
+}
+
 // StructurizrElementStyle defines additional element styles used for views
 // rendered in the Structurizr service. Shape accepts additional values when
 // used in StructurizrElementStyle.
@@ -208,6 +252,56 @@ func RelationshipStyle(tag string, dsl func()) {
 	cfg.Relationships = append(cfg.Relationships, rs)
 }
 
+// RelationshipStyleFor defines relationship styles that apply to the
+// relationships that have all the given tags and none of the tags given via
+// Not.
+//
+// RelationshipStyleFor must appear in Styles.
+//
+// RelationshipStyleFor accepts two or three arguments: the list of tags the
+// relationships must have, an optional list of tags the relationships must not
+// have (using Not) and a function describing the style properties.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         // ...
+//         Views(func() {
+//             // ...
+//             Styles(func() {
+//                 RelationshipStyleFor([]string{"Relationship"}, Not("Asynchronous"), func() {
+//                     Solid()
+//                 })
+//             })
+//         })
+//     })
+//
+func RelationshipStyleFor(tags []string, args ...interface{}) {
+	cfg, ok := eval.Current().(*expr.Styles)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	excluded, dsl, err := parseStyleForArgs(tags, args)
+	if err != nil {
+		eval.ReportError("RelationshipStyleFor: " + err.Error())
+		return
+	}
+	rs := &expr.RelationshipStyle{RequiredTags: tags, ExcludedTags: excluded}
+	eval.Execute(dsl, rs)
+	cfg.Relationships = append(cfg.Relationships, rs)
+}
+
+// Not lists tags that elements or relationships must not have for a style to
+// apply to them.
+//
+// Not must be used as argument to ElementStyleFor or RelationshipStyleFor.
+//
+// Not accepts one or more tags.
+func Not(tag string, tags ...string) ExcludedTags {
+	return ExcludedTags(append([]string{tag}, tags...))
+}
+
 // StructurizrRelationshipStyle defines additional relationship styles that
 // apply to views rendered in the Structurizr service.
 //
@@ -495,3 +589,51 @@ func Solid() {
 	}
 	eval.IncompatibleDSL()
 }
+
+// Dashed makes relationship lines dashed. This is the default for views
+// rendered in the Structurizr service, Dashed makes it possible to override a
+// more generic style.
+//
+// Dashed must appear in RelationshipStyle.
+//
+// Dashed takes no argument.
+func Dashed() {
+	if rs, ok := eval.Current().(*expr.RelationshipStyle); ok {
+		t := true
+		rs.Dashed = &t
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// parseStyleForArgs parses the arguments of ElementStyleFor and
+// RelationshipStyleFor.
+func parseStyleForArgs(tags []string, args []interface{}) (excluded []string, dsl func(), err error) {
+	if len(tags) == 0 {
+		err = fmt.Errorf("at least one tag is required")
+		return
+	}
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case ExcludedTags:
+			if i != 0 {
+				err = fmt.Errorf("excluded tags must be the second argument")
+				return
+			}
+			excluded = a
+		case func():
+			if i != len(args)-1 {
+				err = fmt.Errorf("DSL function must be last argument")
+				return
+			}
+			dsl = a
+		default:
+			err = fmt.Errorf("expected Not(...) or function, got %T", arg)
+			return
+		}
+	}
+	if dsl == nil {
+		err = fmt.Errorf("missing DSL function")
+	}
+	return
+}
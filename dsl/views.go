@@ -19,6 +19,10 @@ type (
 	// RankDirectionKind is the enum for possible automatic layout rank
 	// directions.
 	RankDirectionKind int
+
+	// ImplementationKind is the enum for possible automatic layout
+	// implementations.
+	ImplementationKind int
 )
 
 // Global is the keyword used to define dynamic views with global scope. See
@@ -36,6 +40,13 @@ const (
 	RankRightLeft
 )
 
+const (
+	// ImplementationGraphviz uses Graphviz to compute the automatic layout.
+	ImplementationGraphviz ImplementationKind = iota + 1
+	// ImplementationDagre uses Dagre to compute the automatic layout.
+	ImplementationDagre
+)
+
 const (
 	// SizeA0Landscape defines a render page size of A0 in landscape mode (46-13/16 x 33-1/8).
 	SizeA0Landscape PaperSizeKind = iota + 1
@@ -130,6 +141,9 @@ func Views(dsl func()) {
 // views. The second argument is an optional description. The last argument is a
 // function describing the properties of the view.
 //
+// If the key is empty then the view is keyed "landscape". A "-2", "-3" etc.
+// suffix is appended if the key is already in use.
+//
 // Usage:
 //
 //    SystemLandscapeView("<key>", func())
@@ -167,7 +181,7 @@ func SystemLandscapeView(key string, args ...interface{}) {
 	}
 	v := &expr.LandscapeView{
 		ViewProps: &expr.ViewProps{
-			Key:         key,
+			Key:         viewKey(vs, key, "landscape"),
 			Description: description,
 		},
 	}
@@ -187,6 +201,10 @@ func SystemLandscapeView(key string, args ...interface{}) {
 // fltered views. The third argument is an optional description. The last
 // argument is a function describing the properties of the view.
 //
+// If the key is empty then it is generated from the view type and scope:
+// "context-<system>", slugified. A "-2", "-3" etc. suffix is appended if the
+// key is already in use.
+//
 // Usage:
 //
 //    SystemContextView(SoftwareSystem, "<key>", func())
@@ -243,7 +261,7 @@ func SystemContextView(system interface{}, key string, args ...interface{}) {
 	}
 	v := &expr.ContextView{
 		ViewProps: &expr.ViewProps{
-			Key:         key,
+			Key:         viewKey(vs, key, "context", scopeName(sid)),
 			Description: description,
 		},
 		SoftwareSystemID: sid,
@@ -264,6 +282,10 @@ func SystemContextView(system interface{}, key string, args ...interface{}) {
 // creating a filtered views. The third argument is an optional description. The
 // last argument is a function describing the properties of the view.
 //
+// If the key is empty then it is generated from the view type and scope:
+// "containers-<system>", slugified. A "-2", "-3" etc. suffix is appended if the
+// key is already in use.
+//
 // Usage:
 //
 //    ContainerView(SoftwareSystem, "<key>", func())
@@ -321,7 +343,7 @@ func ContainerView(system interface{}, key string, args ...interface{}) {
 	}
 	v := &expr.ContainerView{
 		ViewProps: &expr.ViewProps{
-			Key:         key,
+			Key:         viewKey(vs, key, "containers", scopeName(sid)),
 			Description: description,
 		},
 		SoftwareSystemID: sid,
@@ -344,6 +366,10 @@ func ContainerView(system interface{}, key string, args ...interface{}) {
 // views. Next is an optional description. The last argument must be a function
 // describing the properties of the view.
 //
+// If the key is empty then it is generated from the view type and scope:
+// "components-<system>-<container>", slugified. A "-2", "-3" etc. suffix is
+// appended if the key is already in use.
+//
 // Usage:
 //
 //    ComponentView(Container, "<key>", func())
@@ -405,7 +431,7 @@ func ComponentView(container interface{}, key string, args ...interface{}) {
 	}
 	v := &expr.ComponentView{
 		ViewProps: &expr.ViewProps{
-			Key:         key,
+			Key:         viewKey(vs, key, "components", c.System.Name, c.Name),
 			Description: description,
 		},
 		ContainerID: c.GetElement().ID,
@@ -416,6 +442,69 @@ func ComponentView(container interface{}, key string, args ...interface{}) {
 	vs.ComponentViews = append(vs.ComponentViews, v)
 }
 
+// GenerateFocusedViews defines a system context view, a container view and a
+// component view for each container of the given software systems. Only the
+// focused software systems are broken down, all other software systems are
+// rendered as plain boxes. The views use the default elements (see
+// AddDefault) and are keyed "<System>Context", "<System>Containers" and
+// "<System><Container>Components" where spaces are removed from the names.
+//
+// GenerateFocusedViews must appear in Views.
+//
+// GenerateFocusedViews accepts zero or more arguments: the software systems or
+// the names of the software systems to focus on. If no argument is given then
+// GenerateFocusedViews uses the software systems marked with InFocus.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.", func() {
+//             Container("API")
+//         })
+//         Views(func() {
+//             GenerateFocusedViews(System)
+//         })
+//     })
+//
+func GenerateFocusedViews(systems ...interface{}) {
+	if _, ok := eval.Current().(*expr.Views); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	var focused []*expr.SoftwareSystem
+	for _, system := range systems {
+		switch s := system.(type) {
+		case *expr.SoftwareSystem:
+			focused = append(focused, s)
+		case string:
+			sys := expr.Root.Model.SoftwareSystem(s)
+			if sys == nil {
+				eval.ReportError("GenerateFocusedViews: no software system named %q", s)
+				continue
+			}
+			focused = append(focused, sys)
+		default:
+			eval.InvalidArgError("software system or software system name", system)
+		}
+	}
+	if len(systems) == 0 {
+		for _, s := range expr.Root.Model.Systems {
+			if s.InFocus {
+				focused = append(focused, s)
+			}
+		}
+	}
+	for _, s := range focused {
+		key := strings.ReplaceAll(s.Name, " ", "")
+		SystemContextView(s, key+"Context", fmt.Sprintf("System context of %s.", s.Name), AddDefault)
+		ContainerView(s, key+"Containers", fmt.Sprintf("Containers of %s.", s.Name), AddDefault)
+		for _, c := range s.Containers {
+			ckey := key + strings.ReplaceAll(c.Name, " ", "") + "Components"
+			ComponentView(c, ckey, fmt.Sprintf("Components of %s.", c.Name), AddDefault)
+		}
+	}
+}
+
 // FilteredView defines a filtered view on top of the specified view.
 // The base key specifies the key of the System Landscape, System
 // Context, Container, or Component view on which this filtered view
@@ -494,6 +583,90 @@ func FilterTag(tag string, tags ...string) {
 	eval.IncompatibleDSL()
 }
 
+// ImageView defines a view that renders an image, for example a PNG or SVG
+// diagram produced by another tool, associated with an element. The image is
+// defined with Content or ContentFile.
+//
+// ImageView must appear in Views.
+//
+// ImageView accepts 4 arguments: the element or the path to the element the
+// image is associated with, a unique key for the view, a description and a
+// function describing the properties of the view. If the key is empty then it
+// is generated from the element name: "image-<element>", slugified.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         Views(func() {
+//             ImageView(System, "architecture", "Hand drawn architecture diagram.", func() {
+//                 Title("Architecture")
+//                 ContentFile("diagrams/architecture.png")
+//             })
+//         })
+//     })
+//
+func ImageView(element interface{}, key, description string, dsl func()) {
+	vs, ok := eval.Current().(*expr.Views)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	var elem *expr.Element
+	switch e := element.(type) {
+	case expr.ElementHolder:
+		elem = e.GetElement()
+	case string:
+		eh, err := expr.Root.Model.FindElement(nil, e)
+		if err != nil {
+			eval.ReportError("ImageView: " + err.Error())
+			return
+		}
+		elem = eh.GetElement()
+	default:
+		eval.InvalidArgError("element or element path", element)
+		return
+	}
+	v := &expr.ImageView{
+		Key:         viewKey(vs, key, "image", elem.Name),
+		Description: description,
+		ElementID:   elem.ID,
+	}
+	if dsl != nil {
+		eval.Execute(dsl, v)
+	}
+	vs.ImageViews = append(vs.ImageViews, v)
+}
+
+// Content sets the URL of the image rendered by an image view.
+//
+// Content must appear in ImageView.
+//
+// Content takes one argument: the URL of the image.
+func Content(url string) {
+	if v, ok := eval.Current().(*expr.ImageView); ok {
+		v.Content = url
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// ContentFile sets the path to the file containing the image rendered by an
+// image view. The path is relative to the current working directory. The
+// content of the file is embedded in the view when it is serialized. The
+// image type is deduced from the file extension (e.g. ".png" or ".svg").
+//
+// ContentFile must appear in ImageView.
+//
+// ContentFile takes one argument: the path to the image file.
+func ContentFile(path string) {
+	if v, ok := eval.Current().(*expr.ImageView); ok {
+		v.ContentFile = path
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
 // DynamicView defines a Dynamic view for the specified scope. The
 // first argument defines the scope of the view, and therefore what can
 // be added to the view, as follows:
@@ -517,6 +690,10 @@ func FilterTag(tag string, tags ...string) {
 // A dynamic view is created by specifying the relationships that should be
 // rendered via Link.
 //
+// If the key is empty then it is generated from the view type and scope:
+// "dynamic-<scope>" where scope is "global" or the scope element name,
+// slugified. A "-2", "-3" etc. suffix is appended if the key is already in use.
+//
 // Usage:
 //
 //    DynamicView(Scope, "<key>", func())
@@ -597,7 +774,7 @@ func DynamicView(scope interface{}, key string, args ...interface{}) {
 	}
 	v := &expr.DynamicView{
 		ViewProps: &expr.ViewProps{
-			Key:         key,
+			Key:         viewKey(vs, key, "dynamic", scopeName(id)),
 			Description: description,
 		},
 		ElementID: id,
@@ -629,6 +806,11 @@ func DynamicView(scope interface{}, key string, args ...interface{}) {
 // description. The last argument is a function describing the properties of the
 // view.
 //
+// If the key is empty then it is generated from the view type and scope:
+// "deployment-<scope>-<environment>" where scope is "global" or the software
+// system name, slugified. A "-2", "-3" etc. suffix is appended if the key is
+// already in use.
+//
 // Usage:
 //
 //    DeploymentView(Scope, "<environment>", "<key>", func())
@@ -703,7 +885,7 @@ func DeploymentView(scope interface{}, env, key string, args ...interface{}) {
 	}
 	v := &expr.DeploymentView{
 		ViewProps: &expr.ViewProps{
-			Key:         key,
+			Key:         viewKey(vs, key, "deployment", scopeName(id), env),
 			Description: description,
 		},
 		SoftwareSystemID: id,
@@ -718,13 +900,16 @@ func DeploymentView(scope interface{}, env, key string, args ...interface{}) {
 // Title sets the view diagram title.
 //
 // Title may appear in SystemLandscapeView, SystemContextView, ContainerView,
-// ComponentView, DynamicView or DeploymentView.
+// ComponentView, DynamicView, DeploymentView or ImageView.
 //
 // Title accepts one argument: the view title.
 func Title(t string) {
-	if v, ok := eval.Current().(expr.View); ok {
+	switch v := eval.Current().(type) {
+	case expr.View:
 		v.Props().Title = t
-	} else {
+	case *expr.ImageView:
+		v.Title = t
+	default:
 		eval.IncompatibleDSL()
 	}
 }
@@ -885,6 +1070,58 @@ func Add(element interface{}, dsl ...func()) {
 	}
 }
 
+// StyleElement overrides the style of an element in the enclosing view only.
+// The style applies on top of the styles defined in Styles for the element
+// tags and does not affect how the element is rendered in other views. The
+// style is ignored if the element is not part of the view once all the
+// elements have been added and removed.
+//
+// StyleElement must appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView, DynamicView or DeploymentView.
+//
+// StyleElement takes two arguments: the element or the path to the element
+// (see Add) and a function that defines the style using Background, Color,
+// Stroke or Shape.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         Views(func() {
+//             SystemLandscapeView("landscape", "An overview diagram.", func() {
+//                 AddAll()
+//                 StyleElement(System, func() {
+//                     Background("#ff0000")
+//                     Color("#ffffff")
+//                     Shape(ShapeRoundedBox)
+//                 })
+//             })
+//         })
+//     })
+//
+func StyleElement(element interface{}, dsl func()) {
+	v, ok := eval.Current().(expr.View)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	eh, err := findViewElement(v, element)
+	if err != nil {
+		eval.ReportError("StyleElement: " + err.Error())
+		return
+	}
+	vp := v.Props()
+	if vp.ElementStyles == nil {
+		vp.ElementStyles = make(map[string]*expr.ElementStyle)
+	}
+	style, ok := vp.ElementStyles[eh.GetElement().ID]
+	if !ok {
+		style = &expr.ElementStyle{}
+		vp.ElementStyles[eh.GetElement().ID] = style
+	}
+	eval.Execute(dsl, style)
+}
+
 // Link adds a relationship to a view.
 //
 // Link must appear in SystemLandscapeView, SystemContextView, ContainerView,
@@ -977,7 +1214,7 @@ func Add(element interface{}, dsl ...func()) {
 //                     Routing(RoutingOrthogonal)
 //                     Position(45)
 //                     Description("Customer sends email to support")
-//                     Order("1")
+//                     Order(1)
 //                 })
 //             })
 //         })
@@ -1001,7 +1238,66 @@ func Link(source, destination interface{}, args ...interface{}) {
 	if dsl != nil {
 		eval.Execute(dsl, rel)
 	}
-	v.Props().RelationshipViews = append(v.Props().RelationshipViews, rel)
+	vp := v.Props()
+	vp.RelationshipViews = append(vp.RelationshipViews, rel)
+	if ret := rel.Return; ret != nil {
+		if _, ok := v.(*expr.DynamicView); !ok {
+			eval.ReportError("Link: Return can only be used in DynamicView")
+			return
+		}
+		if rel.Order == "" {
+			n := 0
+			for _, rv := range vp.RelationshipViews {
+				if !rv.Response {
+					n++
+				}
+			}
+			rel.Order = strconv.Itoa(n)
+		}
+		ret.Order = rel.Order + ".1"
+		vp.RelationshipViews = append(vp.RelationshipViews, ret)
+	}
+}
+
+// Return adds the response to the relationship being linked in a dynamic view.
+// The response is rendered as a relationship going from the destination back
+// to the source with the given description. The order of the response is the
+// order of the relationship followed by ".1" (e.g. "2.1"). The order of the
+// relationship is set to its position in the view if not set explicitly with
+// Order.
+//
+// Return must appear in Link in a DynamicView.
+//
+// Return takes one argument: the description of the response.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         var Customer = Person("Customer", func() {
+//             Uses(System, "Requests quote")
+//         })
+//         Views(func() {
+//             DynamicView(System, "quote", func() {
+//                 Link(Customer, System, func() {
+//                     Return("Sends quote")
+//                 })
+//             })
+//         })
+//     })
+//
+func Return(description string) {
+	rv, ok := eval.Current().(*expr.RelationshipView)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	rv.Return = &expr.RelationshipView{
+		Source:      rv.Source,
+		Destination: rv.Destination,
+		Description: description,
+		Response:    true,
+	}
 }
 
 // AddAll includes all elements and relationships in the view scope.
@@ -1037,6 +1333,48 @@ func AddAll() {
 	}
 }
 
+// AddAllTagged includes the elements in the view scope that have all the
+// given included tags and none of the excluded tags as well as the
+// relationships between them. AddAllTagged makes it possible to define views
+// targeted at a specific audience.
+//
+// AddAllTagged may appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView or DeploymentView.
+//
+// AddAllTagged takes two arguments: the list of tags elements must have and
+// the list of tags elements must not have. At least one tag must be provided.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         SoftwareSystem("Partner System", func() {
+//             Tag("external")
+//         })
+//         Views(func() {
+//             SystemLandscapeView("partners", "Current partner systems.", func() {
+//                 AddAllTagged([]string{"external"}, []string{"deprecated"})
+//             })
+//         })
+//     })
+//
+func AddAllTagged(includeTags, excludeTags []string) {
+	if len(includeTags) == 0 && len(excludeTags) == 0 {
+		eval.ReportError("AddAllTagged: at least one tag must be provided")
+		return
+	}
+	switch v := eval.Current().(type) {
+	case *expr.DynamicView:
+		eval.IncompatibleDSL()
+	case expr.View:
+		vp := v.Props()
+		vp.AddAll = true
+		vp.AddAllRequiredTags = includeTags
+		vp.AddAllExcludedTags = excludeTags
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
 // AddNeighbors Adds all of the permitted elements which are directly connected
 // to the specified element. Permitted elements are software systems and people
 // for system landscape and system context views, software systems, people and
@@ -1103,8 +1441,9 @@ func AddNeighbors(element interface{}) {
 // AddDefault adds default elements that are relevant for the specific view:
 //
 //    - System landscape view: adds all software systems and people
-//    - System context view: adds softare system and other related software systems
-//      and people.
+//    - System context view: adds the software system in scope as well as the
+//      people and software systems it is directly connected to together with
+//      the relationships between them.
 //    - Container view: adds all containers in software system as well as related
 //      software systems and people.
 //    - Component view: adds all components in container as well as related
@@ -1255,6 +1594,50 @@ func Remove(element interface{}) {
 	v.Props().RemoveElements = append(v.Props().RemoveElements, eh.GetElement())
 }
 
+// ExcludeSubtree removes the given element, all its descendants and all their
+// relationships from the view. This is useful to remove the containers and
+// components of a software system pulled in with AddNeighbors for example.
+// It is an error if neither the element nor any of its descendants is in the
+// view once the elements added with AddAll, AddDefault and AddNeighbors are
+// computed.
+//
+// ExcludeSubtree must appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView or DeploymentView.
+//
+// ExcludeSubtree takes one argument: the element or the path to the element
+// as described in Remove.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         var OtherSystem = SoftwareSystem("Other System", func() {
+//             Container("Database", func() {
+//                 Uses(System, "Notifies")
+//             })
+//         })
+//         Views(func() {
+//             ContainerView(System, "containers", "Container diagram.", func() {
+//                 AddNeighbors(System)
+//                 ExcludeSubtree(OtherSystem)
+//             })
+//         })
+//     })
+//
+func ExcludeSubtree(element interface{}) {
+	v, ok := eval.Current().(expr.View)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	eh, err := findViewElement(v, element)
+	if err != nil {
+		eval.ReportError("ExcludeSubtree: " + err.Error())
+		return
+	}
+	v.Props().RemoveSubtrees = append(v.Props().RemoveSubtrees, eh.GetElement())
+}
+
 // RemoveTagged removes all elements and relationships with the given tag from
 // the view.
 //
@@ -1299,6 +1682,48 @@ func RemoveTagged(tag string) {
 	v.Props().RemoveTags = append(v.Props().RemoveTags, tag)
 }
 
+// RemoveNodesTagged removes the deployment nodes and infrastructure nodes that
+// have one of the given tags from a deployment view. The children of the
+// removed deployment nodes (child deployment nodes, infrastructure nodes and
+// container instances) are removed as well. RemoveNodesTagged makes it
+// possible to hide nodes that are not relevant to the view audience such as
+// monitoring sidecars.
+//
+// RemoveNodesTagged must appear in DeploymentView.
+//
+// RemoveNodesTagged takes one or more tags as argument.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.", func() {
+//             Container("API")
+//         })
+//         DeploymentEnvironment("Production", func() {
+//             DeploymentNode("Cluster", func() {
+//                 ContainerInstance("Software System/API")
+//                 DeploymentNode("Prometheus", func() {
+//                     Tag("monitoring")
+//                 })
+//             })
+//         })
+//         Views(func() {
+//             DeploymentView(System, "Production", "deployment", "Application only.", func() {
+//                 AddAll()
+//                 RemoveNodesTagged("monitoring")
+//             })
+//         })
+//     })
+//
+func RemoveNodesTagged(tag string, tags ...string) {
+	v, ok := eval.Current().(*expr.DeploymentView)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	v.RemoveNodeTags = append(v.RemoveNodeTags, append([]string{tag}, tags...)...)
+}
+
 // Unlink removes a relationship from a view.
 //
 // Unlink must appear in SystemLandscapeView, SystemContextView, ContainerView
@@ -1609,6 +2034,208 @@ func PaperSize(size PaperSizeKind) {
 	v.Props().PaperSize = expr.PaperSizeKind(size)
 }
 
+// Dimensions defines the default size in pixels of the rendered view. This
+// controls the viewport of the SVG generated by the Structurizr service.
+//
+// Dimensions must appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView, DynamicView or DeploymentView.
+//
+// Dimensions accepts two arguments: the width and the height. Both values must
+// be strictly positive.
+//
+// Example
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         Views(func() {
+//             SystemContextView(System, "context", "An overview diagram.", func() {
+//                 AddDefault()
+//                 Dimensions(1200, 800)
+//             })
+//         })
+//     })
+//
+func Dimensions(width, height int) {
+	v, ok := eval.Current().(expr.View)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if width <= 0 || height <= 0 {
+		eval.ReportError("Dimensions: width and height must be strictly positive, got %d and %d", width, height)
+		return
+	}
+	v.Props().Dimensions = &expr.Dimensions{Width: width, Height: height}
+}
+
+// EnableLegend shows the legend when the view is rendered. EnableLegend cannot
+// be used together with DisableLegend in the same view.
+//
+// EnableLegend must appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView, DynamicView or DeploymentView.
+//
+// EnableLegend takes no argument.
+//
+// Example
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         Views(func() {
+//             SystemContextView(System, "context", "An overview diagram.", func() {
+//                 AddDefault()
+//                 EnableLegend()
+//             })
+//         })
+//     })
+//
+func EnableLegend() {
+	v, ok := eval.Current().(expr.View)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	vp := v.Props()
+	if vp.Legend != nil && !*vp.Legend {
+		eval.ReportError("EnableLegend: cannot be used together with DisableLegend in the same view")
+		return
+	}
+	show := true
+	vp.Legend = &show
+}
+
+// DisableLegend hides the legend when the view is rendered. DisableLegend
+// cannot be used together with EnableLegend in the same view.
+//
+// DisableLegend must appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView, DynamicView or DeploymentView.
+//
+// DisableLegend takes no argument.
+//
+// Example
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         Views(func() {
+//             SystemContextView(System, "context", "An overview diagram.", func() {
+//                 AddDefault()
+//                 DisableLegend()
+//             })
+//         })
+//     })
+//
+func DisableLegend() {
+	v, ok := eval.Current().(expr.View)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	vp := v.Props()
+	if vp.Legend != nil && *vp.Legend {
+		eval.ReportError("DisableLegend: cannot be used together with EnableLegend in the same view")
+		return
+	}
+	show := false
+	vp.Legend = &show
+}
+
+// ShowLegend renders a legend that maps each tag used by the elements and
+// relationships of the view to its style (colors, shape, line style) when the
+// view is rendered by an exporter that does not draw a legend of its own, for
+// example the SVG renderer. ShowLegend does not apply to Structurizr which
+// draws its own legend, see EnableLegend and DisableLegend.
+//
+// ShowLegend must appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView, DynamicView or DeploymentView.
+//
+// ShowLegend takes one argument: whether the legend is rendered.
+//
+// Example
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         Views(func() {
+//             SystemContextView(System, "context", "An overview diagram.", func() {
+//                 AddDefault()
+//                 ShowLegend(true)
+//             })
+//         })
+//     })
+//
+func ShowLegend(show bool) {
+	v, ok := eval.Current().(expr.View)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	v.Props().ShowLegend = show
+}
+
+// HideEmptyGroups omits the groups that contain no element of the view,
+// directly or in a nested group, from the diagrams and the JSON rendered by
+// the mdl package. By default the groups of all the elements at the same level
+// as the elements of the view are rendered, even if none of their elements is
+// in the view. Structurizr only draws the groups of the elements of the view.
+// See Group.
+//
+// HideEmptyGroups must appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView, DynamicView or DeploymentView.
+//
+// HideEmptyGroups takes one argument: whether empty groups are hidden.
+//
+// Example
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.", func() {
+//             Group("Team A")
+//         })
+//         var _ = SoftwareSystem("Other System", "Another software system.", func() {
+//             Group("Team B")
+//         })
+//         Views(func() {
+//             SystemContextView(System, "context", "An overview diagram.", func() {
+//                 AddDefault()
+//                 HideEmptyGroups(true)
+//             })
+//         })
+//     })
+//
+func HideEmptyGroups(hide bool) {
+	v, ok := eval.Current().(expr.View)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	v.Props().HideEmptyGroups = hide
+}
+
+// HideTitle hides the title of the view when it is rendered.
+//
+// HideTitle must appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView, DynamicView or DeploymentView.
+//
+// HideTitle takes no argument.
+//
+// Example
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         Views(func() {
+//             SystemContextView(System, "context", "An overview diagram.", func() {
+//                 AddDefault()
+//                 HideTitle()
+//             })
+//         })
+//     })
+//
+func HideTitle() {
+	v, ok := eval.Current().(expr.View)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	v.Props().HideTitle = true
+}
+
 // EnterpriseBoundaryVisible makes the enterprise boundary visible to differentiate internal
 // elements from external elements on the resulting diagram.
 //
@@ -1627,6 +2254,37 @@ func EnterpriseBoundaryVisible() {
 	}
 }
 
+// GroupByEnterprise draws a boundary around the software systems of each
+// enterprise. The enterprise of a software system is set using Enterprise in
+// the SoftwareSystem expression.
+//
+// GroupByEnterprise must appear in SystemLandscapeView.
+//
+// GroupByEnterprise takes no argument
+func GroupByEnterprise() {
+	if v, ok := eval.Current().(*expr.LandscapeView); ok {
+		v.GroupByEnterprise = true
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// AggregateRelationships renders multiple relationships between the same
+// source and destination as a single relationship whose description combines
+// the descriptions of the aggregated relationships. The model relationships are
+// not modified.
+//
+// AggregateRelationships must appear in SystemLandscapeView.
+//
+// AggregateRelationships takes no argument
+func AggregateRelationships() {
+	if v, ok := eval.Current().(*expr.LandscapeView); ok {
+		v.AggregateRelationships = true
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
 // SystemBoundariesVisible makes the system boundaries visible for "external" containers
 // (those outside the software system in scope)
 //
@@ -1757,7 +2415,7 @@ func Vertices(args ...int) {
 		eval.IncompatibleDSL()
 	}
 	for i := 0; i < len(args); i += 2 {
-		rv.Vertices = append(rv.Vertices, &expr.Vertex{args[i], args[i+1]})
+		rv.Vertices = append(rv.Vertices, &expr.Vertex{X: args[i], Y: args[i+1]})
 	}
 }
 
@@ -1861,7 +2519,7 @@ func Position(pos int) {
 //
 func RankSeparation(sep int) {
 	if sep < 0 {
-		eval.ReportError("RankSeparation: value must be positive")
+		eval.ReportError("RankSeparation: value cannot be negative")
 		return
 	}
 	if a, ok := eval.Current().(*expr.AutoLayout); ok {
@@ -1893,7 +2551,7 @@ func RankSeparation(sep int) {
 //
 func NodeSeparation(sep int) {
 	if sep < 0 {
-		eval.ReportError("NodeSeparation: value must be positive")
+		eval.ReportError("NodeSeparation: value cannot be negative")
 		return
 	}
 	if a, ok := eval.Current().(*expr.AutoLayout); ok {
@@ -1925,7 +2583,7 @@ func NodeSeparation(sep int) {
 //
 func EdgeSeparation(sep int) {
 	if sep < 0 {
-		eval.ReportError("EdgeSeparation: value must be positive")
+		eval.ReportError("EdgeSeparation: value cannot be negative")
 		return
 	}
 	if a, ok := eval.Current().(*expr.AutoLayout); ok {
@@ -1935,13 +2593,43 @@ func EdgeSeparation(sep int) {
 	eval.IncompatibleDSL()
 }
 
-// RenderVertices indicates that vertices should be created during automatic
-// layout, false by default.
+// Implementation sets the algorithm used to compute the automatic layout.
+// Implementation only applies to views rendered in the Structurizr service.
+//
+// Implementation must appear in AutoLayout.
+//
+// Implementation takes one argument: ImplementationGraphviz or
+// ImplementationDagre.
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         var System = SoftwareSystem("Software System", "My software system.")
+//         Views(func() {
+//             SystemContextView(SoftwareSystem, "context", "An overview diagram.", func() {
+//                 AutoLayout(RankTopBottom, func() {
+//                     Implementation(ImplementationGraphviz)
+//                 })
+//             })
+//         })
+//     })
+//
+func Implementation(impl ImplementationKind) {
+	if a, ok := eval.Current().(*expr.AutoLayout); ok {
+		a.Implementation = expr.ImplementationKind(impl)
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// RenderVertices indicates whether vertices should be created during automatic
+// layout. The Structurizr default (no vertices) applies if RenderVertices is
+// not used.
 // RenderVertices only applies to views rendered in the Structurizr service.
 //
 // RenderVertices must appear in AutoLayout.
 //
-// RenderVertices takes no argument.
+// RenderVertices accepts an optional boolean argument, true if omitted.
 //
 // Example:
 //
@@ -1950,21 +2638,93 @@ func EdgeSeparation(sep int) {
 //         Views(func() {
 //             SystemContextView(SoftwareSystem, "context", "An overview diagram.", func() {
 //                 AutoLayout(func() {
-//                     RenderVertices()
+//                     RenderVertices(true)
 //                 })
 //             })
 //         })
 //     })
 //
-func RenderVertices() {
+func RenderVertices(render ...bool) {
+	if len(render) > 1 {
+		eval.ReportError("RenderVertices: too many arguments")
+		return
+	}
 	if a, ok := eval.Current().(*expr.AutoLayout); ok {
-		t := true
+		t := len(render) == 0 || render[0]
 		a.Vertices = &t
 		return
 	}
 	eval.IncompatibleDSL()
 }
 
+// viewKey returns key if not empty. Otherwise viewKey generates a key by
+// joining the slugified view kind and scope names with dashes, e.g.
+// "context-my-system". A "-2", "-3" etc. suffix is appended if the key is
+// already used by another view. Generated keys only depend on the order in
+// which views are defined so they are stable across runs.
+func viewKey(vs *expr.Views, key string, parts ...string) string {
+	if key != "" {
+		return key
+	}
+	base := slugify(strings.Join(parts, " "))
+	key = base
+	for i := 2; viewKeyInUse(vs, key); i++ {
+		key = fmt.Sprintf("%s-%d", base, i)
+	}
+	return key
+}
+
+// viewKeyInUse returns true if a view or a filtered view uses the given key.
+func viewKeyInUse(vs *expr.Views, key string) bool {
+	for _, v := range vs.All() {
+		if v.Props().Key == key {
+			return true
+		}
+	}
+	for _, fv := range vs.FilteredViews {
+		if fv.Key == key {
+			return true
+		}
+	}
+	for _, iv := range vs.ImageViews {
+		if iv.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeName returns the name of the element with the given ID or "global" if
+// the ID is empty.
+func scopeName(id string) string {
+	if id == "" {
+		return "global"
+	}
+	if eh, ok := expr.Registry[id].(expr.ElementHolder); ok {
+		return eh.GetElement().Name
+	}
+	return id
+}
+
+// slugify lowercases s and replaces any sequence of characters that are not
+// ASCII letters or digits with a single dash.
+func slugify(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return sb.String()
+}
+
 // parseView is a helper function that parses the given view DSL
 // arguments. Accepted syntax are:
 //
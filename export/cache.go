@@ -0,0 +1,77 @@
+package export
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"goa.design/model/expr"
+)
+
+type (
+	// Exporter renders the given view to w, for example mdl.RenderSVG.
+	Exporter func(v expr.View, w io.Writer) error
+
+	// Cache memoizes the output of exporters. The output is cached by view
+	// key and exporter name and is reused as long as the fingerprints of
+	// the model, of the view properties and of the styles are unchanged
+	// (see expr.Model.Fingerprint, expr.ViewProps.Fingerprint and
+	// expr.Styles.Fingerprint). Only the output computed for the latest
+	// fingerprints is kept for a given view and exporter. A Cache is safe for concurrent use, the zero value is an
+	// empty cache ready to use.
+	Cache struct {
+		mu      sync.Mutex
+		entries map[cacheKey]*cacheEntry
+	}
+
+	// cacheKey identifies the output of an exporter for a view.
+	cacheKey struct {
+		exporter string
+		view     string
+	}
+
+	// cacheEntry is the output of an exporter for given model, view and
+	// styles fingerprints.
+	cacheEntry struct {
+		fingerprint string
+		output      []byte
+	}
+)
+
+// Export writes the output of the exporter with the given name for view v of
+// model m to w. Export only calls export if the output is not cached already
+// for the current fingerprints of m, v and the design styles. Errors returned
+// by export are not cached.
+func (c *Cache) Export(name string, export Exporter, m *expr.Model, v expr.View, w io.Writer) error {
+	key := cacheKey{exporter: name, view: v.Props().Key}
+	fp := fingerprint(m, v)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && entry.fingerprint == fp {
+		_, err := w.Write(entry.output)
+		return err
+	}
+	var buf bytes.Buffer
+	if err := export(v, &buf); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[cacheKey]*cacheEntry)
+	}
+	c.entries[key] = &cacheEntry{fingerprint: fp, output: buf.Bytes()}
+	c.mu.Unlock()
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// fingerprint combines the fingerprints of the model, of the view properties
+// and of the design styles that exporters use to render v.
+func fingerprint(m *expr.Model, v expr.View) string {
+	var styles *expr.Styles
+	if expr.Root.Views != nil {
+		styles = expr.Root.Views.Styles
+	}
+	return m.Fingerprint() + v.Props().Fingerprint() + styles.Fingerprint()
+}
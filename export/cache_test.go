@@ -0,0 +1,120 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"goa.design/model/expr"
+)
+
+func TestCacheExport(t *testing.T) {
+	t.Parallel()
+	var (
+		sys = &expr.SoftwareSystem{Element: &expr.Element{ID: "1", Name: "System"}}
+		m   = &expr.Model{Systems: expr.SoftwareSystems{sys}}
+		v   = &expr.LandscapeView{ViewProps: &expr.ViewProps{Key: "landscape"}}
+	)
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	spy := func(v expr.View, w io.Writer) error {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		_, err := fmt.Fprintf(w, "view %s", v.Props().Key)
+		return err
+	}
+	var c Cache
+	export := func() string {
+		var sb strings.Builder
+		if err := c.Export("spy", spy, m, v, &sb); err != nil {
+			t.Fatal(err)
+		}
+		return sb.String()
+	}
+
+	first, second := export(), export()
+
+	if calls != 1 {
+		t.Errorf("got %d exporter calls for an unchanged model, want 1", calls)
+	}
+	if first != "view landscape" || second != first {
+		t.Errorf("got outputs %q and %q, want %q", first, second, "view landscape")
+	}
+
+	sys.Description = "Changed"
+	export()
+
+	if calls != 2 {
+		t.Errorf("got %d exporter calls after the model changed, want 2", calls)
+	}
+}
+
+func TestCacheExportInvalidation(t *testing.T) {
+	styles := expr.Root.Views.Styles
+	defer func() { expr.Root.Views.Styles = styles }()
+	expr.Root.Views.Styles = &expr.Styles{Elements: []*expr.ElementStyle{{Tag: "Element", Color: "#000000"}}}
+
+	tests := []struct {
+		name   string
+		change func(sys, api *expr.Element, rel *expr.Relationship, vp *expr.ViewProps)
+	}{
+		{"element-size", func(sys, _ *expr.Element, _ *expr.Relationship, _ *expr.ViewProps) {
+			sys.Size = &expr.Dimensions{Width: 300, Height: 200}
+		}},
+		{"element-group", func(sys, _ *expr.Element, _ *expr.Relationship, _ *expr.ViewProps) { sys.Group = "Team" }},
+		{"relationship-weight", func(_, _ *expr.Element, rel *expr.Relationship, _ *expr.ViewProps) { rel.Weight = 3 }},
+		{"element-position", func(_, _ *expr.Element, _ *expr.Relationship, vp *expr.ViewProps) {
+			x := 100
+			vp.ElementViews[0].X = &x
+		}},
+		{"relationship-vertices", func(_, _ *expr.Element, _ *expr.Relationship, vp *expr.ViewProps) {
+			vp.RelationshipViews[0].Vertices = []*expr.Vertex{{X: 10, Y: 20}}
+		}},
+		{"auto-layout", func(_, _ *expr.Element, _ *expr.Relationship, vp *expr.ViewProps) {
+			vp.AutoLayout = &expr.AutoLayout{RankDirection: expr.RankLeftRight}
+		}},
+		{"legend", func(_, _ *expr.Element, _ *expr.Relationship, vp *expr.ViewProps) { vp.ShowLegend = true }},
+		{"title", func(_, _ *expr.Element, _ *expr.Relationship, vp *expr.ViewProps) { vp.HideTitle = true }},
+		{"style", func(_, _ *expr.Element, _ *expr.Relationship, _ *expr.ViewProps) {
+			expr.Root.Views.Styles.Elements[0].Color = "#ffffff"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				sys = &expr.SoftwareSystem{Element: &expr.Element{ID: "1", Name: "System"}}
+				api = &expr.SoftwareSystem{Element: &expr.Element{ID: "2", Name: "API"}}
+				rel = &expr.Relationship{ID: "3", Source: sys.Element, Destination: api.Element}
+				m   = &expr.Model{Systems: expr.SoftwareSystems{sys, api}}
+				vp  = &expr.ViewProps{
+					Key:               "landscape",
+					ElementViews:      []*expr.ElementView{{Element: sys.Element}, {Element: api.Element}},
+					RelationshipViews: []*expr.RelationshipView{{Source: sys.Element, Destination: api.Element, RelationshipID: rel.ID}},
+				}
+				v     = &expr.LandscapeView{ViewProps: vp}
+				c     Cache
+				calls int
+			)
+			sys.Relationships = []*expr.Relationship{rel}
+			spy := func(expr.View, io.Writer) error { calls++; return nil }
+			if err := c.Export("spy", spy, m, v, ioutil.Discard); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.change(sys.Element, api.Element, rel, vp)
+			if err := c.Export("spy", spy, m, v, ioutil.Discard); err != nil {
+				t.Fatal(err)
+			}
+
+			if calls != 2 {
+				t.Errorf("got %d exporter calls, want 2", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"goa.design/model/expr"
+)
+
+// dotDPI is the number of pixels per inch used to convert the element sizes
+// to the DOT node width and height which are expressed in inches.
+const dotDPI = 72
+
+// dotEscaper escapes the characters that cannot appear as is in DOT quoted
+// strings.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// DOT writes the static structure of the given model to w using the Graphviz
+// DOT language. The graph contains a node for each person, software system,
+// container and component and an edge for each relationship between them.
+// Implied relationships are not rendered. Edges are labeled with the
+// relationship description followed by its technology if the model sets
+// AppendTechnologyToLabels and their pen width is the weight of the
+// relationship (see expr.Relationship.EffectiveWeight) so that relationships
+// with a higher weight are rendered with thicker lines. Nodes of elements
+// that define a size (see expr.Element.Size) have their width and height set
+// accordingly, for example:
+//
+//    digraph model {
+//        "1" [label="User", shape=box];
+//        "2" [label="System", shape=box, width=8.33, height=2.78];
+//        "1" -> "2" [label="Uses", penwidth=3];
+//    }
+//
+// The output is deterministic: nodes and edges are written in model order.
+func DOT(m *expr.Model, w io.Writer) error {
+	var elems []*expr.Element
+	for _, p := range m.People {
+		elems = append(elems, p.Element)
+	}
+	for _, s := range m.Systems {
+		elems = append(elems, s.Element)
+		for _, c := range s.Containers {
+			elems = append(elems, c.Element)
+			for _, cmp := range c.Components {
+				elems = append(elems, cmp.Element)
+			}
+		}
+	}
+	static := make(map[string]bool, len(elems))
+	for _, e := range elems {
+		static[e.ID] = true
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph model {\n")
+	for _, e := range elems {
+		var size string
+		if e.Size != nil {
+			size = fmt.Sprintf(", width=%.2f, height=%.2f", float64(e.Size.Width)/dotDPI, float64(e.Size.Height)/dotDPI)
+		}
+		fmt.Fprintf(&sb, "    \"%s\" [label=\"%s\", shape=box%s];\n", dotEscaper.Replace(e.ID), dotEscaper.Replace(e.Name), size)
+	}
+	for _, e := range elems {
+		for _, r := range m.ElementRelationships(e) {
+			if r.Implied || r.Destination == nil || !static[r.Destination.ID] {
+				continue
+			}
+			fmt.Fprintf(&sb, "    \"%s\" -> \"%s\" [label=\"%s\", penwidth=%d];\n",
+				dotEscaper.Replace(e.ID), dotEscaper.Replace(r.Destination.ID), dotEscaper.Replace(r.Label(r.Description, m.AppendTechnologyToLabels)), r.EffectiveWeight())
+		}
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/model/expr"
+)
+
+func TestDOTWeight(t *testing.T) {
+	t.Parallel()
+	var (
+		user = &expr.Person{Element: &expr.Element{ID: "1", Name: "User"}}
+		sys  = &expr.SoftwareSystem{Element: &expr.Element{ID: "2", Name: "System"}}
+		api  = &expr.Container{Element: &expr.Element{ID: "3", Name: "API"}, System: sys}
+	)
+	sys.Containers = expr.Containers{api}
+	user.Relationships = []*expr.Relationship{
+		{ID: "4", Source: user.Element, Destination: sys.Element, Description: "Uses"},
+		{ID: "5", Source: user.Element, Destination: api.Element, Description: "Calls", Weight: 5},
+		{ID: "6", Source: user.Element, Destination: api.Element, Description: "Implied", Implied: true},
+	}
+	m := &expr.Model{People: expr.People{user}, Systems: expr.SoftwareSystems{sys}}
+	want := `digraph model {
+    "1" [label="User", shape=box];
+    "2" [label="System", shape=box];
+    "3" [label="API", shape=box];
+    "1" -> "2" [label="Uses", penwidth=1];
+    "1" -> "3" [label="Calls", penwidth=5];
+}
+`
+
+	var sb strings.Builder
+	if err := DOT(m, &sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sb.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDOTLabel(t *testing.T) {
+	t.Parallel()
+	var (
+		api = &expr.SoftwareSystem{Element: &expr.Element{ID: "1", Name: "API"}}
+		db  = &expr.SoftwareSystem{Element: &expr.Element{ID: "2", Name: "DB"}}
+	)
+	api.Relationships = []*expr.Relationship{
+		{ID: "3", Source: api.Element, Destination: db.Element, Description: "Reads from", Technology: "SQL"},
+	}
+	tests := []struct {
+		name       string
+		appendTech bool
+		want       string
+	}{
+		{"description", false, `"1" -> "2" [label="Reads from", penwidth=1];`},
+		{"appended", true, `"1" -> "2" [label="Reads from [SQL]", penwidth=1];`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m := &expr.Model{Systems: expr.SoftwareSystems{api, db}, AppendTechnologyToLabels: tt.appendTech}
+			var sb strings.Builder
+
+			if err := DOT(m, &sb); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := sb.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("got:\n%s\nwant it to contain %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Package export provides exporters that render information about a model in
+formats consumed by third party tools.
+
+The exporters work on the expressions produced by running the DSL, for
+example:
+
+    if err := eval.RunDSL(); err != nil {
+        return err
+    }
+    return export.Prometheus(expr.Root.Model, os.Stdout)
+*/
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"goa.design/model/expr"
+)
+
+// Prometheus writes the metrics of the given model to w using the Prometheus
+// text exposition format. The exported metrics are:
+//
+//    - model_elements_total: the number of elements by type (e.g.
+//      model_elements_total{type="container"}).
+//    - model_relationships_total: the number of relationships, relationships
+//      replicated onto container instances are not counted.
+//    - model_orphan_elements_total: the number of people, software systems,
+//      containers and components that are neither the source nor the
+//      destination of a relationship.
+//
+// The output is deterministic: metrics and labels are always written in the
+// same order.
+func Prometheus(m *expr.Model, w io.Writer) error {
+	metrics := m.Metrics()
+	static := make(map[*expr.Element]bool)
+	for _, p := range m.People {
+		static[p.Element] = true
+	}
+	for _, s := range m.Systems {
+		static[s.Element] = true
+		for _, c := range s.Containers {
+			static[c.Element] = true
+			for _, cmp := range c.Components {
+				static[cmp.Element] = true
+			}
+		}
+	}
+	orphans := 0
+	for _, em := range metrics.Elements {
+		if static[em.Element] && em.FanIn == 0 && em.FanOut == 0 {
+			orphans++
+		}
+	}
+
+	var sb strings.Builder
+	gauge := func(name, help string) {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+	gauge("model_elements_total", "Number of elements in the model by type.")
+	for _, c := range []struct {
+		typ   string
+		count int
+	}{
+		{"component", metrics.Components},
+		{"container", metrics.Containers},
+		{"container_instance", metrics.ContainerInstances},
+		{"deployment_node", metrics.DeploymentNodes},
+		{"infrastructure_node", metrics.InfrastructureNodes},
+		{"person", metrics.People},
+		{"software_system", metrics.Systems},
+	} {
+		fmt.Fprintf(&sb, "model_elements_total{type=%q} %d\n", c.typ, c.count)
+	}
+	gauge("model_relationships_total", "Number of relationships in the model.")
+	fmt.Fprintf(&sb, "model_relationships_total %d\n", metrics.Relationships)
+	gauge("model_orphan_elements_total", "Number of people, software systems, containers and components without relationships.")
+	fmt.Fprintf(&sb, "model_orphan_elements_total %d\n", orphans)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/model/expr"
+)
+
+func TestPrometheus(t *testing.T) {
+	t.Parallel()
+	var (
+		user = &expr.Person{Element: &expr.Element{ID: "1", Name: "User"}}
+		sys  = &expr.SoftwareSystem{Element: &expr.Element{ID: "2", Name: "System"}}
+		api  = &expr.Container{Element: &expr.Element{ID: "3", Name: "API"}, System: sys}
+		db   = &expr.Container{Element: &expr.Element{ID: "4", Name: "DB"}, System: sys}
+	)
+	sys.Containers = expr.Containers{api, db}
+	user.Relationships = []*expr.Relationship{{ID: "5", Source: user.Element, Destination: api.Element}}
+	m := &expr.Model{People: expr.People{user}, Systems: expr.SoftwareSystems{sys}}
+	want := `# HELP model_elements_total Number of elements in the model by type.
+# TYPE model_elements_total gauge
+model_elements_total{type="component"} 0
+model_elements_total{type="container"} 2
+model_elements_total{type="container_instance"} 0
+model_elements_total{type="deployment_node"} 0
+model_elements_total{type="infrastructure_node"} 0
+model_elements_total{type="person"} 1
+model_elements_total{type="software_system"} 1
+# HELP model_relationships_total Number of relationships in the model.
+# TYPE model_relationships_total gauge
+model_relationships_total 1
+# HELP model_orphan_elements_total Number of people, software systems, containers and components without relationships.
+# TYPE model_orphan_elements_total gauge
+model_orphan_elements_total 2
+`
+
+	var sb strings.Builder
+	if err := Prometheus(m, &sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sb.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package expr
+
+type (
+	// CatalogEntry describes a single element in the model catalog.
+	CatalogEntry struct {
+		// Type is the element type, e.g. "Software System".
+		Type string
+		// Path is the fully qualified path of the element, see
+		// FindElement.
+		Path string
+		// Element is the cataloged element.
+		Element *Element
+		// Responsibilities lists the element responsibilities if any.
+		Responsibilities []string
+	}
+)
+
+// Catalog returns an entry for each person, software system, container and
+// component of the model in model order. Each container follows its software
+// system and each component its container.
+func (m *Model) Catalog() []*CatalogEntry {
+	var entries []*CatalogEntry
+	add := func(typ, path string, e *Element) {
+		entries = append(entries, &CatalogEntry{
+			Type:             typ,
+			Path:             path,
+			Element:          e,
+			Responsibilities: e.Responsibilities,
+		})
+	}
+	for _, p := range m.People {
+		add("Person", p.Name, p.Element)
+	}
+	for _, s := range m.Systems {
+		add("Software System", s.Name, s.Element)
+		for _, c := range s.Containers {
+			cpath := s.Name + "/" + c.Name
+			add("Container", cpath, c.Element)
+			for _, cmp := range c.Components {
+				add("Component", cpath+"/"+cmp.Name, cmp.Element)
+			}
+		}
+	}
+	return entries
+}
@@ -0,0 +1,40 @@
+package expr
+
+import "testing"
+
+func TestComponentResponsibilities(t *testing.T) {
+	t.Parallel()
+	sys := &SoftwareSystem{Element: &Element{Name: "System"}}
+	cont := &Container{Element: &Element{Name: "API"}, System: sys}
+	cmp := &Component{
+		Element: &Element{
+			Name:             "Orders",
+			Description:      "Handles orders.",
+			Responsibilities: []string{"Validates orders", "Persists orders"},
+		},
+		Container: cont,
+	}
+	sys.Containers = Containers{cont}
+	cont.Components = Components{cmp}
+	m := &Model{Systems: SoftwareSystems{sys}}
+
+	cmp.Finalize()
+
+	if got, want := cmp.Properties[ResponsibilitiesProperty], "Validates orders\nPersists orders"; got != want {
+		t.Errorf("got property %q, want %q", got, want)
+	}
+	if got, want := cmp.DescriptionWithResponsibilities(), "Handles orders.\n- Validates orders\n- Persists orders"; got != want {
+		t.Errorf("got description %q, want %q", got, want)
+	}
+	catalog := m.Catalog()
+	if len(catalog) != 3 {
+		t.Fatalf("got %d catalog entries, want 3", len(catalog))
+	}
+	entry := catalog[2]
+	if entry.Path != "System/API/Orders" || entry.Type != "Component" {
+		t.Errorf("got entry %q of type %q, want %q of type %q", entry.Path, entry.Type, "System/API/Orders", "Component")
+	}
+	if len(entry.Responsibilities) != 2 || entry.Responsibilities[0] != "Validates orders" {
+		t.Errorf("got responsibilities %v, want %v", entry.Responsibilities, cmp.Responsibilities)
+	}
+}
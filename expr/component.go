@@ -2,6 +2,7 @@ package expr
 
 import (
 	"fmt"
+	"strings"
 )
 
 type (
@@ -9,6 +10,31 @@ type (
 	Component struct {
 		*Element
 		Container *Container
+		// CodeElements lists the code elements (e.g. classes) that
+		// implement the component.
+		CodeElements []*CodeElement
+		// Interfaces lists the interfaces exposed by the component.
+		Interfaces []*Interface
+	}
+
+	// Interface describes an interface exposed by a component, for example
+	// an API or a message queue. Relationships may target a specific
+	// interface of their destination component, see Relationship.Interface.
+	Interface struct {
+		// Name of the interface, unique within the component.
+		Name string
+		// Technology used to implement the interface if any.
+		Technology string
+	}
+
+	// CodeElement describes a code element that implements a component.
+	CodeElement struct {
+		// Name of the code element, e.g. the fully qualified class name.
+		Name string
+		// Type of the code element, one of CodeElementTypes.
+		Type string
+		// URL where the source code of the element can be found if any.
+		URL string
 	}
 
 	// Components is a slice of components that can be easily converted into
@@ -24,12 +50,54 @@ func (c *Component) EvalName() string {
 	return fmt.Sprintf("component %q", c.Name)
 }
 
-// Finalize adds the 'Component' tag ands finalizes relationships.
+// CodeElementTypes lists the types of code elements supported by Structurizr.
+var CodeElementTypes = []string{"Class", "Interface", "Enum", "Annotation"}
+
+// ResponsibilitiesProperty is the name of the property used to serialize the
+// component responsibilities.
+const ResponsibilitiesProperty = "Responsibilities"
+
+// InterfacesProperty is the name of the property used to serialize the
+// component interfaces.
+const InterfacesProperty = "Interfaces"
+
+// Finalize adds the 'Component' tag, records the responsibilities and the
+// interfaces in the properties and finalizes relationships.
 func (c *Component) Finalize() {
 	c.PrefixTags("Element", "Component")
+	if len(c.Responsibilities) > 0 {
+		if c.Properties == nil {
+			c.Properties = make(map[string]string)
+		}
+		c.Properties[ResponsibilitiesProperty] = strings.Join(c.Responsibilities, "\n")
+	}
+	if len(c.Interfaces) > 0 {
+		if c.Properties == nil {
+			c.Properties = make(map[string]string)
+		}
+		ifaces := make([]string, len(c.Interfaces))
+		for i, iface := range c.Interfaces {
+			ifaces[i] = iface.Name
+			if iface.Technology != "" {
+				ifaces[i] += " [" + iface.Technology + "]"
+			}
+		}
+		c.Properties[InterfacesProperty] = strings.Join(ifaces, "\n")
+	}
 	c.Element.Finalize()
 }
 
+// Interface returns the interface of the component with the given name, nil
+// if there isn't one.
+func (c *Component) Interface(name string) *Interface {
+	for _, iface := range c.Interfaces {
+		if iface.Name == name {
+			return iface
+		}
+	}
+	return nil
+}
+
 // Elements returns a slice of ElementHolder that contains the elements of c.
 func (cs Components) Elements() []ElementHolder {
 	res := make([]ElementHolder, len(cs))
@@ -2,6 +2,7 @@ package expr
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -68,3 +69,49 @@ func TestComponentsElements(t *testing.T) {
 		t.Errorf("got %d, want %d", len(got), len(components))
 	}
 }
+
+func TestComponentFromType(t *testing.T) {
+	t.Parallel()
+	type tagged struct {
+		_ struct{} `c4:"name=Order Service,desc=Manages orders,tech=Go,tags=Core|API"`
+	}
+	type noName struct {
+		_ struct{} `c4:"desc=Manages orders"`
+	}
+	type unknownKey struct {
+		_ struct{} `c4:"name=Order Service,owner=team"`
+	}
+	type untagged struct{ Name string }
+	tests := []struct {
+		name    string
+		typ     reflect.Type
+		want    *Element
+		wantErr bool
+	}{
+		{"tagged", reflect.TypeOf(tagged{}), &Element{Name: "Order Service", Description: "Manages orders", Technology: "Go", Tags: "Core,API"}, false},
+		{"pointer", reflect.TypeOf(&tagged{}), &Element{Name: "Order Service", Description: "Manages orders", Technology: "Go", Tags: "Core,API"}, false},
+		{"missing-name", reflect.TypeOf(noName{}), nil, true},
+		{"unknown-key", reflect.TypeOf(unknownKey{}), nil, true},
+		{"untagged", reflect.TypeOf(untagged{}), nil, true},
+		{"not-struct", reflect.TypeOf(""), nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ComponentFromType(tt.typ)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got no error, want one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != tt.want.Name || got.Description != tt.want.Description || got.Technology != tt.want.Technology || got.Tags != tt.want.Tags {
+				t.Errorf("got %+v, want %+v", got.Element, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package expr
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ComponentTag is the name of the struct tag read by ComponentFromType.
+const ComponentTag = "c4"
+
+// componentTagKeys lists the keys supported in ComponentTag struct tags and
+// whether they are required.
+var componentTagKeys = map[string]bool{
+	"name": true,
+	"desc": false,
+	"tech": false,
+	"tags": false,
+}
+
+// ComponentFromType creates a component from the ComponentTag struct tag of
+// the first field of the given struct type that defines one. The tag value is
+// a comma separated list of key=value pairs, the supported keys are:
+//
+//    * name: the name of the component (required),
+//    * desc: the description of the component,
+//    * tech: the technology of the component,
+//    * tags: the tags of the component separated with "|".
+//
+// Values may not contain commas. A blank field can be used to annotate the
+// type:
+//
+//    type OrderService struct {
+//        _ struct{} `c4:"name=Order Service,desc=Manages orders,tech=Go,tags=Core|API"`
+//    }
+//
+// ComponentFromType returns an error if the type is not a struct (or a pointer
+// to a struct), if it does not define the tag, if the tag contains unknown keys
+// or if a required key is missing. The component is not added to a container,
+// use Container.AddComponent to do so.
+func ComponentFromType(t reflect.Type) (*Component, error) {
+	if t == nil {
+		return nil, fmt.Errorf("ComponentFromType: type cannot be nil")
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("ComponentFromType: %s is not a struct type", t)
+	}
+	var tag string
+	for i := 0; i < t.NumField(); i++ {
+		if v, ok := t.Field(i).Tag.Lookup(ComponentTag); ok {
+			tag = v
+			break
+		}
+	}
+	if tag == "" {
+		return nil, fmt.Errorf("ComponentFromType: %s does not define a %q struct tag", t, ComponentTag)
+	}
+	vals := make(map[string]string)
+	for _, pair := range strings.Split(tag, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if _, ok := componentTagKeys[key]; !ok {
+			return nil, fmt.Errorf("ComponentFromType: %s: unknown %q tag key %q", t, ComponentTag, key)
+		}
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("ComponentFromType: %s: missing value for %q tag key %q", t, ComponentTag, key)
+		}
+		vals[key] = strings.TrimSpace(kv[1])
+	}
+	for key, required := range componentTagKeys {
+		if required && vals[key] == "" {
+			return nil, fmt.Errorf("ComponentFromType: %s: missing required %q tag key %q", t, ComponentTag, key)
+		}
+	}
+	elem := &Element{
+		Name:        vals["name"],
+		Description: vals["desc"],
+		Technology:  vals["tech"],
+	}
+	if tags := vals["tags"]; tags != "" {
+		elem.MergeTags(strings.Split(tags, "|")...)
+	}
+	return &Component{Element: elem}, nil
+}
@@ -0,0 +1,48 @@
+package expr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CostRollup sums the numeric property with the given key (e.g. a monthly
+// cost) up the model hierarchy: the total of a container is its own value
+// plus the totals of its components and the total of a software system is its
+// own value plus the totals of its containers. People only count their own
+// value. Values that are missing or that are not numbers count as zero.
+//
+// CostRollup returns the totals indexed by the fully qualified path of the
+// people, software systems, containers and components (see FindElement), for
+// example:
+//
+//    costs := m.CostRollup("monthly-cost")
+//    fmt.Println(costs["Billing"], costs["Billing/API"])
+//
+func (m *Model) CostRollup(key string) map[string]float64 {
+	value := func(e *Element) float64 {
+		f, err := strconv.ParseFloat(strings.TrimSpace(e.Properties[key]), 64)
+		if err != nil {
+			return 0
+		}
+		return f
+	}
+	res := make(map[string]float64)
+	for _, p := range m.People {
+		res[elementPath(p)] = value(p.Element)
+	}
+	for _, s := range m.Systems {
+		sysTotal := value(s.Element)
+		for _, c := range s.Containers {
+			total := value(c.Element)
+			for _, cmp := range c.Components {
+				v := value(cmp.Element)
+				res[elementPath(cmp)] = v
+				total += v
+			}
+			res[elementPath(c)] = total
+			sysTotal += total
+		}
+		res[elementPath(s)] = sysTotal
+	}
+	return res
+}
@@ -0,0 +1,40 @@
+package expr
+
+import "testing"
+
+func TestModelCostRollup(t *testing.T) {
+	t.Parallel()
+	const key = "monthly-cost"
+	var (
+		sys    = &SoftwareSystem{Element: &Element{Name: "Billing", Properties: map[string]string{key: "100"}}}
+		api    = &Container{Element: &Element{Name: "API", Properties: map[string]string{key: "50.5"}}, System: sys}
+		db     = &Container{Element: &Element{Name: "DB"}, System: sys}
+		orders = &Component{Element: &Element{Name: "Orders", Properties: map[string]string{key: "20"}}, Container: api}
+		ledger = &Component{Element: &Element{Name: "Ledger", Properties: map[string]string{key: " 9.5 "}}, Container: api}
+		notes  = &Component{Element: &Element{Name: "Notes", Properties: map[string]string{key: "n/a"}}, Container: api}
+		user   = &Person{Element: &Element{Name: "User"}}
+	)
+	sys.Containers = Containers{api, db}
+	api.Components = Components{orders, ledger, notes}
+	m := &Model{People: People{user}, Systems: SoftwareSystems{sys}}
+
+	got := m.CostRollup(key)
+
+	want := map[string]float64{
+		"User":               0,
+		"Billing":            180,
+		"Billing/API":        80,
+		"Billing/DB":         0,
+		"Billing/API/Orders": 20,
+		"Billing/API/Ledger": 9.5,
+		"Billing/API/Notes":  0,
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d totals, want %d: %v", len(got), len(want), got)
+	}
+	for path, total := range want {
+		if got[path] != total {
+			t.Errorf("got total %v for %q, want %v", got[path], path, total)
+		}
+	}
+}
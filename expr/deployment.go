@@ -2,6 +2,18 @@ package expr
 
 import (
 	"fmt"
+	"strconv"
+
+	"goa.design/goa/v3/eval"
+)
+
+const (
+	// ScalingMinProperty is the name of the container instance property
+	// that holds the minimum number of replicas.
+	ScalingMinProperty = "scaling.min"
+	// ScalingMaxProperty is the name of the container instance property
+	// that holds the maximum number of replicas.
+	ScalingMaxProperty = "scaling.max"
 )
 
 type (
@@ -216,6 +228,50 @@ func (ci *ContainerInstance) EvalName() string {
 	return fmt.Sprintf("instance %d of %s", ci.InstanceID, n)
 }
 
+// Validate makes sure the scaling properties, if any, are valid.
+func (ci *ContainerInstance) Validate() error {
+	verr := new(eval.ValidationErrors)
+	_, hasMin := ci.Properties[ScalingMinProperty]
+	_, hasMax := ci.Properties[ScalingMaxProperty]
+	if !hasMin && !hasMax {
+		return nil
+	}
+	min, max, ok := ci.Scaling()
+	switch {
+	case !ok:
+		verr.Add(ci, "scaling properties %q and %q must both be set to non-negative integers", ScalingMinProperty, ScalingMaxProperty)
+	case min > max:
+		verr.Add(ci, "scaling minimum %d is greater than maximum %d", min, max)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Scaling returns the minimum and maximum number of replicas of the container
+// instance as set with the "scaling.min" and "scaling.max" properties. ok is
+// false if the properties are not set or are not valid non-negative integers.
+func (ci *ContainerInstance) Scaling() (min, max int, ok bool) {
+	parse := func(name string) (int, bool) {
+		v, found := ci.Properties[name]
+		if !found {
+			return 0, false
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		return n, true
+	}
+	min, minOK := parse(ScalingMinProperty)
+	max, maxOK := parse(ScalingMaxProperty)
+	if !minOK || !maxOK {
+		return 0, 0, false
+	}
+	return min, max, true
+}
+
 // Finalize adds the "Container Instance" tag if not present.
 func (ci *ContainerInstance) Finalize() {
 	ci.PrefixTags("Container Instance")
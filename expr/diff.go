@@ -0,0 +1,284 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// ModelDiff describes the differences between two models. Elements and
+	// relationships are identified by their canonical names so that models
+	// built independently (e.g. from two revisions of a design) can be
+	// compared. The canonical name of an element is its path, e.g.
+	// "System/Container/Component". The path of deployment elements is
+	// prefixed with DiffDeploymentPrefix, e.g. "deployment:Production/Node"
+	// so that it cannot be mistaken for the path of a container. The
+	// canonical name of a relationship is "<source> -> <destination>" where
+	// source and destination are canonical element names. If the same
+	// elements are linked by multiple relationships the name also includes
+	// the normalized technology, e.g. "<source> -> <destination> [http]", and
+	// if that is not enough the position of the relationship among these,
+	// e.g. "<source> -> <destination> [http] #2".
+	ModelDiff struct {
+		// AddedElements lists the canonical names of the elements that
+		// only exist in the second model, sorted alphabetically.
+		AddedElements []string
+		// RemovedElements lists the canonical names of the elements that
+		// only exist in the first model, sorted alphabetically.
+		RemovedElements []string
+		// ChangedElements lists the elements that exist in both models
+		// but whose fields differ, sorted by name.
+		ChangedElements []*Change
+		// AddedRelationships lists the canonical names of the
+		// relationships that only exist in the second model, sorted
+		// alphabetically.
+		AddedRelationships []string
+		// RemovedRelationships lists the canonical names of the
+		// relationships that only exist in the first model, sorted
+		// alphabetically.
+		RemovedRelationships []string
+		// ChangedRelationships lists the relationships that exist in both
+		// models but whose fields differ, sorted by name.
+		ChangedRelationships []*Change
+	}
+
+	// Change describes the fields that differ between two versions of the
+	// same element or relationship.
+	Change struct {
+		// Name is the canonical name of the element or relationship.
+		Name string
+		// Fields lists the fields that differ.
+		Fields []*FieldChange
+	}
+
+	// FieldChange describes a field whose value differs.
+	FieldChange struct {
+		// Field is the name of the field: "description", "technology"
+		// or "tags".
+		Field string
+		// Old is the value in the first model.
+		Old string
+		// New is the value in the second model.
+		New string
+	}
+
+	// diffEntry is an element or relationship indexed by canonical name.
+	diffEntry struct {
+		Description string
+		Technology  string
+		Tags        string
+		// normalizedTechnology is the technology normalized with the
+		// technology normalizer of the model.
+		normalizedTechnology string
+	}
+)
+
+// DiffDeploymentPrefix prefixes the canonical names of deployment nodes,
+// infrastructure nodes and container instances in model diffs.
+const DiffDeploymentPrefix = "deployment:"
+
+// Diff compares the two models and returns the elements and relationships
+// that were added, removed or changed in b relative to a. Elements and
+// relationships are changed if their description, technology or tags
+// differ. Technologies are compared once normalized (see
+// Model.NormalizeTechnology) and tags regardless of their order.
+func Diff(a, b *Model) *ModelDiff {
+	aelems, arels := diffIndex(a)
+	belems, brels := diffIndex(b)
+	d := &ModelDiff{}
+	d.AddedElements, d.RemovedElements, d.ChangedElements = diffEntries(aelems, belems)
+	d.AddedRelationships, d.RemovedRelationships, d.ChangedRelationships = diffEntries(arels, brels)
+	return d
+}
+
+// Empty returns true if the diff does not contain any difference.
+func (d *ModelDiff) Empty() bool {
+	return len(d.AddedElements) == 0 && len(d.RemovedElements) == 0 && len(d.ChangedElements) == 0 &&
+		len(d.AddedRelationships) == 0 && len(d.RemovedRelationships) == 0 && len(d.ChangedRelationships) == 0
+}
+
+// String returns a human readable description of the diff, one line per
+// difference.
+func (d *ModelDiff) String() string {
+	var sb strings.Builder
+	list := func(prefix string, names []string) {
+		for _, n := range names {
+			sb.WriteString(prefix + " " + n + "\n")
+		}
+	}
+	changes := func(cs []*Change) {
+		for _, c := range cs {
+			for _, f := range c.Fields {
+				fmt.Fprintf(&sb, "~ %s: %s %q -> %q\n", c.Name, f.Field, f.Old, f.New)
+			}
+		}
+	}
+	list("+", d.AddedElements)
+	list("-", d.RemovedElements)
+	changes(d.ChangedElements)
+	list("+", d.AddedRelationships)
+	list("-", d.RemovedRelationships)
+	changes(d.ChangedRelationships)
+	return sb.String()
+}
+
+// diffIndex indexes the elements and relationships of m by canonical name.
+func diffIndex(m *Model) (elems, rels map[string]*diffEntry) {
+	elems = make(map[string]*diffEntry)
+	rels = make(map[string]*diffEntry)
+	if m == nil {
+		return
+	}
+	paths := make(map[string]string)
+	var all []*Element
+	add := func(e *Element, path string) {
+		paths[e.ID] = path
+		elems[path] = m.diffEntry(e.Description, e.Technology, e.Tags)
+		all = append(all, e)
+	}
+	for _, p := range m.People {
+		add(p.Element, p.Name)
+	}
+	for _, s := range m.Systems {
+		add(s.Element, s.Name)
+		for _, c := range s.Containers {
+			add(c.Element, s.Name+"/"+c.Name)
+			for _, cmp := range c.Components {
+				add(cmp.Element, s.Name+"/"+c.Name+"/"+cmp.Name)
+			}
+		}
+	}
+	var walk func(prefix string, nodes []*DeploymentNode)
+	walk = func(prefix string, nodes []*DeploymentNode) {
+		for _, n := range nodes {
+			path := prefix + "/" + n.Name
+			add(n.Element, path)
+			for _, i := range n.InfrastructureNodes {
+				add(i.Element, path+"/"+i.Name)
+			}
+			for _, ci := range n.ContainerInstances {
+				cpath, ok := paths[ci.ContainerID]
+				if !ok {
+					cpath = ci.Name
+				}
+				add(ci.Element, fmt.Sprintf("%s/%s#%d", path, cpath, ci.InstanceID))
+			}
+			walk(path, n.Children)
+		}
+	}
+	for _, n := range m.DeploymentNodes {
+		walk(DiffDeploymentPrefix+n.Environment, []*DeploymentNode{n})
+	}
+	type link struct {
+		name  string
+		entry *diffEntry
+	}
+	var links []*link
+	byEndpoints := make(map[string][]*link)
+	for _, e := range all {
+		for _, r := range m.ElementRelationships(e) {
+			dest := r.DestinationPath
+			if r.Destination != nil {
+				if p, ok := paths[r.Destination.ID]; ok {
+					dest = p
+				} else {
+					dest = r.Destination.Name
+				}
+			}
+			l := &link{name: paths[e.ID] + " -> " + dest, entry: m.diffEntry(r.Description, r.Technology, r.Tags)}
+			links = append(links, l)
+			byEndpoints[l.name] = append(byEndpoints[l.name], l)
+		}
+	}
+	for _, ls := range byEndpoints {
+		if len(ls) == 1 {
+			continue
+		}
+		byTech := make(map[string][]*link)
+		for _, l := range ls {
+			if tech := l.entry.normalizedTechnology; tech != "" {
+				l.name += " [" + tech + "]"
+			}
+			byTech[l.name] = append(byTech[l.name], l)
+		}
+		for _, same := range byTech {
+			for i, l := range same[1:] {
+				l.name += fmt.Sprintf(" #%d", i+2)
+			}
+		}
+	}
+	for _, l := range links {
+		rels[l.name] = l.entry
+	}
+	return
+}
+
+// diffEntry returns the diff entry for an element or relationship of m with
+// the given fields.
+func (m *Model) diffEntry(description, technology, tags string) *diffEntry {
+	return &diffEntry{
+		Description:          description,
+		Technology:           technology,
+		Tags:                 tags,
+		normalizedTechnology: m.NormalizeTechnology(technology),
+	}
+}
+
+// diffEntries compares the entries indexed by canonical names.
+func diffEntries(a, b map[string]*diffEntry) (added, removed []string, changed []*Change) {
+	for name, eb := range b {
+		ea, ok := a[name]
+		if !ok {
+			added = append(added, name)
+			continue
+		}
+		var fields []*FieldChange
+		if ea.Description != eb.Description {
+			fields = append(fields, &FieldChange{Field: "description", Old: ea.Description, New: eb.Description})
+		}
+		if ea.normalizedTechnology != eb.normalizedTechnology {
+			fields = append(fields, &FieldChange{Field: "technology", Old: ea.Technology, New: eb.Technology})
+		}
+		if !sameTags(ea.Tags, eb.Tags) {
+			fields = append(fields, &FieldChange{Field: "tags", Old: ea.Tags, New: eb.Tags})
+		}
+		if len(fields) > 0 {
+			changed = append(changed, &Change{Name: name, Fields: fields})
+		}
+	}
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Name < changed[j].Name })
+	return
+}
+
+// sameTags returns true if the two comma separated lists of tags contain the
+// same tags regardless of order and surrounding whitespace.
+func sameTags(a, b string) bool {
+	split := func(tags string) []string {
+		var res []string
+		for _, t := range strings.Split(tags, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				res = append(res, t)
+			}
+		}
+		sort.Strings(res)
+		return res
+	}
+	as, bs := split(a), split(b)
+	if len(as) != len(bs) {
+		return false
+	}
+	for i := range as {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
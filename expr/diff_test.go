@@ -0,0 +1,74 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	// model builds a model with a component "Shop/Production/Node" and a
+	// deployment node "Node" in the "Shop/Production" deployment environment
+	// so that their paths would collide without the deployment prefix.
+	model := func(description, technology, nodeDescription string, extra bool) *Model {
+		var (
+			user = &Person{Element: &Element{ID: "user", Name: "User"}}
+			shop = &SoftwareSystem{Element: &Element{ID: "shop", Name: "Shop"}}
+			prod = &Container{Element: &Element{ID: "prod", Name: "Production", Technology: technology}, System: shop}
+			node = &DeploymentNode{Element: &Element{ID: "node", Name: "Node", Description: nodeDescription}, Environment: "Shop/Production"}
+			cmp  = &Component{Element: &Element{ID: "cmp", Name: "Node", Description: "A component"}, Container: prod}
+		)
+		shop.Containers = Containers{prod}
+		prod.Components = Components{cmp}
+		user.Relationships = []*Relationship{
+			{Source: user.Element, Destination: shop.Element, Description: description, Technology: "HTTPS"},
+			{Source: user.Element, Destination: prod.Element, Description: "Reads", Technology: "SQL"},
+			{Source: user.Element, Destination: prod.Element, Description: "Writes", Technology: "SQL"},
+		}
+		if extra {
+			user.Relationships = append(user.Relationships,
+				&Relationship{Source: user.Element, Destination: prod.Element, Description: "Streams", Technology: "gRPC"})
+		}
+		return &Model{People: People{user}, Systems: SoftwareSystems{shop}, DeploymentNodes: []*DeploymentNode{node}}
+	}
+	tests := []struct {
+		name        string
+		a, b        *Model
+		wantAdded   []string
+		wantChanged []string
+	}{
+		{"identical", model("Buys", "Go", "A node", false), model("Buys", "Go", "A node", false), nil, nil},
+		{"deployment-path", model("Buys", "Go", "A node", false), model("Buys", "Go", "A server", false), nil,
+			[]string{`~ deployment:Shop/Production/Node: description "A node" -> "A server"`}},
+		{"relationship-description", model("Buys", "Go", "A node", false), model("Orders", "Go", "A node", false), nil,
+			[]string{`~ User -> Shop: description "Buys" -> "Orders"`}},
+		{"technology-case", model("Buys", "Go", "A node", false), model("Buys", " go", "A node", false), nil, nil},
+		{"element-technology", model("Buys", "Go", "A node", false), model("Buys", "Java", "A node", false), nil,
+			[]string{`~ Shop/Production: technology "Go" -> "Java"`}},
+		{"added-relationship", model("Buys", "Go", "A node", false), model("Buys", "Go", "A node", true), []string{"User -> Shop/Production [grpc]"}, nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := Diff(tt.a, tt.b)
+
+			if len(d.RemovedElements) != 0 || len(d.RemovedRelationships) != 0 || len(d.AddedElements) != 0 {
+				t.Errorf("got unexpected added or removed entries:\n%s", d)
+			}
+			if strings.Join(d.AddedRelationships, ", ") != strings.Join(tt.wantAdded, ", ") {
+				t.Errorf("got added relationships %v, want %v", d.AddedRelationships, tt.wantAdded)
+			}
+			var changed []string
+			for _, line := range strings.Split(strings.TrimSpace(d.String()), "\n") {
+				if strings.HasPrefix(line, "~") {
+					changed = append(changed, line)
+				}
+			}
+			if strings.Join(changed, "\n") != strings.Join(tt.wantChanged, "\n") {
+				t.Errorf("got changes:\n%s\nwant:\n%s", strings.Join(changed, "\n"), strings.Join(tt.wantChanged, "\n"))
+			}
+		})
+	}
+}
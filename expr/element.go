@@ -7,15 +7,28 @@ import (
 type (
 	// Element describes an element.
 	Element struct {
-		ID            string
-		Name          string
-		Description   string
-		Technology    string
-		Tags          string
-		URL           string
-		Properties    map[string]string
-		Relationships []*Relationship
-		DSLFunc       func()
+		ID               string
+		Name             string
+		Description      string
+		Technology       string
+		Tags             string
+		URL              string
+		Alias            string
+		Properties       map[string]string
+		Responsibilities []string
+		Relationships    []*Relationship
+		DSLFunc          func()
+		// DSLLocation is the file:line of the DSL that declared the
+		// element, empty if the element was not declared with the DSL.
+		DSLLocation string
+		// Size is the size in pixels used to render the element by
+		// exporters that compute their own layout (e.g. SVG or DOT), nil
+		// if unset. Structurizr ignores it.
+		Size *Dimensions
+		// Group is the path of the group the element belongs to if any.
+		// Nested groups are separated with GroupSeparator, e.g.
+		// "Team A/Backend".
+		Group string
 	}
 
 	// ElementHolder provides access to the underlying element.
@@ -36,6 +49,16 @@ const (
 	LocationExternal
 )
 
+// TagDeprecated is the tag added to deprecated elements, see Deprecate.
+const TagDeprecated = "Deprecated"
+
+// TagExternal is the tag added to people and software systems declared
+// external with the External DSL.
+const TagExternal = "External"
+
+// GroupSeparator separates the names of nested groups in group paths.
+const GroupSeparator = "/"
+
 // DSL returns the attached DSL.
 func (e *Element) DSL() func() { return e.DSLFunc }
 
@@ -46,9 +69,36 @@ func (e *Element) Finalize() {
 	}
 }
 
+// DescriptionWithResponsibilities returns the element description followed by
+// one line per responsibility. It is intended for exporters that do not
+// support lists.
+func (e *Element) DescriptionWithResponsibilities() string {
+	if len(e.Responsibilities) == 0 {
+		return e.Description
+	}
+	lines := make([]string, 0, len(e.Responsibilities)+1)
+	if e.Description != "" {
+		lines = append(lines, e.Description)
+	}
+	for _, r := range e.Responsibilities {
+		lines = append(lines, "- "+r)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // GetElement returns the underlying element.
 func (e *Element) GetElement() *Element { return e }
 
+// Deprecate marks the element as deprecated by adding the "Deprecated" tag.
+func (e *Element) Deprecate() {
+	e.MergeTags(TagDeprecated)
+}
+
+// Deprecated returns true if the element is tagged with "Deprecated".
+func (e *Element) Deprecated() bool {
+	return hasTag(e.Tags, TagDeprecated)
+}
+
 // MergeTags adds the given tags. It skips tags already present in e.Tags.
 func (e *Element) MergeTags(tags ...string) {
 	e.Tags = mergeTags(e.Tags, tags)
@@ -98,3 +148,13 @@ func mergeTags(existing string, tags []string) string {
 	}
 	return strings.Join(merged, ",")
 }
+
+// declaredAt returns the suffix added to validation error messages to indicate
+// where the offending element or relationship was declared given its DSL
+// location, empty if the location is unknown.
+func declaredAt(loc string) string {
+	if loc == "" {
+		return ""
+	}
+	return " (declared at " + loc + ")"
+}
@@ -0,0 +1,79 @@
+package expr
+
+type (
+	// elementIndex indexes the people, software systems, containers and
+	// components of a model by name so that FindElement does not need to
+	// scan the model for each lookup.
+	elementIndex struct {
+		people     map[string]*Person
+		systems    map[string]*SoftwareSystem
+		containers map[indexKey]*Container
+		components map[indexKey]*Component
+	}
+
+	// indexKey identifies a child element by its parent and its name.
+	indexKey struct {
+		parent *Element
+		name   string
+	}
+)
+
+// buildIndex indexes the elements of the model. The index is used by
+// FindElement until it is invalidated with invalidateIndex. When several
+// elements have the same name the first one is indexed so that lookups return
+// the same element as a linear scan would.
+func (m *Model) buildIndex() {
+	idx := &elementIndex{
+		people:     make(map[string]*Person, len(m.People)),
+		systems:    make(map[string]*SoftwareSystem, len(m.Systems)),
+		containers: make(map[indexKey]*Container),
+		components: make(map[indexKey]*Component),
+	}
+	for _, p := range m.People {
+		if _, ok := idx.people[p.Name]; !ok {
+			idx.people[p.Name] = p
+		}
+	}
+	for _, s := range m.Systems {
+		if _, ok := idx.systems[s.Name]; !ok {
+			idx.systems[s.Name] = s
+		}
+		for _, c := range s.Containers {
+			ck := indexKey{s.Element, c.Name}
+			if _, ok := idx.containers[ck]; !ok {
+				idx.containers[ck] = c
+			}
+			for _, cmp := range c.Components {
+				k := indexKey{c.Element, cmp.Name}
+				if _, ok := idx.components[k]; !ok {
+					idx.components[k] = cmp
+				}
+			}
+		}
+	}
+	m.index = idx
+}
+
+// invalidateIndex discards the element index, lookups scan the model again
+// until the index is rebuilt.
+func (m *Model) invalidateIndex() {
+	m.index = nil
+}
+
+// container returns the container of s with the given name if any, nil
+// otherwise.
+func (m *Model) container(s *SoftwareSystem, name string) *Container {
+	if m.index == nil {
+		return s.Container(name)
+	}
+	return m.index.containers[indexKey{s.Element, name}]
+}
+
+// component returns the component of c with the given name if any, nil
+// otherwise.
+func (m *Model) component(c *Container, name string) *Component {
+	if m.index == nil {
+		return c.Component(name)
+	}
+	return m.index.components[indexKey{c.Element, name}]
+}
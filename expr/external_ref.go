@@ -0,0 +1,52 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	// ExternalRefProperty is the name of the property that holds the URN of
+	// the element referenced by an external reference placeholder.
+	ExternalRefProperty = "urn"
+
+	// ExternalRefTag is the tag of the placeholder software systems created
+	// for external references.
+	ExternalRefTag = "External Reference"
+)
+
+// urnPattern matches URNs as defined by RFC 8141: "urn:", a namespace
+// identifier and a namespace specific string.
+var urnPattern = regexp.MustCompile(`(?i)^urn:[a-z0-9][a-z0-9-]{0,30}[a-z0-9]:[a-z0-9()+,\-.:=@;$_!*'%/?#~&]+$`)
+
+// ExternalRef returns the placeholder software system that stands for the
+// element with the given URN defined in another workspace. The placeholder is
+// created and added to the model the first time the URN is referenced. It is
+// named after the URN, is located outside of the enterprise, is tagged with
+// ExternalRefTag and records the URN in its ExternalRefProperty property so
+// that exporters render it as an external stub. ExternalRef returns an error
+// if urn is not a valid URN or if the model already defines a person or a
+// software system with the same name that is not a placeholder.
+func (m *Model) ExternalRef(urn string) (*SoftwareSystem, error) {
+	if !urnPattern.MatchString(urn) {
+		return nil, fmt.Errorf("invalid URN %q, URNs must be of the form \"urn:<namespace>:<name>\"", urn)
+	}
+	if s := m.SoftwareSystem(urn); s != nil {
+		if s.Properties[ExternalRefProperty] != urn {
+			return nil, fmt.Errorf("software system %q is not an external reference", urn)
+		}
+		return s, nil
+	}
+	if m.Person(urn) != nil {
+		return nil, fmt.Errorf("person %q is not an external reference", urn)
+	}
+	s := &SoftwareSystem{
+		Element: &Element{
+			Name:       urn,
+			Tags:       ExternalRefTag,
+			Properties: map[string]string{ExternalRefProperty: urn},
+		},
+		Location: LocationExternal,
+	}
+	return m.AddSystem(s), nil
+}
@@ -0,0 +1,47 @@
+package expr
+
+import "testing"
+
+func TestModelExternalRef(t *testing.T) {
+	const urn = "urn:acme:payments:gateway"
+	var (
+		billing = &SoftwareSystem{Element: &Element{Name: "ExternalRef Billing"}}
+		m       = &Model{}
+	)
+	m.AddSystem(billing)
+	defer func() {
+		for _, s := range m.Systems {
+			delete(Registry, s.ID)
+		}
+	}()
+
+	ref, err := m.ExternalRef(urn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	billing.Relationships = []*Relationship{{Source: billing.Element, Destination: ref.Element, Description: "Charges cards using"}}
+
+	if len(m.Systems) != 2 || m.Systems[1] != ref {
+		t.Fatalf("got %d software systems, want the billing system and the placeholder", len(m.Systems))
+	}
+	if ref.Name != urn || ref.Location != LocationExternal || ref.Tags != ExternalRefTag {
+		t.Errorf("got placeholder %q with location %d and tags %q, want %q, %d and %q", ref.Name, ref.Location, ref.Tags, urn, LocationExternal, ExternalRefTag)
+	}
+	if got := ref.Properties[ExternalRefProperty]; got != urn {
+		t.Errorf("got URN property %q, want %q", got, urn)
+	}
+	if _, ok := Registry[ref.ID]; !ok {
+		t.Errorf("placeholder is not registered")
+	}
+	if dest := billing.Relationships[0].Destination; dest != ref.Element {
+		t.Errorf("got relationship destination %q, want the placeholder", dest.Name)
+	}
+	if again, err := m.ExternalRef(urn); err != nil || again != ref {
+		t.Errorf("got %v, %v for the same URN, want the existing placeholder", again, err)
+	}
+	for _, invalid := range []string{"", "acme:payments", "urn:acme", "urn:-acme:x", "urn:acme:has space", "ExternalRef Billing"} {
+		if _, err := m.ExternalRef(invalid); err == nil {
+			t.Errorf("got no error for %q, want one", invalid)
+		}
+	}
+}
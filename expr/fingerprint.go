@@ -0,0 +1,103 @@
+package expr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Fingerprint returns a hash of the content of the model: the elements and
+// relationships, implied relationships included, with their properties. Two
+// models with the same content have the same fingerprint so that the
+// fingerprint can be used to detect that a model has not changed. The
+// fingerprint does not depend on the DSL locations. It covers the fields used
+// by exporters such as the element sizes and groups and the relationship
+// weights.
+func (m *Model) Fingerprint() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "m%q%t\n", m.Enterprise, m.AppendTechnologyToLabels)
+	for _, eh := range m.allElementHolders() {
+		e := eh.GetElement()
+		fmt.Fprintf(h, "e%q%q%q%q%q%q%q%q\n", e.ID, e.Name, e.Description, e.Technology, e.Tags, e.URL, e.Alias, e.Group)
+		hashJSON(h, "z", e.Size)
+		switch t := eh.(type) {
+		case *Person:
+			fmt.Fprintf(h, "l%d\n", t.Location)
+		case *SoftwareSystem:
+			fmt.Fprintf(h, "l%d\n", t.Location)
+		}
+		keys := make([]string, 0, len(e.Properties))
+		for k := range e.Properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "p%q%q\n", k, e.Properties[k])
+		}
+		for _, r := range e.Responsibilities {
+			fmt.Fprintf(h, "s%q\n", r)
+		}
+		for _, r := range m.ElementRelationships(e) {
+			var dest string
+			if r.Destination != nil {
+				dest = r.Destination.ID
+			}
+			fmt.Fprintf(h, "r%q%q%q%q%d%q%q%t%d%d%q\n", r.ID, dest, r.Description, r.Technology,
+				r.InteractionStyle, r.Tags, r.URL, r.Implied, r.Order, r.Weight, r.Interface)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Fingerprint returns a hash of the view properties that affect rendering:
+// the title and description, the element views with their positions and
+// styles, the relationship views with their vertices and routing, the
+// animation steps, the automatic layout, the paper size, the dimensions and
+// the legend, title and group flags.
+func (vp *ViewProps) Fingerprint() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "v%q%q%q%d%t%t%t\n", vp.Key, vp.Title, vp.Description, vp.PaperSize, vp.HideTitle, vp.ShowLegend, vp.HideEmptyGroups)
+	hashJSON(h, "g", vp.Legend)
+	hashJSON(h, "a", vp.AutoLayout)
+	hashJSON(h, "d", vp.Dimensions)
+	for _, ev := range vp.ElementViews {
+		var id string
+		if ev.Element != nil {
+			id = ev.Element.ID
+		}
+		fmt.Fprintf(h, "e%q%t\n", id, ev.NoRelationship)
+		hashJSON(h, "x", ev.X)
+		hashJSON(h, "y", ev.Y)
+		hashJSON(h, "s", ev.Style)
+	}
+	for _, rv := range vp.RelationshipViews {
+		fmt.Fprintf(h, "r%q%q%q%d%t%t\n", rv.RelationshipID, rv.Description, rv.Order, rv.Routing, rv.Response, rv.Return != nil)
+		hashJSON(h, "p", rv.Position)
+		hashJSON(h, "t", rv.Vertices)
+	}
+	for _, as := range vp.AnimationSteps {
+		fmt.Fprintf(h, "n%d%q\n", as.Order, as.RelationshipIDs)
+		for _, eh := range as.Elements {
+			fmt.Fprintf(h, "ne%q\n", eh.GetElement().ID)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Fingerprint returns a hash of the styles, s may be nil.
+func (s *Styles) Fingerprint() string {
+	h := sha256.New()
+	hashJSON(h, "", s)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashJSON writes prefix followed by the JSON encoding of v to w. The values
+// hashed this way only contain plain fields so that the encoding cannot fail
+// and is deterministic.
+func hashJSON(w io.Writer, prefix string, v interface{}) {
+	b, _ := json.Marshal(v)
+	fmt.Fprintf(w, "%s%s\n", prefix, b)
+}
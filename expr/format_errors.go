@@ -0,0 +1,204 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+)
+
+// declaredAtPattern matches the location suffix added by declaredAt to
+// validation error messages.
+var declaredAtPattern = regexp.MustCompile(`\s*\(declared at ([^)]+)\)`)
+
+// formattedIssue is a single issue rendered by FormatErrors.
+type formattedIssue struct {
+	severity SeverityKind
+	message  string
+	location string
+}
+
+// FormatErrors renders err in a form suitable for command line and continuous
+// integration output. Validation errors are grouped by element, relationship
+// or view, each group is introduced by the canonical name of the expression
+// (e.g. container "System/Container") and lists the issues indented below it
+// with their severity and, when known, the file and line where the
+// expression was declared:
+//
+//    container "Billing/API":
+//        error: name already in use
+//            at design.go:42
+//
+// Warnings (*Warning) are rendered with their severity. Errors that do not
+// originate from the model validation are rendered as a single ungrouped
+// issue. FormatErrors returns an empty string if err is nil.
+func FormatErrors(err error) string {
+	if err == nil {
+		return ""
+	}
+	var (
+		order  []string
+		groups = make(map[string][]*formattedIssue)
+	)
+	add := func(e eval.Expression, issue *formattedIssue) {
+		name := ""
+		if e != nil {
+			name = canonicalName(e)
+		}
+		if _, ok := groups[name]; !ok {
+			order = append(order, name)
+		}
+		groups[name] = append(groups[name], issue)
+	}
+	var collect func(err error, location string)
+	collect = func(err error, location string) {
+		switch actual := err.(type) {
+		case *eval.ValidationErrors:
+			for i, e := range actual.Errors {
+				var ex eval.Expression
+				if i < len(actual.Expressions) {
+					ex = actual.Expressions[i]
+				}
+				if w, ok := e.(*Warning); ok {
+					add(ex, newIssue(w.Severity, w.Message, location))
+					continue
+				}
+				add(ex, newIssue(SeverityError, e.Error(), location))
+			}
+		case eval.MultiError:
+			for _, e := range actual {
+				collect(e, "")
+			}
+		case *eval.Error:
+			loc := ""
+			if actual.File != "" {
+				loc = fmt.Sprintf("%s:%d", actual.File, actual.Line)
+			}
+			if actual.GoError != nil {
+				collect(actual.GoError, loc)
+			}
+		case *Warning:
+			add(actual.Expr, newIssue(actual.Severity, actual.Message, location))
+		default:
+			add(nil, newIssue(SeverityError, err.Error(), location))
+		}
+	}
+	collect(err, "")
+
+	var sb strings.Builder
+	for _, name := range order {
+		indent := ""
+		if name != "" {
+			sb.WriteString(name + ":\n")
+			indent = "    "
+		}
+		for _, issue := range groups[name] {
+			fmt.Fprintf(&sb, "%s%s: %s\n", indent, issue.severity, issue.message)
+			if issue.location != "" {
+				fmt.Fprintf(&sb, "%s    at %s\n", indent, issue.location)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// newIssue creates an issue from the given message, the location is extracted
+// from the message if it was added with declaredAt and defaults to loc
+// otherwise.
+func newIssue(severity SeverityKind, msg, loc string) *formattedIssue {
+	if m := declaredAtPattern.FindStringSubmatch(msg); m != nil {
+		msg = declaredAtPattern.ReplaceAllString(msg, "")
+		loc = m[1]
+	}
+	return &formattedIssue{severity: severity, message: msg, location: loc}
+}
+
+// canonicalName returns the name of the expression qualified with the names
+// of its parents, for example container "System/Container" or relationship
+// "Person -> System/Container: description". It defaults to the expression
+// EvalName for expressions that are not elements or relationships.
+func canonicalName(e eval.Expression) string {
+	switch actual := e.(type) {
+	case *Person:
+		return fmt.Sprintf("person %q", elementPath(actual))
+	case *SoftwareSystem:
+		return fmt.Sprintf("software system %q", elementPath(actual))
+	case *Container:
+		return fmt.Sprintf("container %q", elementPath(actual))
+	case *Component:
+		return fmt.Sprintf("component %q", elementPath(actual))
+	case *DeploymentNode:
+		return fmt.Sprintf("deployment node %q", elementPath(actual))
+	case *InfrastructureNode:
+		return fmt.Sprintf("infrastructure node %q", elementPath(actual))
+	case *ContainerInstance:
+		return fmt.Sprintf("container instance %q", elementPath(actual))
+	case *Relationship:
+		src, dest := "<unknown source>", actual.DestinationPath
+		if actual.Source != nil {
+			if src = elementPath(Registry[actual.Source.ID]); src == "" {
+				src = actual.Source.Name
+			}
+		}
+		if actual.Destination != nil {
+			if dest = elementPath(Registry[actual.Destination.ID]); dest == "" {
+				dest = actual.Destination.Name
+			}
+		}
+		if dest == "" {
+			dest = "<unknown destination>"
+		}
+		return fmt.Sprintf("relationship %q", src+" -> "+dest+": "+actual.Description)
+	default:
+		return e.EvalName()
+	}
+}
+
+// elementPath returns the name of the element prefixed with the names of its
+// parents separated with slashes. The path of deployment elements starts
+// with the name of the deployment environment.
+func elementPath(e interface{}) string {
+	switch actual := e.(type) {
+	case *Person:
+		return actual.Name
+	case *SoftwareSystem:
+		return actual.Name
+	case *Container:
+		if actual.System == nil {
+			return actual.Name
+		}
+		return actual.System.Name + "/" + actual.Name
+	case *Component:
+		if actual.Container == nil {
+			return actual.Name
+		}
+		return elementPath(actual.Container) + "/" + actual.Name
+	case *DeploymentNode:
+		if actual.Parent == nil {
+			return actual.Environment + "/" + actual.Name
+		}
+		return elementPath(actual.Parent) + "/" + actual.Name
+	case *InfrastructureNode:
+		if actual.Parent == nil {
+			return actual.Environment + "/" + actual.Name
+		}
+		return elementPath(actual.Parent) + "/" + actual.Name
+	case *ContainerInstance:
+		name := actual.Name
+		if actual.Container != nil {
+			name = elementPath(actual.Container)
+		} else if c, ok := Registry[actual.ContainerID].(*Container); ok {
+			name = elementPath(c)
+		}
+		name = fmt.Sprintf("%s#%d", name, actual.InstanceID)
+		if actual.Parent == nil {
+			return actual.Environment + "/" + name
+		}
+		return elementPath(actual.Parent) + "/" + name
+	case interface{ GetElement() *Element }:
+		return actual.GetElement().Name
+	default:
+		return ""
+	}
+}
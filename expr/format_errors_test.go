@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatErrors(t *testing.T) {
+	var (
+		sys   = &SoftwareSystem{Element: &Element{Name: "Format System"}}
+		api   = &Container{Element: &Element{Name: "API", DSLLocation: "design.go:10"}, System: sys}
+		dup   = &Container{Element: &Element{Name: "API", DSLLocation: "design.go:12"}, System: sys}
+		calls = &Relationship{Source: api.Element, DestinationPath: "Unknown", Description: "Calls", DSLLocation: "design.go:11"}
+	)
+	sys.Containers = Containers{api, dup}
+	api.Relationships = []*Relationship{calls}
+	for _, e := range []interface{}{sys, api, dup, calls} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{sys.ID, api.ID, dup.ID, calls.ID} {
+			delete(Registry, id)
+		}
+	}()
+	m := &Model{Systems: SoftwareSystems{sys}}
+
+	got := FormatErrors(m.Validate())
+
+	want := `container "Format System/API":
+    error: name already in use by container "Format System/API" (both have ID "dquscp"), rename one of them, e.g. to "API 2"
+        at design.go:12
+relationship "Format System/API -> Unknown: Calls":
+    error: "Unknown" does not match the name of a person, a software system or an element in the scope of "Format System"
+        at design.go:11
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"non-model", errors.New("boom"), "error: boom\n"},
+		{"warning", &Warning{Expr: sys, Message: "deprecated"}, "software system \"Format System\":\n    warning: deprecated\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := FormatErrors(c.err); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"path"
+	"path/filepath"
+)
+
+type (
+	// ImageView describes a view that renders an image (e.g. a PNG or SVG
+	// diagram produced by another tool) associated with an element.
+	ImageView struct {
+		Key         string
+		Title       string
+		Description string
+		// ElementID is the ID of the element the image is associated
+		// with.
+		ElementID string
+		// Content is the URL of the image if set with Content.
+		Content string
+		// ContentFile is the path to the image file if set with
+		// ContentFile.
+		ContentFile string
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (iv *ImageView) EvalName() string {
+	return fmt.Sprintf("image view %q", iv.Key)
+}
+
+// ContentData returns the content of the image and its MIME type. The content
+// is the image URL if the view content was set with Content or a base64 data
+// URI built from the content of the file otherwise.
+func (iv *ImageView) ContentData() (content, contentType string, err error) {
+	if iv.ContentFile == "" {
+		u, err := url.Parse(iv.Content)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid image URL %q: %s", iv.Content, err)
+		}
+		return iv.Content, mime.TypeByExtension(path.Ext(u.Path)), nil
+	}
+	b, err := ioutil.ReadFile(iv.ContentFile)
+	if err != nil {
+		return "", "", err
+	}
+	contentType = mime.TypeByExtension(filepath.Ext(iv.ContentFile))
+	if contentType == "" {
+		return "", "", fmt.Errorf("unknown image type for %q", iv.ContentFile)
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(b), contentType, nil
+}
@@ -0,0 +1,108 @@
+package expr
+
+import (
+	"sort"
+	"strings"
+)
+
+// LegendEntry maps a tag used by the elements or relationships of a view to
+// the style defined for it.
+type LegendEntry struct {
+	// Tag is the tag described by the entry.
+	Tag string
+	// Element is the style of the elements with the tag, nil if no element
+	// style is defined for the tag or if no element of the view has it.
+	Element *ElementStyle
+	// Relationship is the style of the relationships with the tag, nil if
+	// no relationship style is defined for the tag or if no relationship
+	// of the view has it.
+	Relationship *RelationshipStyle
+}
+
+// LegendEntries returns the entries of the tag legend of the view: one entry
+// for each tag of the elements and relationships of the view for which a
+// style is defined, sorted by tag. Styles defined multiple times for the same
+// tag are merged in definition order. Styles using required and excluded tags
+// are not listed. Exporters that do not draw a legend of their own may use
+// LegendEntries to render one when ShowLegend is set.
+func (vp *ViewProps) LegendEntries() []*LegendEntry {
+	if Root.Views == nil || Root.Views.Styles == nil {
+		return nil
+	}
+	styles := Root.Views.Styles
+	elemTags := make(map[string]bool)
+	for _, ev := range vp.ElementViews {
+		for _, tag := range strings.Split(ev.Element.Tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				elemTags[tag] = true
+			}
+		}
+	}
+	relTags := make(map[string]bool)
+	for _, rv := range vp.RelationshipViews {
+		r, ok := Registry[rv.RelationshipID].(*Relationship)
+		if !ok {
+			continue
+		}
+		for _, tag := range strings.Split(r.Tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				relTags[tag] = true
+			}
+		}
+	}
+
+	entries := make(map[string]*LegendEntry)
+	entry := func(tag string) *LegendEntry {
+		e, ok := entries[tag]
+		if !ok {
+			e = &LegendEntry{Tag: tag}
+			entries[tag] = e
+		}
+		return e
+	}
+	for _, es := range styles.Elements {
+		if es.Compound() || !elemTags[es.Tag] {
+			continue
+		}
+		e := entry(es.Tag)
+		if e.Element == nil {
+			e.Element = &ElementStyle{Tag: es.Tag}
+		}
+		e.Element.merge(es)
+	}
+	for _, rs := range styles.Relationships {
+		if rs.Compound() || !relTags[rs.Tag] {
+			continue
+		}
+		e := entry(rs.Tag)
+		if e.Relationship == nil {
+			e.Relationship = &RelationshipStyle{Tag: rs.Tag}
+		}
+		s := e.Relationship
+		if rs.Thick != nil {
+			s.Thick = rs.Thick
+		}
+		if rs.Color != "" {
+			s.Color = rs.Color
+		}
+		if rs.Stroke != "" {
+			s.Stroke = rs.Stroke
+		}
+		if rs.Dashed != nil {
+			s.Dashed = rs.Dashed
+		}
+		if rs.Routing != RoutingUndefined {
+			s.Routing = rs.Routing
+		}
+		if rs.Opacity != nil {
+			s.Opacity = rs.Opacity
+		}
+	}
+
+	res := make([]*LegendEntry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Tag < res[j].Tag })
+	return res
+}
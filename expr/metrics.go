@@ -0,0 +1,252 @@
+package expr
+
+import "sort"
+
+type (
+	// ModelMetrics is a snapshot of the size and connectivity of a model.
+	ModelMetrics struct {
+		// People is the number of people in the model.
+		People int
+		// Systems is the number of software systems in the model.
+		Systems int
+		// Containers is the number of containers in the model.
+		Containers int
+		// Components is the number of components in the model.
+		Components int
+		// DeploymentNodes is the number of deployment nodes in the model
+		// including nested nodes.
+		DeploymentNodes int
+		// InfrastructureNodes is the number of infrastructure nodes in the
+		// model.
+		InfrastructureNodes int
+		// ContainerInstances is the number of container instances in the
+		// model.
+		ContainerInstances int
+		// Relationships is the number of relationships in the model.
+		// Implied relationships and relationships replicated onto
+		// container instances are not counted.
+		Relationships int
+		// AvgContainersPerSystem is the average number of containers per
+		// software system.
+		AvgContainersPerSystem float64
+		// Elements lists the fan-in and fan-out of each element in model
+		// order: people, software systems, containers, components then
+		// deployment nodes, infrastructure nodes and container instances.
+		Elements []*ElementMetrics
+	}
+
+	// ElementMetrics describes the connectivity of a single element.
+	ElementMetrics struct {
+		// Element is the element being measured.
+		Element *Element
+		// FanIn is the number of relationships with the element as
+		// destination.
+		FanIn int
+		// FanOut is the number of relationships with the element as
+		// source.
+		FanOut int
+		// WeightedFanIn is the sum of the weights of the relationships
+		// with the element as destination, see
+		// Relationship.EffectiveWeight.
+		WeightedFanIn int
+		// WeightedFanOut is the sum of the weights of the relationships
+		// with the element as source.
+		WeightedFanOut int
+		// Systems is the number of distinct software systems a person
+		// interacts with: the systems the person uses or that deliver to
+		// the person either directly or through their containers and
+		// components. Systems is always zero for other elements.
+		Systems int
+	}
+)
+
+// Metrics computes the metrics of the model. The result is deterministic for a
+// given model. Implied relationships and relationships replicated onto
+// container instances are not counted so that the fan-in and fan-out match
+// FanInOut.
+func (m *Model) Metrics() ModelMetrics {
+	res := ModelMetrics{
+		People:  len(m.People),
+		Systems: len(m.Systems),
+	}
+	elems := m.allElements()
+	byElem := make(map[*Element]*ElementMetrics, len(elems))
+	for _, e := range elems {
+		em := &ElementMetrics{Element: e}
+		res.Elements = append(res.Elements, em)
+		byElem[e] = em
+	}
+	for _, e := range elems {
+		for _, r := range m.ElementRelationships(e) {
+			if !measured(r) {
+				continue
+			}
+			res.Relationships++
+			byElem[e].FanOut++
+			byElem[e].WeightedFanOut += r.EffectiveWeight()
+			if em, ok := byElem[r.Destination]; ok {
+				em.FanIn++
+				em.WeightedFanIn += r.EffectiveWeight()
+			}
+		}
+	}
+	systemOf := make(map[*Element]*Element)
+	for _, s := range m.Systems {
+		systemOf[s.Element] = s.Element
+		for _, c := range s.Containers {
+			systemOf[c.Element] = s.Element
+			for _, cmp := range c.Components {
+				systemOf[cmp.Element] = s.Element
+			}
+		}
+	}
+	interactions := make(map[*Element]map[*Element]bool)
+	for _, p := range m.People {
+		interactions[p.Element] = make(map[*Element]bool)
+	}
+	for _, e := range elems {
+		for _, r := range m.ElementRelationships(e) {
+			if !measured(r) {
+				continue
+			}
+			if sys, ok := interactions[r.Source]; ok && systemOf[r.Destination] != nil {
+				sys[systemOf[r.Destination]] = true
+			}
+			if sys, ok := interactions[r.Destination]; ok && systemOf[r.Source] != nil {
+				sys[systemOf[r.Source]] = true
+			}
+		}
+	}
+	for _, p := range m.People {
+		byElem[p.Element].Systems = len(interactions[p.Element])
+	}
+	for _, s := range m.Systems {
+		res.Containers += len(s.Containers)
+		for _, c := range s.Containers {
+			res.Components += len(c.Components)
+		}
+	}
+	if res.Systems > 0 {
+		res.AvgContainersPerSystem = float64(res.Containers) / float64(res.Systems)
+	}
+	var countNodes func([]*DeploymentNode)
+	countNodes = func(nodes []*DeploymentNode) {
+		for _, n := range nodes {
+			res.DeploymentNodes++
+			res.InfrastructureNodes += len(n.InfrastructureNodes)
+			res.ContainerInstances += len(n.ContainerInstances)
+			countNodes(n.Children)
+		}
+	}
+	countNodes(m.DeploymentNodes)
+	return res
+}
+
+// FanInOut returns the number of relationships with e as destination (in) and
+// with e as source (out). Implied relationships and relationships replicated
+// onto container instances are not counted.
+func (m *Model) FanInOut(e *Element) (in, out int) {
+	for _, src := range m.allElements() {
+		for _, r := range m.ElementRelationships(src) {
+			if !measured(r) {
+				continue
+			}
+			if r.Source == e {
+				out++
+			}
+			if r.Destination == e {
+				in++
+			}
+		}
+	}
+	return
+}
+
+// Hotspots returns up to n elements with the highest combined fan-in and
+// fan-out as computed by FanInOut, most connected first. Elements with the
+// same count are returned in model order and elements with no relationship
+// are omitted.
+func (m *Model) Hotspots(n int) []*Element {
+	elems := m.allElements()
+	counts := make(map[*Element]int, len(elems))
+	for _, src := range elems {
+		for _, r := range m.ElementRelationships(src) {
+			if !measured(r) {
+				continue
+			}
+			counts[src]++
+			if r.Destination != nil {
+				counts[r.Destination]++
+			}
+		}
+	}
+	var res []*Element
+	for _, e := range elems {
+		if counts[e] > 0 {
+			res = append(res, e)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return counts[res[i]] > counts[res[j]] })
+	if n >= 0 && len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
+// measured returns true if r is counted by Metrics, FanInOut and Hotspots:
+// implied relationships and relationships replicated onto container instances
+// are not.
+func measured(r *Relationship) bool {
+	return !r.Implied && r.LinkedRelationshipID == ""
+}
+
+// allElements returns all the elements of the model in model order: people,
+// software systems, containers, components then deployment nodes,
+// infrastructure nodes and container instances.
+func (m *Model) allElements() []*Element {
+	holders := m.allElementHolders()
+	elems := make([]*Element, len(holders))
+	for i, eh := range holders {
+		elems[i] = eh.GetElement()
+	}
+	return elems
+}
+
+// allElementHolders returns all the elements of the model in the same order
+// as allElements.
+func (m *Model) allElementHolders() []ElementHolder {
+	var elems []ElementHolder
+	for _, p := range m.People {
+		elems = append(elems, p)
+	}
+	for _, s := range m.Systems {
+		elems = append(elems, s)
+	}
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			elems = append(elems, c)
+		}
+	}
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			for _, cmp := range c.Components {
+				elems = append(elems, cmp)
+			}
+		}
+	}
+	var deployment func([]*DeploymentNode)
+	deployment = func(nodes []*DeploymentNode) {
+		for _, n := range nodes {
+			elems = append(elems, n)
+			for _, i := range n.InfrastructureNodes {
+				elems = append(elems, i)
+			}
+			for _, ci := range n.ContainerInstances {
+				elems = append(elems, ci)
+			}
+			deployment(n.Children)
+		}
+	}
+	deployment(m.DeploymentNodes)
+	return elems
+}
@@ -0,0 +1,100 @@
+package expr
+
+import "testing"
+
+func TestModelMetrics(t *testing.T) {
+	t.Parallel()
+	var (
+		user  = &Person{Element: &Element{Name: "User"}}
+		sysA  = &SoftwareSystem{Element: &Element{Name: "A"}}
+		sysB  = &SoftwareSystem{Element: &Element{Name: "B"}}
+		contA = &Container{Element: &Element{Name: "API"}, System: sysA}
+		contB = &Container{Element: &Element{Name: "DB"}, System: sysA}
+		cmp   = &Component{Element: &Element{Name: "Handler"}, Container: contA}
+		node  = &DeploymentNode{Element: &Element{Name: "Node"}}
+		ci    = &ContainerInstance{Element: &Element{}, Container: contA, Parent: node}
+	)
+	sysA.Containers = Containers{contA, contB}
+	contA.Components = Components{cmp}
+	node.ContainerInstances = []*ContainerInstance{ci}
+	user.Relationships = []*Relationship{
+		{Source: user.Element, Destination: sysA.Element},
+		{Source: user.Element, Destination: sysB.Element},
+		{Source: user.Element, Destination: contA.Element},
+	}
+	contB.Relationships = []*Relationship{{Source: contB.Element, Destination: user.Element}}
+	sysA.Relationships = []*Relationship{{Source: sysA.Element, Destination: sysB.Element}}
+	cmp.Relationships = []*Relationship{{Source: cmp.Element, Destination: contB.Element}}
+	contA.Relationships = []*Relationship{{Source: contA.Element, Destination: contB.Element, Implied: true}}
+	ci.Relationships = []*Relationship{{Source: ci.Element, Destination: ci.Element, LinkedRelationshipID: "linked"}}
+	m := &Model{
+		People:          People{user},
+		Systems:         SoftwareSystems{sysA, sysB},
+		DeploymentNodes: []*DeploymentNode{node},
+	}
+
+	got := m.Metrics()
+
+	counts := []struct {
+		name      string
+		got, want int
+	}{
+		{"people", got.People, 1},
+		{"systems", got.Systems, 2},
+		{"containers", got.Containers, 2},
+		{"components", got.Components, 1},
+		{"deployment nodes", got.DeploymentNodes, 1},
+		{"infrastructure nodes", got.InfrastructureNodes, 0},
+		{"container instances", got.ContainerInstances, 1},
+		{"relationships", got.Relationships, 6},
+		{"elements", len(got.Elements), 8},
+	}
+	for _, c := range counts {
+		if c.got != c.want {
+			t.Errorf("%s: got %d, want %d", c.name, c.got, c.want)
+		}
+	}
+	if got.AvgContainersPerSystem != 1 {
+		t.Errorf("average containers per system: got %f, want 1", got.AvgContainersPerSystem)
+	}
+
+	fans := []struct {
+		elem          *Element
+		fanIn, fanOut int
+	}{
+		{user.Element, 1, 3},
+		{sysA.Element, 1, 1},
+		{sysB.Element, 2, 0},
+		{contA.Element, 1, 0},
+		{contB.Element, 1, 1},
+		{cmp.Element, 0, 1},
+		{ci.Element, 0, 0},
+	}
+	for _, f := range fans {
+		var em *ElementMetrics
+		for _, e := range got.Elements {
+			if e.Element == f.elem {
+				em = e
+			}
+		}
+		if em == nil {
+			t.Errorf("%q: missing metrics", f.elem.Name)
+			continue
+		}
+		if em.FanIn != f.fanIn || em.FanOut != f.fanOut {
+			t.Errorf("%q: got fan-in/out %d/%d, want %d/%d", f.elem.Name, em.FanIn, em.FanOut, f.fanIn, f.fanOut)
+		}
+		if in, out := m.FanInOut(f.elem); in != em.FanIn || out != em.FanOut {
+			t.Errorf("%q: got FanInOut %d/%d, want %d/%d as computed by Metrics", f.elem.Name, in, out, em.FanIn, em.FanOut)
+		}
+	}
+	if got.Elements[0].Element != user.Element {
+		t.Errorf("got first element %q, want %q", got.Elements[0].Element.Name, user.Name)
+	}
+	if got.Elements[0].Systems != 2 {
+		t.Errorf("%q: got %d systems interacted with, want 2", user.Name, got.Elements[0].Systems)
+	}
+	if got.Elements[1].Systems != 0 {
+		t.Errorf("%q: got %d systems interacted with, want 0", sysA.Name, got.Elements[1].Systems)
+	}
+}
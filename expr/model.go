@@ -2,7 +2,10 @@ package expr
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"goa.design/goa/v3/eval"
 )
@@ -15,81 +18,371 @@ type (
 		Systems                 SoftwareSystems
 		DeploymentNodes         []*DeploymentNode
 		AddImpliedRelationships bool
+
+		// ImpliedMode controls which implied relationships are added
+		// when AddImpliedRelationships is true.
+		ImpliedMode ImpliedModeKind
+
+		// SeparateImpliedRelationships causes implied relationships to be
+		// recorded in ImpliedRelationships rather than added to the
+		// source element relationships.
+		SeparateImpliedRelationships bool
+		// ImpliedRelationships lists the implied relationships when
+		// SeparateImpliedRelationships is true.
+		ImpliedRelationships []*Relationship
+
+		// DescriptionMaxLength is the maximum length of element
+		// descriptions, DefaultDescriptionMaxLength if zero.
+		DescriptionMaxLength int
+		// SkipDescriptionLength disables the description length check.
+		SkipDescriptionLength bool
+
+		// AppendTechnologyToLabels causes the Mermaid, SVG and DOT
+		// exporters to append the relationship technology in brackets to
+		// relationship labels (see Relationship.Label). The Structurizr
+		// serializer ignores it as Structurizr renders the technology
+		// separately.
+		AppendTechnologyToLabels bool
+
+		// DefaultTechnology is the technology given to containers and
+		// components that do not define one explicitly, none if empty.
+		DefaultTechnology string
+
+		// DescriptionTemplate is the template used to compute the
+		// description of templated relationships that have no description.
+		// The "{source}" and "{destination}" placeholders are replaced with
+		// the names of the relationship source and destination.
+		DescriptionTemplate string
+
+		// PlaceholderPatterns lists the case insensitive patterns that
+		// should not appear as whole words in element and relationship
+		// descriptions and technologies, DefaultPlaceholderPatterns if nil.
+		PlaceholderPatterns []string
+
+		// TechnologyNormalizer normalizes technology names before they are
+		// compared or aggregated, DefaultTechnologyNormalizer if nil.
+		TechnologyNormalizer func(string) string
+
+		// PersonNameConvention, SystemNameConvention,
+		// ContainerNameConvention and ComponentNameConvention are the
+		// patterns that the names of the corresponding elements must match
+		// if not nil.
+		PersonNameConvention    *regexp.Regexp
+		SystemNameConvention    *regexp.Regexp
+		ContainerNameConvention *regexp.Regexp
+		ComponentNameConvention *regexp.Regexp
+
+		// RequireRelationshipTechnology causes Validate to report an
+		// error for each relationship between containers or components
+		// that does not define a technology.
+		RequireRelationshipTechnology bool
+
+		// NormalizeNames causes Validate to record a warning for each
+		// element whose name has leading or trailing whitespace and for
+		// each element whose name only differs from the name of another
+		// element in the same scope by case or whitespace. Validate does
+		// not modify the names.
+		NormalizeNames bool
+
+		// WarnDuplicateUses causes Validate to record a warning for each
+		// relationship declared more than once with the same source,
+		// destination and description in the same file.
+		WarnDuplicateUses bool
+
+		// WarnUnstyledRelationshipTags causes Validate to record a
+		// warning for each tag of a relationship that no relationship
+		// style applies to.
+		WarnUnstyledRelationshipTags bool
+
+		// PrefixIDs causes Identify to prefix the IDs of elements and
+		// relationships with their type, e.g. "sys-" or "rel-".
+		PrefixIDs bool
+
+		// WarningsAreErrors causes Validate to fail if any warning is
+		// recorded, the warnings are then reported as errors.
+		WarningsAreErrors bool
+
+		// Warnings lists the non fatal issues found by Validate.
+		Warnings []*Warning
+
+		// index speeds up FindElement during validation, nil otherwise.
+		index *elementIndex
 	}
+
+	// ImpliedModeKind is the enum for the implied relationships modes.
+	ImpliedModeKind int
 )
 
+const (
+	// ImpliedFull adds implied relationships between all the parents of
+	// the relationship source and destination.
+	ImpliedFull ImpliedModeKind = iota
+	// ImpliedDirectParentOnly only adds implied relationships from the
+	// direct parent of the source to the destination and from the source
+	// to the direct parent of the destination. For example a relationship
+	// between two components implies relationships involving their
+	// containers but not their software systems.
+	ImpliedDirectParentOnly
+)
+
+// DefaultDescriptionMaxLength is the default maximum length of element
+// descriptions. Longer descriptions get truncated when rendered by the
+// Structurizr service.
+const DefaultDescriptionMaxLength = 256
+
+// descriptionTemplatePlaceholder matches the placeholders of
+// DescriptionTemplate.
+var descriptionTemplatePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// DefaultPlaceholderPatterns lists the patterns used to detect scaffolding
+// text left in descriptions and technologies when Model.PlaceholderPatterns is
+// nil.
+var DefaultPlaceholderPatterns = []string{"TODO", "FIXME"}
+
 // Parent returns the parent scope for the given element, nil if eh is a Person
-// or SoftwareSystem.
-func Parent(eh ElementHolder) ElementHolder {
+// or SoftwareSystem. Parent returns an error if eh is not a person, a software
+// system, a container or a component.
+func Parent(eh ElementHolder) (ElementHolder, error) {
 	switch e := eh.(type) {
 	case *SoftwareSystem, *Person:
-		return nil
+		return nil, nil
 	case *Container:
-		return e.System
+		return e.System, nil
 	case *Component:
-		return e.Container
+		return e.Container, nil
 	default:
-		panic(fmt.Sprintf("unknown element type %T", e)) // bug
+		return nil, fmt.Errorf("elements of type %T cannot be the source of relationships whose destination is given by path", e)
 	}
 }
 
 // EvalName is the qualified name of the DSL expression.
 func (m *Model) EvalName() string { return "model" }
 
-// Validate makes sure all element names are unique.
+// Validate makes sure that element names and aliases are unique, resolves the
+// relationship destinations given by path and checks the naming conventions,
+// placeholders, deployment environments, interfaces and groups as well as any
+// check enabled by the model options (e.g. RequireRelationshipTechnology).
+// Validate records non fatal issues in Warnings, the warnings are reported as
+// errors if WarningsAreErrors is true.
 func (m *Model) Validate() error {
 	verr := new(eval.ValidationErrors)
-	known := make(map[string]struct{})
+	m.Warnings = nil
+	if m.NormalizeNames {
+		m.validateNormalizedNames()
+	}
+	m.buildIndex()
+	defer m.invalidateIndex()
+	known := make(map[string]ElementHolder)
 	for _, p := range m.People {
-		if _, ok := known[p.Name]; ok {
-			verr.Add(p, "name already in use")
+		if other, ok := known[p.Name]; ok {
+			verr.Add(p, "%s%s", duplicateName(p, other, known), declaredAt(p.DSLLocation))
+			continue
 		}
-		known[p.Name] = struct{}{}
+		known[p.Name] = p
 	}
 	for _, s := range m.Systems {
-		if _, ok := known[s.Name]; ok {
-			verr.Add(s, "name already in use")
+		if other, ok := known[s.Name]; ok {
+			verr.Add(s, "%s%s", duplicateName(s, other, known), declaredAt(s.DSLLocation))
+		} else {
+			known[s.Name] = s
 		}
-		known[s.Name] = struct{}{}
-		containers := make(map[string]struct{})
+		containers := make(map[string]ElementHolder)
 		for _, c := range s.Containers {
-			if _, ok := containers[c.Name]; ok {
-				verr.Add(c, "name already in use")
+			if other, ok := containers[c.Name]; ok {
+				verr.Add(c, "%s%s", duplicateName(c, other, containers), declaredAt(c.DSLLocation))
+			} else {
+				containers[c.Name] = c
 			}
-			containers[c.Name] = struct{}{}
-			components := make(map[string]struct{})
+			components := make(map[string]ElementHolder)
 			for _, cm := range c.Components {
-				if _, ok := components[cm.Name]; ok {
-					verr.Add(cm, "name already in use")
+				if other, ok := components[cm.Name]; ok {
+					verr.Add(cm, "%s%s", duplicateName(cm, other, components), declaredAt(cm.DSLLocation))
+					continue
 				}
-				components[cm.Name] = struct{}{}
+				components[cm.Name] = cm
 			}
 		}
 	}
 
+	// Make sure aliases are unique and do not shadow element names.
+	// Container instances are named after their container.
+	names := make(map[string]bool)
+	Iterate(func(e interface{}) {
+		if _, ok := e.(*ContainerInstance); ok {
+			return
+		}
+		if eh, ok := e.(ElementHolder); ok {
+			names[eh.GetElement().Name] = true
+		}
+	})
+	aliases := make(map[string]*Element)
+	Iterate(func(e interface{}) {
+		eh, ok := e.(ElementHolder)
+		if !ok || eh.GetElement().Alias == "" {
+			return
+		}
+		elem := eh.GetElement()
+		if other, ok := aliases[elem.Alias]; ok {
+			verr.Add(e.(eval.Expression), "alias %q already used by %q%s", elem.Alias, other.Name, declaredAt(elem.DSLLocation))
+			return
+		}
+		if elem.Alias != elem.Name && names[elem.Alias] {
+			verr.Add(e.(eval.Expression), "alias %q is the name of another element%s", elem.Alias, declaredAt(elem.DSLLocation))
+			return
+		}
+		aliases[elem.Alias] = elem
+	})
+
 	// Finalize all relationship destination now that the DSL has been executed.
 	IterateRelationships(func(r *Relationship) {
+		if r.Weight < 0 {
+			verr.AddError(r, fmt.Errorf("weight cannot be negative, got %d%s", r.Weight, declaredAt(r.DSLLocation)))
+		}
 		if r.Destination != nil {
 			return
 		}
 		// Relationship was created with Uses and used one or more strings to
 		// identify the destination.
-		eh, err := m.FindElement(Parent(Registry[r.Source.ID].(ElementHolder)), r.DestinationPath)
+		src, _ := Registry[r.Source.ID].(ElementHolder)
+		scope, err := Parent(src)
 		if err != nil {
-			verr.AddError(r, err)
+			verr.AddError(r, fmt.Errorf("%s%s", err, declaredAt(r.DSLLocation)))
+			return
+		}
+		eh, err := m.FindElement(scope, r.DestinationPath)
+		if err != nil {
+			verr.AddError(r, fmt.Errorf("%s%s", err, declaredAt(r.DSLLocation)))
 			return
 		}
 		r.Destination = eh.GetElement()
 	})
 
+	m.validateDescriptions()
+	m.validateDeprecated()
+	m.validateLocations()
+	if m.WarnDuplicateUses {
+		m.validateDuplicateUses()
+	}
+	if m.WarnUnstyledRelationshipTags {
+		m.validateRelationshipTagStyles()
+	}
+	m.validateStyleConflicts()
+	m.validatePlaceholders()
+	m.validateNameConventions(verr)
+	m.validateEnterprises(verr)
+	m.validateDeploymentEnvironments(verr)
+	m.validateInstanceRelationships(verr)
+	m.validateNodeRelationships(verr)
+	m.validateDescriptionTemplate(verr)
+	m.validateInterfaces(verr)
+	m.validateGroups(verr)
+	if m.RequireRelationshipTechnology {
+		m.validateRelationshipTechnology(verr)
+	}
+	m.escalateWarnings(verr)
+
+	return verr
+}
+
+// Check runs the same validations as the DSL evaluation: the model
+// validations, including the resolution of relationship destinations, the
+// validations of the registered elements and relationships and, if m is the
+// design model, the validations of the design views. Check leaves the design
+// untouched: the relationship destinations resolved, the relationship views
+// mapped to relationships and the warnings recorded during the validation are
+// reverted once done. Check does not finalize the model so that implied
+// relationships are not added. Check returns nil if the design is valid and
+// the validation errors otherwise. Check makes it possible to validate a
+// design without side effects, for example in continuous integration checks.
+func (m *Model) Check() error {
+	warnings := m.Warnings
+	var unresolved []*Relationship
+	IterateRelationships(func(r *Relationship) {
+		if r.Destination == nil {
+			unresolved = append(unresolved, r)
+		}
+	})
+	var views *Views
+	if Root.Model == m {
+		views = Root.Views
+	}
+	mapped := make(map[*RelationshipView]string)
+	if views != nil {
+		for _, v := range views.All() {
+			for _, rv := range v.Props().RelationshipViews {
+				mapped[rv] = rv.RelationshipID
+			}
+		}
+	}
+	defer func() {
+		m.Warnings = warnings
+		for _, r := range unresolved {
+			r.Destination = nil
+		}
+		for rv, id := range mapped {
+			rv.RelationshipID = id
+		}
+	}()
+	verr := new(eval.ValidationErrors)
+	if err := m.Validate(); err != nil {
+		verr.AddError(m, err)
+	}
+	Iterate(func(e interface{}) {
+		if v, ok := e.(eval.Validator); ok {
+			if err := v.Validate(); err != nil {
+				verr.AddError(e.(eval.Expression), err)
+			}
+		}
+	})
+	if views != nil {
+		if err := views.Validate(); err != nil {
+			verr.AddError(views, err)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
 	return verr
 }
 
-// Finalize adds all implied relationships if needed.
+// Finalize sets the technology of containers and components that do not
+// define one to DefaultTechnology, computes the description of templated
+// relationships and adds all implied relationships if needed.
 func (m *Model) Finalize() {
-	// Add relationships between container instances.
+	if m.DescriptionTemplate != "" {
+		IterateRelationships(func(r *Relationship) {
+			if r.Templated && r.Description == "" && r.Destination != nil {
+				r.Description = strings.NewReplacer(
+					"{source}", r.Source.Name,
+					"{destination}", r.Destination.Name,
+				).Replace(m.DescriptionTemplate)
+			}
+		})
+	}
+	if m.DefaultTechnology != "" {
+		for _, s := range m.Systems {
+			for _, c := range s.Containers {
+				if c.Technology == "" {
+					c.Technology = m.DefaultTechnology
+				}
+				for _, cmp := range c.Components {
+					if cmp.Technology == "" {
+						cmp.Technology = m.DefaultTechnology
+					}
+				}
+			}
+		}
+	}
+	// Add relationships between container instances of the same deployment
+	// environment.
+	// Relationships declared explicitly between the instances take
+	// precedence.
 	Iterate(func(e interface{}) {
 		if ci, ok := e.(*ContainerInstance); ok {
+			for _, r := range ci.Relationships {
+				r.Environment = ci.Environment
+			}
+			explicit := append([]*Relationship{}, ci.Relationships...)
 			c := Registry[ci.ContainerID].(*Container)
 			for _, r := range c.Relationships {
 				dc, ok := Registry[r.Destination.ID].(*Container)
@@ -101,34 +394,528 @@ func (m *Model) Finalize() {
 					if !ok {
 						return
 					}
-					if eci.ContainerID == dc.ID {
+					for _, er := range explicit {
+						if er.Destination.ID == eci.ID && er.Description == r.Description {
+							return
+						}
+					}
+					if eci.ContainerID == dc.ID && eci.Environment == ci.Environment {
 						rc := r.Dup(ci.Element, eci.Element)
 						rc.LinkedRelationshipID = r.ID
+						rc.Environment = ci.Environment
 						ci.Relationships = append(ci.Relationships, rc)
 					}
 				})
 			}
 		}
 	})
+	// Set the deployment environment of relationships between deployment
+	// nodes and infrastructure nodes.
+	IterateRelationships(func(r *Relationship) {
+		if r.CrossEnvironment || r.Destination == nil {
+			return
+		}
+		srcEnv, srcOK := nodeEnvironment(r.Source.ID)
+		destEnv, destOK := nodeEnvironment(r.Destination.ID)
+		if srcOK && destOK && srcEnv == destEnv {
+			r.Environment = srcEnv
+		}
+	})
 	if !m.AddImpliedRelationships {
 		return
 	}
 	// Add relationship between element parents.
 	Iterate(func(e interface{}) {
-		if r, ok := e.(*Relationship); ok {
+		if r, ok := e.(*Relationship); ok && !r.NoImply {
 			switch s := Registry[r.Source.ID].(type) {
 			case *Container:
-				addMissingRelationships(s.System.Element, r.Destination, r)
+				m.addMissingRelationships(s.System.Element, r.Destination, r)
+				m.addDirectParentRelationship(r)
 			case *Component:
-				addMissingRelationships(s.Container.Element, r.Destination, r)
-				addMissingRelationships(s.Container.System.Element, r.Destination, r)
+				m.addMissingRelationships(s.Container.Element, r.Destination, r)
+				if m.ImpliedMode == ImpliedFull {
+					m.addMissingRelationships(s.Container.System.Element, r.Destination, r)
+				}
+				m.addDirectParentRelationship(r)
+			case *Person, *SoftwareSystem:
+				// Relationships from people and software systems to
+				// containers or components imply relationships to the
+				// parents of the destination.
+				var parent *Element
+				switch d := Registry[r.Destination.ID].(type) {
+				case *Container:
+					parent = d.System.Element
+				case *Component:
+					parent = d.Container.Element
+				}
+				if parent != nil {
+					m.addMissingRelationships(r.Source, parent, r)
+				}
+			}
+		}
+	})
+	// Implied relationships recorded separately are not finalized with the
+	// relationships of their source element.
+	for _, r := range m.ImpliedRelationships {
+		r.Finalize()
+	}
+}
+
+// validateDescriptions records a warning for each element whose description
+// exceeds the maximum length.
+func (m *Model) validateDescriptions() {
+	if m.SkipDescriptionLength {
+		return
+	}
+	max := m.DescriptionMaxLength
+	if max == 0 {
+		max = DefaultDescriptionMaxLength
+	}
+	Iterate(func(e interface{}) {
+		eh, ok := e.(ElementHolder)
+		if !ok {
+			return
+		}
+		if l := utf8.RuneCountInString(eh.GetElement().Description); l > max {
+			m.warn(e.(eval.Expression), "description is %d characters long (maximum %d), consider shortening it or moving the details to the documentation", l, max)
+		}
+	})
+}
+
+// validateDeprecated records a warning for each relationship from an element
+// that is not deprecated to a deprecated element.
+func (m *Model) validateDeprecated() {
+	IterateRelationships(func(r *Relationship) {
+		if r.Destination == nil || !r.Destination.Deprecated() || r.Source.Deprecated() {
+			return
+		}
+		m.warn(r, "%q uses deprecated element %q", r.Source.Name, r.Destination.Name)
+	})
+}
+
+// validateLocations records a warning for each person or software system
+// tagged as external that is located inside the enterprise. The location
+// determines how the enterprise boundary is drawn so the tag is misleading.
+func (m *Model) validateLocations() {
+	check := func(e eval.Expression, elem *Element, loc LocationKind) {
+		if loc == LocationInternal && hasTag(elem.Tags, TagExternal) {
+			m.warn(e, "tagged %q but located internally, the enterprise boundary uses the location", TagExternal)
+		}
+	}
+	for _, p := range m.People {
+		check(p, p.Element, p.Location)
+	}
+	for _, s := range m.Systems {
+		check(s, s.Element, s.Location)
+	}
+}
+
+// duplicateName returns the message reported for element eh whose name is
+// already used by other. The message includes the canonical names and IDs of
+// both elements and suggests a name that is not used in the scope given by
+// known.
+func duplicateName(eh, other ElementHolder, known map[string]ElementHolder) string {
+	name := eh.GetElement().Name
+	suggestion := name + " (" + elementKind(eh) + ")"
+	if elementKind(eh) == elementKind(other) || known[suggestion] != nil {
+		for i := 2; ; i++ {
+			suggestion = fmt.Sprintf("%s %d", name, i)
+			if known[suggestion] == nil {
+				break
 			}
 		}
+	}
+	ids := fmt.Sprintf("both have ID %q", eh.GetElement().ID)
+	if id := other.GetElement().ID; id != eh.GetElement().ID {
+		ids = fmt.Sprintf("IDs %q and %q", id, eh.GetElement().ID)
+	}
+	return fmt.Sprintf("name already in use by %s (%s), rename one of them, e.g. to %q",
+		canonicalName(other.(eval.Expression)), ids, suggestion)
+}
+
+// elementKind returns the human readable kind of the given element.
+func elementKind(eh ElementHolder) string {
+	switch eh.(type) {
+	case *Person:
+		return "Person"
+	case *SoftwareSystem:
+		return "Software System"
+	case *Container:
+		return "Container"
+	case *Component:
+		return "Component"
+	case *DeploymentNode:
+		return "Deployment Node"
+	case *InfrastructureNode:
+		return "Infrastructure Node"
+	case *ContainerInstance:
+		return "Container Instance"
+	default:
+		return "Element"
+	}
+}
+
+// validateNormalizedNames records a warning for each person, software system,
+// container or component whose name has leading or trailing whitespace and
+// for each one whose name matches the name of another element of the same
+// scope when compared case insensitively and ignoring whitespace differences.
+func (m *Model) validateNormalizedNames() {
+	check := func(ehs []ElementHolder) {
+		seen := make(map[string]ElementHolder)
+		for _, eh := range ehs {
+			e := eh.GetElement()
+			if strings.TrimSpace(e.Name) != e.Name {
+				m.warn(eh.(eval.Expression), "name %q has leading or trailing whitespace%s", e.Name, declaredAt(e.DSLLocation))
+			}
+			key := strings.ToLower(strings.Join(strings.Fields(e.Name), " "))
+			if other, ok := seen[key]; ok {
+				if other.GetElement().Name != e.Name {
+					m.warn(eh.(eval.Expression), "name is nearly identical to the name of %s%s", canonicalName(other.(eval.Expression)), declaredAt(e.DSLLocation))
+				}
+				continue
+			}
+			seen[key] = eh
+		}
+	}
+	var top []ElementHolder
+	for _, p := range m.People {
+		top = append(top, p)
+	}
+	for _, s := range m.Systems {
+		top = append(top, s)
+	}
+	check(top)
+	for _, s := range m.Systems {
+		check(s.Containers.Elements())
+		for _, c := range s.Containers {
+			check(c.Components.Elements())
+		}
+	}
+}
+
+// validateDuplicateUses records a warning for each relationship declared more
+// than once with the same source, destination and description in the same
+// file. Identical relationships declared in different files are legitimate as
+// element definitions may be merged across files.
+func (m *Model) validateDuplicateUses() {
+	declared := make(map[string]*Relationship)
+	for _, e := range m.allElements() {
+		for _, r := range m.ElementRelationships(e) {
+			if r.DSLLocation == "" || r.Destination == nil {
+				continue
+			}
+			file := r.DSLLocation
+			if i := strings.LastIndex(file, ":"); i > 0 {
+				file = file[:i]
+			}
+			key := strings.Join([]string{r.Source.ID, r.Destination.ID, r.Description, file}, "\x00")
+			if first, ok := declared[key]; ok {
+				m.warn(r, "declared at %s duplicates relationship declared at %s", r.DSLLocation, first.DSLLocation)
+				continue
+			}
+			declared[key] = r
+		}
+	}
+}
+
+// validateRelationshipTagStyles records a warning for each tag of a
+// relationship that is neither the tag of a relationship style nor one of the
+// required tags of a relationship style. The default tags added when the
+// design is finalized (e.g. "Relationship") and the flow tags added by the Flow
+// DSL are not reported.
+func (m *Model) validateRelationshipTagStyles() {
+	styled := make(map[string]bool)
+	if Root.Views != nil && Root.Views.Styles != nil {
+		for _, rs := range Root.Views.Styles.Relationships {
+			styled[rs.Tag] = true
+			for _, tag := range rs.RequiredTags {
+				styled[tag] = true
+			}
+		}
+	}
+	for _, e := range m.allElements() {
+		for _, r := range m.ElementRelationships(e) {
+			if r.Implied || r.LinkedRelationshipID != "" {
+				continue
+			}
+			for _, tag := range strings.Split(r.Tags, ",") {
+				tag = strings.TrimSpace(tag)
+				if tag == "" || tag == "Relationship" || strings.HasPrefix(tag, FlowTagPrefix) || styled[tag] {
+					continue
+				}
+				m.warn(r, "tag %q has no relationship style, the relationship is rendered like untagged relationships%s", tag, declaredAt(r.DSLLocation))
+			}
+		}
+	}
+}
+
+// validateNameConventions reports an error for each person, software system,
+// container or component whose name does not match the corresponding naming
+// convention.
+func (m *Model) validateNameConventions(verr *eval.ValidationErrors) {
+	check := func(re *regexp.Regexp, e eval.Expression, elem *Element) {
+		if re != nil && !re.MatchString(elem.Name) {
+			verr.Add(e, "name %q does not match naming convention %q%s", elem.Name, re.String(), declaredAt(elem.DSLLocation))
+		}
+	}
+	for _, p := range m.People {
+		check(m.PersonNameConvention, p, p.Element)
+	}
+	for _, s := range m.Systems {
+		check(m.SystemNameConvention, s, s.Element)
+		for _, c := range s.Containers {
+			check(m.ContainerNameConvention, c, c.Element)
+			for _, cmp := range c.Components {
+				check(m.ComponentNameConvention, cmp, cmp.Element)
+			}
+		}
+	}
+}
+
+// validateEnterprises reports an error for each software system whose
+// enterprise name is blank or has leading or trailing whitespace.
+func (m *Model) validateEnterprises(verr *eval.ValidationErrors) {
+	for _, s := range m.Systems {
+		name, ok := s.Properties[EnterpriseProperty]
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(name) == "" {
+			verr.Add(s, "enterprise name cannot be blank%s", declaredAt(s.DSLLocation))
+		} else if strings.TrimSpace(name) != name {
+			verr.Add(s, "enterprise name %q has leading or trailing whitespace%s", name, declaredAt(s.DSLLocation))
+		}
+	}
+}
+
+// validateInstanceRelationships reports an error for each relationship
+// involving a container instance whose source and destination are not both
+// container instances of the same deployment environment.
+func (m *Model) validateInstanceRelationships(verr *eval.ValidationErrors) {
+	IterateRelationships(func(r *Relationship) {
+		if r.Destination == nil {
+			return
+		}
+		src, srcOK := Registry[r.Source.ID].(*ContainerInstance)
+		dest, destOK := Registry[r.Destination.ID].(*ContainerInstance)
+		switch {
+		case !srcOK && !destOK:
+			return
+		case !srcOK || !destOK:
+			verr.Add(r, "relationships with container instances must be between container instances%s", declaredAt(r.DSLLocation))
+		case src.Environment != dest.Environment:
+			verr.Add(r, "container instances are in different deployment environments %q and %q%s", src.Environment, dest.Environment, declaredAt(r.DSLLocation))
+		}
 	})
 }
 
+// validateNodeRelationships reports an error for each relationship that links
+// a deployment node or an infrastructure node to an element that is not a
+// deployment node or an infrastructure node and for each relationship between
+// nodes of different deployment environments unless the relationship is marked
+// as crossing environments.
+func (m *Model) validateNodeRelationships(verr *eval.ValidationErrors) {
+	IterateRelationships(func(r *Relationship) {
+		if r.Destination == nil {
+			return
+		}
+		srcEnv, srcOK := nodeEnvironment(r.Source.ID)
+		destEnv, destOK := nodeEnvironment(r.Destination.ID)
+		switch {
+		case !srcOK && !destOK:
+			return
+		case !srcOK || !destOK:
+			verr.Add(r, "relationships with deployment nodes or infrastructure nodes must be between deployment nodes or infrastructure nodes%s", declaredAt(r.DSLLocation))
+		case srcEnv != destEnv && !r.CrossEnvironment:
+			verr.Add(r, "deployment nodes are in different deployment environments %q and %q, use CrossEnvironment to allow%s", srcEnv, destEnv, declaredAt(r.DSLLocation))
+		}
+	})
+}
+
+// validateRelationshipTechnology reports an error for each relationship between
+// containers or components that does not define a technology. Relationships
+// from or to people and software systems are exempt.
+func (m *Model) validateRelationshipTechnology(verr *eval.ValidationErrors) {
+	isContainerOrComponent := func(id string) bool {
+		switch Registry[id].(type) {
+		case *Container, *Component:
+			return true
+		}
+		return false
+	}
+	IterateRelationships(func(r *Relationship) {
+		if r.Destination == nil || r.Technology != "" {
+			return
+		}
+		if isContainerOrComponent(r.Source.ID) && isContainerOrComponent(r.Destination.ID) {
+			verr.Add(r, "relationships between containers or components must define a technology%s", declaredAt(r.DSLLocation))
+		}
+	})
+}
+
+// validateGroups makes sure that the names of the groups of people and
+// software systems, of the containers of a software system and of the
+// components of a container are not the names of elements at the same level.
+func (m *Model) validateGroups(verr *eval.ValidationErrors) {
+	check := func(elems []ElementHolder) {
+		names := make(map[string]ElementHolder, len(elems))
+		for _, eh := range elems {
+			names[eh.GetElement().Name] = eh
+		}
+		for _, eh := range elems {
+			e := eh.GetElement()
+			if e.Group == "" {
+				continue
+			}
+			for _, name := range strings.Split(e.Group, GroupSeparator) {
+				if other, ok := names[name]; ok {
+					verr.Add(eh.(eval.Expression), "group %q conflicts with the name of %s at the same level%s", e.Group, canonicalName(other.(eval.Expression)), declaredAt(e.DSLLocation))
+					break
+				}
+			}
+		}
+	}
+	check(append(m.People.Elements(), m.Systems.Elements()...))
+	for _, s := range m.Systems {
+		check(s.Containers.Elements())
+		for _, c := range s.Containers {
+			check(c.Components.Elements())
+		}
+	}
+}
+
+// validateInterfaces makes sure that the interface names of each component are
+// unique and that relationships targeting an interface have a destination
+// component that exposes it.
+func (m *Model) validateInterfaces(verr *eval.ValidationErrors) {
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			for _, cmp := range c.Components {
+				seen := make(map[string]bool)
+				for _, iface := range cmp.Interfaces {
+					if seen[iface.Name] {
+						verr.Add(cmp, "interface %q is defined more than once", iface.Name)
+					}
+					seen[iface.Name] = true
+				}
+			}
+		}
+	}
+	IterateRelationships(func(r *Relationship) {
+		if r.Interface == "" || r.Destination == nil {
+			return
+		}
+		cmp, ok := Registry[r.Destination.ID].(*Component)
+		if !ok {
+			verr.Add(r, "relationship targets interface %q but its destination %q is not a component%s", r.Interface, r.Destination.Name, declaredAt(r.DSLLocation))
+			return
+		}
+		if cmp.Interface(r.Interface) == nil {
+			verr.Add(r, "relationship targets interface %q which is not defined by component %q%s", r.Interface, cmp.Name, declaredAt(r.DSLLocation))
+		}
+	})
+}
+
+// nodeEnvironment returns the deployment environment of the deployment node or
+// infrastructure node with the given ID. The second value is false if the ID
+// does not correspond to a deployment node or an infrastructure node.
+func nodeEnvironment(id string) (string, bool) {
+	switch n := Registry[id].(type) {
+	case *DeploymentNode:
+		return n.Environment, true
+	case *InfrastructureNode:
+		return n.Environment, true
+	}
+	return "", false
+}
+
+// validatePlaceholders records a warning for each element or relationship
+// whose description or technology contains one of the placeholder patterns as
+// a whole word, so that "TODO" matches "TODO: describe" but not "Mastodon".
+func (m *Model) validatePlaceholders() {
+	patterns := m.PlaceholderPatterns
+	if patterns == nil {
+		patterns = DefaultPlaceholderPatterns
+	}
+	if len(patterns) == 0 {
+		return
+	}
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		res[i] = regexp.MustCompile(`(?i)(?:^|[^\pL\pN_])` + regexp.QuoteMeta(p) + `(?:$|[^\pL\pN_])`)
+	}
+	check := func(e eval.Expression, loc, field, val string) {
+		for i, re := range res {
+			if re.MatchString(val) {
+				m.warn(e, "%s contains placeholder %q%s", field, patterns[i], declaredAt(loc))
+				return
+			}
+		}
+	}
+	Iterate(func(e interface{}) {
+		switch x := e.(type) {
+		case ElementHolder:
+			elem := x.GetElement()
+			check(x.(eval.Expression), elem.DSLLocation, "description", elem.Description)
+			check(x.(eval.Expression), elem.DSLLocation, "technology", elem.Technology)
+		case *Relationship:
+			check(x, x.DSLLocation, "description", x.Description)
+			check(x, x.DSLLocation, "technology", x.Technology)
+		}
+	})
+}
+
+// validateDescriptionTemplate reports an error if DescriptionTemplate uses
+// placeholders other than "{source}" and "{destination}" and for each
+// templated relationship with no description if there is no template.
+func (m *Model) validateDescriptionTemplate(verr *eval.ValidationErrors) {
+	for _, p := range descriptionTemplatePlaceholder.FindAllString(m.DescriptionTemplate, -1) {
+		if p != "{source}" && p != "{destination}" {
+			verr.Add(m, "description template %q: unknown placeholder %s, valid placeholders are {source} and {destination}", m.DescriptionTemplate, p)
+		}
+	}
+	if m.DescriptionTemplate != "" {
+		return
+	}
+	IterateRelationships(func(r *Relationship) {
+		if r.Templated && r.Description == "" {
+			verr.Add(r, "relationship is templated but the model does not define a description template%s", declaredAt(r.DSLLocation))
+		}
+	})
+}
+
+// validateDeploymentEnvironments reports an error for each child deployment
+// node, infrastructure node or container instance whose deployment environment
+// differs from the environment of its root deployment node.
+func (m *Model) validateDeploymentEnvironments(verr *eval.ValidationErrors) {
+	for _, root := range m.DeploymentNodes {
+		check := func(e eval.Expression, elem *Element, env string) {
+			if env != root.Environment {
+				verr.Add(e, "deployment environment %q differs from environment %q of root deployment node %q%s", env, root.Environment, root.Name, declaredAt(elem.DSLLocation))
+			}
+		}
+		var walk func(*DeploymentNode)
+		walk = func(n *DeploymentNode) {
+			for _, i := range n.InfrastructureNodes {
+				check(i, i.Element, i.Environment)
+			}
+			for _, ci := range n.ContainerInstances {
+				check(ci, ci.Element, ci.Environment)
+			}
+			for _, c := range n.Children {
+				check(c, c.Element, c.Environment)
+				walk(c)
+			}
+		}
+		walk(root)
+	}
+}
+
 // Person returns the person with the given name if any, nil otherwise.
 func (m *Model) Person(name string) *Person {
+	if m.index != nil {
+		return m.index.people[name]
+	}
 	for _, pp := range m.People {
 		if pp.Name == name {
 			return pp
@@ -140,6 +927,9 @@ func (m *Model) Person(name string) *Person {
 // SoftwareSystem returns the software system with the given name if any, nil
 // otherwise.
 func (m *Model) SoftwareSystem(name string) *SoftwareSystem {
+	if m.index != nil {
+		return m.index.systems[name]
+	}
 	for _, s := range m.Systems {
 		if s.Name == name {
 			return s
@@ -164,21 +954,24 @@ func (m *Model) DeploymentNode(name string) *DeploymentNode {
 //    - "<Person>", "<SoftwareSystem>", "<SoftwareSystem>/<Container>" or "<SoftwareSystem>/<Container>/<Component>"
 //    - "<Container>" (if container is a child of the software system scope)
 //    - "<Component>" (if component is a child of the container scope)
-//    - "<Container>/<Component>" (if container is a child of the software system scope)
+//    - "<Container>/<Component>" (if container is a child of the software system scope
+//      or of the software system of the container or component scope)
+//    - "<Alias>" (if an element defines the alias, see Alias)
 //
 // The scope may be nil in which case the path must be rooted with a top level
-// element (person or software system).
+// element (person or software system). Fully qualified paths always resolve
+// regardless of the scope.
 func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder, err error) {
 	elems := strings.Split(path, "/")
 	switch len(elems) {
 	case 1:
 		switch s := scope.(type) {
 		case *SoftwareSystem:
-			if c := s.Container(path); c != nil {
+			if c := m.container(s, path); c != nil {
 				eh = c
 			}
 		case *Container:
-			if c := s.Component(path); c != nil {
+			if c := m.component(s, path); c != nil {
 				eh = c
 			}
 		}
@@ -187,6 +980,8 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 				eh = p
 			} else if sys := m.SoftwareSystem(path); sys != nil {
 				eh = sys
+			} else if a, ok := Aliases[path]; ok {
+				eh = a
 			} else {
 				if scope == nil {
 					return nil, fmt.Errorf("%q does not match the name of a person, a software system or the path to container or component in scope", path)
@@ -195,27 +990,41 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 			}
 		}
 	case 2:
-		if s, ok := scope.(*SoftwareSystem); ok {
-			if c := s.Container(elems[0]); c != nil {
-				if cmp := c.Component(elems[1]); cmp != nil {
+		// Resolve container/component relative to the software system in
+		// scope first, then software system/container.
+		var sys *SoftwareSystem
+		switch s := scope.(type) {
+		case *SoftwareSystem:
+			sys = s
+		case *Container:
+			sys = s.System
+		case *Component:
+			sys = s.Container.System
+		}
+		if sys != nil {
+			if c := m.container(sys, elems[0]); c != nil {
+				if cmp := m.component(c, elems[1]); cmp != nil {
 					eh = cmp
 				}
 			}
 		}
 		if eh == nil {
 			if s := m.SoftwareSystem(elems[0]); s != nil {
-				if c := s.Container(elems[1]); c != nil {
+				if c := m.container(s, elems[1]); c != nil {
 					eh = c
 				}
 			}
 			if eh == nil {
+				if scope == nil {
+					return nil, fmt.Errorf("%q does not match the name of a software system and container", path)
+				}
 				return nil, fmt.Errorf("%q does not match the name of a software system and container or the name of a container and component in the scope of %q", path, scope.GetElement().Name)
 			}
 		}
 	case 3:
 		if s := m.SoftwareSystem(elems[0]); s != nil {
-			if c := s.Container(elems[1]); c != nil {
-				if cmp := c.Component(elems[2]); cmp != nil {
+			if c := m.container(s, elems[1]); c != nil {
+				if cmp := m.component(c, elems[2]); cmp != nil {
 					eh = cmp
 				}
 			}
@@ -229,6 +1038,214 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 	return eh, nil
 }
 
+// Relationship returns the relationship with the given description between
+// the elements with the given paths. The paths must be fully qualified, see
+// FindElement.
+func (m *Model) Relationship(srcPath, destPath, description string) (*Relationship, error) {
+	src, err := m.FindElement(nil, srcPath)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := m.FindElement(nil, destPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range m.ElementRelationships(src.GetElement()) {
+		if r.Destination != nil && r.Destination.ID == dest.GetElement().ID && r.Description == description {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("no relationship %q from %q to %q", description, srcPath, destPath)
+}
+
+// HasRelationship returns true if the model contains a relationship with the
+// given description between the elements with the given paths, false
+// otherwise. The paths must be fully qualified, see FindElement.
+func (m *Model) HasRelationship(srcPath, destPath, description string) bool {
+	r, _ := m.Relationship(srcPath, destPath, description)
+	return r != nil
+}
+
+// ElementRelationships returns the relationships whose source is the given
+// element including the implied relationships recorded separately when
+// SeparateImpliedRelationships is true.
+// Code reading the relationships of an element must use ElementRelationships
+// rather than Element.Relationships so that the implied relationships are
+// taken into account.
+func (m *Model) ElementRelationships(e *Element) []*Relationship {
+	if len(m.ImpliedRelationships) == 0 {
+		return e.Relationships
+	}
+	rels := append([]*Relationship{}, e.Relationships...)
+	for _, r := range m.ImpliedRelationships {
+		if r.Source.ID == e.ID {
+			rels = append(rels, r)
+		}
+	}
+	return rels
+}
+
+// RelationshipsInDeclarationOrder returns the relationships of the model in
+// the order they were declared in the DSL. Relationships that were not
+// declared explicitly (e.g. implied relationships or relationships replicated
+// onto container instances) come last in model order (see Metrics). The
+// result does not depend on the Registry iteration order and is thus stable
+// across runs.
+func (m *Model) RelationshipsInDeclarationOrder() []*Relationship {
+	var rels []*Relationship
+	for _, e := range m.allElements() {
+		rels = append(rels, m.ElementRelationships(e)...)
+	}
+	sort.SliceStable(rels, func(i, j int) bool {
+		si, sj := rels[i].seq, rels[j].seq
+		if si == 0 || sj == 0 {
+			return si != 0 && sj == 0
+		}
+		return si < sj
+	})
+	return rels
+}
+
+// FlowTagPrefix is the prefix of the tags added by the Flow DSL to the
+// relationships of a flow, the tag is the prefix followed by the flow name.
+const FlowTagPrefix = "flow:"
+
+// Flow returns the relationships of the flow with the given name, that is the
+// relationships tagged with FlowTagPrefix followed by name, in declaration
+// order. Implied relationships and relationships replicated onto container
+// instances inherit the tags of the relationship they derive from but are not
+// part of the flow.
+func (m *Model) Flow(name string) []*Relationship {
+	var res []*Relationship
+	for _, r := range m.RelationshipsInDeclarationOrder() {
+		if r.Implied || r.LinkedRelationshipID != "" {
+			continue
+		}
+		if hasTag(r.Tags, FlowTagPrefix+name) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// AllTags returns the sorted list of distinct tags defined by the design on
+// the elements and relationships of the model. The implicit tags each element
+// and relationship gets (e.g. "Element", "Person", "Relationship" or
+// "Synchronous") are not listed, see AllEffectiveTags.
+func (m *Model) AllTags() []string {
+	return m.allTags(false)
+}
+
+// AllEffectiveTags returns the sorted list of distinct tags of the elements
+// and relationships of the model including the implicit tags whether or not
+// the design has been finalized.
+func (m *Model) AllEffectiveTags() []string {
+	return m.allTags(true)
+}
+
+// allTags returns the sorted list of distinct tags of the model, withImplicit
+// controls whether the implicit tags are included.
+func (m *Model) allTags(withImplicit bool) []string {
+	seen := make(map[string]struct{})
+	add := func(tags string, defaults []string) {
+		skip := make(map[string]bool, len(defaults))
+		for _, t := range defaults {
+			if withImplicit {
+				seen[t] = struct{}{}
+			} else {
+				skip[t] = true
+			}
+		}
+		for _, t := range strings.Split(tags, ",") {
+			if t = strings.TrimSpace(t); t != "" && !skip[t] {
+				seen[t] = struct{}{}
+			}
+		}
+	}
+	addElement := func(eh ElementHolder) {
+		e := eh.GetElement()
+		add(e.Tags, implicitTags(eh))
+		for _, r := range m.ElementRelationships(e) {
+			add(r.Tags, implicitRelationshipTags(r))
+		}
+	}
+	for _, p := range m.People {
+		addElement(p)
+	}
+	for _, s := range m.Systems {
+		addElement(s)
+		for _, c := range s.Containers {
+			addElement(c)
+			for _, cmp := range c.Components {
+				addElement(cmp)
+			}
+		}
+	}
+	eachDeploymentNode(m.DeploymentNodes, func(n *DeploymentNode) {
+		addElement(n)
+		for _, i := range n.InfrastructureNodes {
+			addElement(i)
+		}
+		for _, ci := range n.ContainerInstances {
+			addElement(ci)
+		}
+	})
+	res := make([]string, 0, len(seen))
+	for t := range seen {
+		res = append(res, t)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// implicitTags returns the tags added to the given element when the design is
+// finalized.
+func implicitTags(eh ElementHolder) []string {
+	switch eh.(type) {
+	case *Person:
+		return []string{"Element", "Person"}
+	case *SoftwareSystem:
+		return []string{"Element", "Software System"}
+	case *Container:
+		return []string{"Element", "Container"}
+	case *Component:
+		return []string{"Element", "Component"}
+	case *DeploymentNode:
+		return []string{"Element", "Deployment Node"}
+	case *InfrastructureNode:
+		return []string{"Element", "Infrastructure Node"}
+	case *ContainerInstance:
+		return []string{"Container Instance"}
+	default:
+		return nil
+	}
+}
+
+// implicitRelationshipTags returns the tags added to the given relationship
+// when the design is finalized.
+func implicitRelationshipTags(r *Relationship) []string {
+	switch r.InteractionStyle {
+	case InteractionSynchronous:
+		return []string{"Relationship", TagSynchronous}
+	case InteractionAsynchronous:
+		return []string{"Relationship", TagAsynchronous}
+	default:
+		return []string{"Relationship"}
+	}
+}
+
+// effectiveTags returns the tags of the given element including its implicit
+// tags whether or not the design has been finalized.
+func effectiveTags(eh ElementHolder) string {
+	return strings.Join(append(implicitTags(eh), eh.GetElement().Tags), ",")
+}
+
+// effectiveRelationshipTags returns the tags of the given relationship
+// including its implicit tags whether or not the design has been finalized.
+func effectiveRelationshipTags(r *Relationship) string {
+	return strings.Join(append(implicitRelationshipTags(r), r.Tags), ",")
+}
+
 // AddPerson adds the given person to the model. If there is already a person
 // with the given name then AddPerson merges both definitions. The merge
 // algorithm:
@@ -239,6 +1256,7 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 //
 // AddPerson returns the new or merged person.
 func (m *Model) AddPerson(p *Person) *Person {
+	m.invalidateIndex()
 	existing := m.Person(p.Name)
 	if existing == nil {
 		Identify(p)
@@ -265,6 +1283,7 @@ func (m *Model) AddPerson(p *Person) *Person {
 //
 // AddSystem returns the new or merged software system.
 func (m *Model) AddSystem(s *SoftwareSystem) *SoftwareSystem {
+	m.invalidateIndex()
 	existing := m.SoftwareSystem(s.Name)
 	if existing == nil {
 		Identify(s)
@@ -311,25 +1330,142 @@ func (m *Model) AddDeploymentNode(d *DeploymentNode) *DeploymentNode {
 	return existing
 }
 
+// Add adds the given element to the model using AddPerson, AddSystem or
+// AddDeploymentNode depending on its type and returns the new or merged
+// element. Add returns an error if the element cannot be a top level element
+// of the model (e.g. a container or a component).
+func (m *Model) Add(eh ElementHolder) (ElementHolder, error) {
+	switch e := eh.(type) {
+	case *Person:
+		return m.AddPerson(e), nil
+	case *SoftwareSystem:
+		return m.AddSystem(e), nil
+	case *DeploymentNode:
+		if e.Parent != nil {
+			return nil, fmt.Errorf("cannot add deployment node %q to the model: it is a child of deployment node %q", e.Name, e.Parent.Name)
+		}
+		return m.AddDeploymentNode(e), nil
+	case nil:
+		return nil, fmt.Errorf("cannot add nil element to the model")
+	default:
+		name := fmt.Sprintf("element %q", eh.GetElement().Name)
+		if ex, ok := eh.(eval.Expression); ok {
+			name = ex.EvalName()
+		}
+		return nil, fmt.Errorf("cannot add %s to the model: only people, software systems and deployment nodes can be added at the top level", name)
+	}
+}
+
 // addMissingRelationships adds relationships from src to element with ID destID
 // and its parents (container system software and component container) based on
 // the properties of existing. It only adds a relationship if one doesn't
-// already exist with the same description.
-func addMissingRelationships(src, dest *Element, existing *Relationship) {
-	for _, r := range src.Relationships {
+// already exist with the same description and never adds a relationship from
+// an element to itself or to one of its parents.
+func (m *Model) addMissingRelationships(src, dest *Element, existing *Relationship) {
+	if selfOrAncestor(dest, src) {
+		return
+	}
+	for _, r := range m.ElementRelationships(src) {
 		if r.Destination.ID == dest.ID && r.Description == existing.Description {
 			return
 		}
 	}
 	r := existing.Dup(src, dest)
-	src.Relationships = append(src.Relationships, r)
+	r.Implied = true
+	if m.SeparateImpliedRelationships {
+		m.ImpliedRelationships = append(m.ImpliedRelationships, r)
+	} else {
+		src.Relationships = append(src.Relationships, r)
+	}
 
 	// Add relationships to destination parents as well.
+	if m.ImpliedMode == ImpliedDirectParentOnly {
+		return
+	}
 	switch e := Registry[dest.ID].(type) {
 	case *Container:
-		addMissingRelationships(src, e.System.Element, existing)
+		m.addMissingRelationships(src, e.System.Element, existing)
+	case *Component:
+		m.addMissingRelationships(src, e.Container.Element, existing)
+		m.addMissingRelationships(src, e.Container.System.Element, existing)
+	}
+}
+
+// selfOrAncestor returns true if e is the element src or one of its parents.
+func selfOrAncestor(e, src *Element) bool {
+	if e.ID == src.ID {
+		return true
+	}
+	switch s := Registry[src.ID].(type) {
+	case *Container:
+		return s.System.ID == e.ID
 	case *Component:
-		addMissingRelationships(src, e.Container.Element, existing)
-		addMissingRelationships(src, e.Container.System.Element, existing)
+		return s.Container.ID == e.ID || s.Container.System.ID == e.ID
+	}
+	return false
+}
+
+// addDirectParentRelationship adds the relationship from the source of r to
+// the direct parent of its destination when ImpliedMode is
+// ImpliedDirectParentOnly. In ImpliedFull mode addMissingRelationships already
+// adds relationships to all the destination parents. The relationship is not
+// added if the parent is also a parent of the source.
+func (m *Model) addDirectParentRelationship(r *Relationship) {
+	if m.ImpliedMode != ImpliedDirectParentOnly {
+		return
+	}
+	var parent *Element
+	switch d := Registry[r.Destination.ID].(type) {
+	case *Container:
+		parent = d.System.Element
+	case *Component:
+		parent = d.Container.Element
+	default:
+		return
+	}
+	switch s := Registry[r.Source.ID].(type) {
+	case *Container:
+		if s.System.ID == parent.ID {
+			return
+		}
+	case *Component:
+		if s.Container.ID == parent.ID || s.Container.System.ID == parent.ID {
+			return
+		}
+	}
+	m.addMissingRelationships(r.Source, parent, r)
+}
+
+// removeRelationships removes the relationships whose source or destination is
+// one of the elements with the given IDs from the relationships of all the
+// elements of the model, including container instances, as well as from the
+// implied relationships and the registry. It must be called by any code that
+// removes elements from the model so that the model never holds a relationship
+// to an element that does not exist.
+func (m *Model) removeRelationships(removed map[string]bool) {
+	dangling := func(r *Relationship) bool {
+		return removed[r.Source.ID] || r.Destination != nil && removed[r.Destination.ID]
+	}
+	prune := func(rels []*Relationship) []*Relationship {
+		var res []*Relationship
+		for _, r := range rels {
+			if !dangling(r) {
+				res = append(res, r)
+			}
+		}
+		return res
+	}
+	for _, e := range m.allElements() {
+		e.Relationships = prune(e.Relationships)
+	}
+	m.ImpliedRelationships = prune(m.ImpliedRelationships)
+	var ids []string
+	IterateRelationships(func(r *Relationship) {
+		if dangling(r) {
+			ids = append(ids, r.ID)
+		}
+	})
+	for _, id := range ids {
+		delete(Registry, id)
 	}
 }
@@ -0,0 +1,1071 @@
+package expr
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+)
+
+func TestModelFindElement(t *testing.T) {
+	t.Parallel()
+	var (
+		sysA  = &SoftwareSystem{Element: &Element{Name: "A"}}
+		contB = &Container{Element: &Element{Name: "B"}, System: sysA}
+		cmpD  = &Component{Element: &Element{Name: "D"}, Container: contB}
+		sysC  = &SoftwareSystem{Element: &Element{Name: "C"}}
+		contE = &Container{Element: &Element{Name: "E"}, System: sysC}
+		cmpF  = &Component{Element: &Element{Name: "F"}, Container: contE}
+	)
+	sysA.Containers = Containers{contB}
+	contB.Components = Components{cmpD}
+	sysC.Containers = Containers{contE}
+	contE.Components = Components{cmpF}
+	m := &Model{Systems: SoftwareSystems{sysA, sysC}}
+
+	tests := []struct {
+		name    string
+		scope   ElementHolder
+		path    string
+		want    ElementHolder
+		wantErr bool
+	}{
+		{"system", nil, "A", sysA, false},
+		{"container-nil-scope", nil, "A/B", contB, false},
+		{"component-nil-scope", nil, "A/B/D", cmpD, false},
+		{"container-from-other-container", contE, "A/B", contB, false},
+		{"component-from-other-container", contE, "A/B/D", cmpD, false},
+		{"component-from-other-component", cmpF, "A/B/D", cmpD, false},
+		{"component-from-other-system", sysC, "A/B/D", cmpD, false},
+		{"component-in-same-system", contE, "E/F", cmpF, false},
+		{"component-in-system-scope", sysC, "E/F", cmpF, false},
+		{"unknown-nil-scope", nil, "A/X", nil, true},
+		{"unknown-component", contE, "A/B/X", nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := m.FindElement(tt.scope, tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got.GetElement().Name, tt.want.GetElement().Name)
+			}
+		})
+	}
+}
+
+func TestModelValidateAliases(t *testing.T) {
+	var (
+		user = &Person{Element: &Element{Name: "Aliases User"}}
+		sys  = &SoftwareSystem{Element: &Element{Name: "Aliases System"}}
+		api  = &Container{Element: &Element{Name: "API"}, System: sys}
+		db   = &Container{Element: &Element{Name: "Database"}, System: sys}
+	)
+	sys.Containers = Containers{api, db}
+	for _, e := range []interface{}{user, sys, api, db} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, sys.ID, api.ID, db.ID} {
+			delete(Registry, id)
+		}
+	}()
+	tests := []struct {
+		name       string
+		apiAlias   string
+		dbAlias    string
+		wantErrors int
+	}{
+		{"unique", "gateway", "store", 0},
+		{"own-name", "API", "", 0},
+		{"duplicate", "gateway", "gateway", 1},
+		{"person-name", "Aliases User", "", 1},
+		{"container-name", "Database", "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api.Alias, db.Alias = tt.apiAlias, tt.dbAlias
+			m := &Model{People: People{user}, Systems: SoftwareSystems{sys}}
+
+			err := m.Validate()
+
+			if got := len(err.(*eval.ValidationErrors).Errors); got != tt.wantErrors {
+				t.Errorf("got %d errors, want %d: %v", got, tt.wantErrors, err)
+			}
+		})
+	}
+}
+
+func TestModelAllTags(t *testing.T) {
+	t.Parallel()
+	var (
+		user = &Person{Element: &Element{Name: "User", Tags: "Customer"}}
+		sys  = &SoftwareSystem{Element: &Element{Name: "System", Tags: "Internal,Legacy"}}
+		cont = &Container{Element: &Element{Name: "DB", Tags: "Database, Legacy"}, System: sys}
+	)
+	sys.Containers = Containers{cont}
+	user.Relationships = []*Relationship{
+		{Source: user.Element, Destination: sys.Element, Tags: "HTTP", InteractionStyle: InteractionSynchronous},
+	}
+	for _, f := range []interface{ Finalize() }{user, sys, cont} {
+		f.Finalize()
+	}
+	m := &Model{People: People{user}, Systems: SoftwareSystems{sys}}
+
+	tests := []struct {
+		name string
+		tags func() []string
+		want []string
+	}{
+		{"defined", m.AllTags, []string{"Customer", "Database", "HTTP", "Internal", "Legacy"}},
+		{"effective", m.AllEffectiveTags, []string{"Container", "Customer", "Database", "Element", "HTTP", "Internal", "Legacy", "Person", "Relationship", "Software System", "Synchronous"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.tags()
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i, tag := range tt.want {
+				if got[i] != tag {
+					t.Errorf("got tag %d %q, want %q", i, got[i], tag)
+				}
+			}
+		})
+	}
+}
+
+func TestModelValidatePlaceholders(t *testing.T) {
+	var (
+		user = &Person{Element: &Element{Name: "Placeholders User", Description: "A user"}}
+		sys  = &SoftwareSystem{Element: &Element{Name: "Placeholders System"}}
+		rel  = &Relationship{Source: user.Element, Destination: sys.Element}
+	)
+	user.Relationships = []*Relationship{rel}
+	for _, e := range []interface{}{user, sys, rel} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, sys.ID, rel.ID} {
+			delete(Registry, id)
+		}
+	}()
+	tests := []struct {
+		name              string
+		description       string
+		patterns          []string
+		warningsAreErrors bool
+		wantWarnings      int
+		wantErrors        int
+	}{
+		{"placeholder", "todo: describe", nil, false, 1, 0},
+		{"inside-word", "Posts to Mastodon", nil, false, 0, 0},
+		{"custom-pattern", "Calls the TBD API", []string{"TBD"}, false, 1, 0},
+		{"disabled", "todo: describe", []string{}, false, 0, 0},
+		{"warnings-are-errors", "FIXME", nil, true, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel.Description = tt.description
+			m := &Model{
+				People:              People{user},
+				Systems:             SoftwareSystems{sys},
+				PlaceholderPatterns: tt.patterns,
+				WarningsAreErrors:   tt.warningsAreErrors,
+			}
+
+			err := m.Validate()
+
+			if len(m.Warnings) != tt.wantWarnings {
+				t.Fatalf("got %d warnings, want %d: %v", len(m.Warnings), tt.wantWarnings, m.Warnings)
+			}
+			if tt.wantWarnings > 0 {
+				if m.Warnings[0].Expr != rel {
+					t.Errorf("got warning on %s, want %s", m.Warnings[0].Expr.EvalName(), rel.EvalName())
+				}
+				if !strings.Contains(m.Warnings[0].Message, "contains placeholder") {
+					t.Errorf("got warning %q, want placeholder warning", m.Warnings[0].Message)
+				}
+			}
+			if got := len(err.(*eval.ValidationErrors).Errors); got != tt.wantErrors {
+				t.Errorf("got %d errors, want %d: %v", got, tt.wantErrors, err)
+			}
+		})
+	}
+}
+
+func TestModelValidateInterfaces(t *testing.T) {
+	var (
+		sys    = &SoftwareSystem{Element: &Element{Name: "Interfaces System"}}
+		cont   = &Container{Element: &Element{Name: "Interfaces Container"}, System: sys}
+		orders = &Component{Element: &Element{Name: "Orders"}, Container: cont}
+		web    = &Component{Element: &Element{Name: "Web"}, Container: cont}
+	)
+	sys.Containers = Containers{cont}
+	cont.Components = Components{orders, web}
+	orders.Interfaces = []*Interface{{Name: "Orders API", Technology: "REST"}, {Name: "Order Events"}}
+	for _, e := range []interface{}{sys, cont, orders, web} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{sys.ID, cont.ID, orders.ID, web.ID} {
+			delete(Registry, id)
+		}
+	}()
+	m := &Model{Systems: SoftwareSystems{sys}}
+
+	tests := []struct {
+		name    string
+		iface   string
+		dest    *Element
+		wantErr string
+	}{
+		{"interface", "Orders API", orders.Element, ""},
+		{"unknown interface", "Payments API", orders.Element, `interface "Payments API" which is not defined by component "Orders"`},
+		{"not a component", "Orders API", cont.Element, `destination "Interfaces Container" is not a component`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel := &Relationship{Source: web.Element, Destination: tt.dest, Description: "Places orders using", Interface: tt.iface}
+			Identify(rel)
+			defer delete(Registry, rel.ID)
+			web.Relationships = []*Relationship{rel}
+			defer func() { web.Relationships = nil }()
+
+			err := m.Validate()
+
+			verr := err.(*eval.ValidationErrors)
+			if tt.wantErr == "" {
+				if len(verr.Errors) != 0 {
+					t.Errorf("got %v, want no error", err)
+				}
+				return
+			}
+			if len(verr.Errors) != 1 || verr.Expressions[0] != rel {
+				t.Fatalf("got %v, want one error on the relationship", err)
+			}
+			if !strings.Contains(verr.Error(), tt.wantErr) {
+				t.Errorf("got error %q, want it to contain %q", verr.Error(), tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("duplicate interface", func(t *testing.T) {
+		web.Interfaces = []*Interface{{Name: "Web API"}, {Name: "Web API"}}
+		defer func() { web.Interfaces = nil }()
+
+		err := m.Validate()
+
+		verr := err.(*eval.ValidationErrors)
+		if len(verr.Errors) != 1 || verr.Expressions[0] != web {
+			t.Fatalf("got %v, want one error on the component", err)
+		}
+		if !strings.Contains(verr.Error(), `interface "Web API" is defined more than once`) {
+			t.Errorf("got error %q, want duplicate interface error", verr.Error())
+		}
+	})
+}
+
+func TestModelValidateDescriptions(t *testing.T) {
+	sys := &SoftwareSystem{Element: &Element{Name: "Descriptions System"}}
+	Identify(sys)
+	defer delete(Registry, sys.ID)
+	tests := []struct {
+		name         string
+		description  string
+		max          int
+		skip         bool
+		wantWarnings int
+	}{
+		{"short", "A system", 0, false, 0},
+		{"default-maximum", strings.Repeat("a", DefaultDescriptionMaxLength+1), 0, false, 1},
+		{"custom-maximum", "A system", 5, false, 1},
+		{"multibyte-at-maximum", "Système", 7, false, 0},
+		{"multibyte-over-maximum", "Système!", 7, false, 1},
+		{"skipped", strings.Repeat("a", DefaultDescriptionMaxLength+1), 0, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys.Description = tt.description
+			m := &Model{Systems: SoftwareSystems{sys}, DescriptionMaxLength: tt.max, SkipDescriptionLength: tt.skip}
+
+			m.Validate()
+
+			if len(m.Warnings) != tt.wantWarnings {
+				t.Fatalf("got %d warnings, want %d: %v", len(m.Warnings), tt.wantWarnings, m.Warnings)
+			}
+			if tt.wantWarnings > 0 && m.Warnings[0].Expr != sys {
+				t.Errorf("got warning on %s, want %s", m.Warnings[0].Expr.EvalName(), sys.EvalName())
+			}
+		})
+	}
+}
+
+func TestModelValidateDeprecated(t *testing.T) {
+	var (
+		user   = &Person{Element: &Element{Name: "Deprecated User"}}
+		sys    = &SoftwareSystem{Element: &Element{Name: "Deprecated System"}}
+		legacy = &Container{Element: &Element{Name: "Legacy"}, System: sys}
+		api    = &Container{Element: &Element{Name: "API"}, System: sys}
+		uses   = &Relationship{Source: user.Element, Destination: legacy.Element, Description: "Uses"}
+		calls  = &Relationship{Source: legacy.Element, Destination: api.Element, Description: "Calls"}
+	)
+	sys.Containers = Containers{legacy, api}
+	user.Relationships = []*Relationship{uses}
+	legacy.Relationships = []*Relationship{calls}
+	for _, e := range []interface{}{user, sys, legacy, api, uses, calls} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, sys.ID, legacy.ID, api.ID, uses.ID, calls.ID} {
+			delete(Registry, id)
+		}
+	}()
+	legacy.Deprecate()
+	legacy.Finalize()
+	m := &Model{People: People{user}, Systems: SoftwareSystems{sys}}
+
+	if err := m.Validate(); len(err.(*eval.ValidationErrors).Errors) != 0 {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !hasTag(legacy.Tags, TagDeprecated) {
+		t.Errorf("got tags %q, want %q", legacy.Tags, TagDeprecated)
+	}
+	if len(m.Warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1", len(m.Warnings))
+	}
+	if m.Warnings[0].Expr != uses {
+		t.Errorf("got warning on %s, want %s", m.Warnings[0].Expr.EvalName(), uses.EvalName())
+	}
+}
+
+func TestModelValidateDuplicateUses(t *testing.T) {
+	var (
+		user = &Person{Element: &Element{ID: "DuplicateUses User", Name: "DuplicateUses User"}}
+		sys  = &SoftwareSystem{Element: &Element{ID: "DuplicateUses System", Name: "DuplicateUses System"}}
+		rel  = func(loc string) *Relationship {
+			return &Relationship{Source: user.Element, Destination: sys.Element, Description: "Uses", DSLLocation: loc}
+		}
+		dup = rel("design.go:12")
+	)
+	user.Relationships = []*Relationship{rel("design.go:10"), dup, rel("other.go:5")}
+	m := &Model{People: People{user}, Systems: SoftwareSystems{sys}, WarnDuplicateUses: true}
+
+	m.Validate()
+
+	if len(m.Warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1", len(m.Warnings))
+	}
+	if m.Warnings[0].Expr != dup {
+		t.Errorf("got warning on %s, want %s", m.Warnings[0].Expr.EvalName(), dup.EvalName())
+	}
+	if !strings.Contains(m.Warnings[0].Message, "design.go:10") {
+		t.Errorf("got message %q, want reference to first declaration", m.Warnings[0].Message)
+	}
+}
+
+func TestModelFinalizeExplicitInstanceRelationships(t *testing.T) {
+	var (
+		sys   = &SoftwareSystem{Element: &Element{Name: "Instances System"}}
+		api   = &Container{Element: &Element{Name: "API"}, System: sys}
+		cache = &Container{Element: &Element{Name: "Cache"}, System: sys}
+		reads = &Relationship{Source: api.Element, Destination: cache.Element, Description: "Reads from"}
+		east  = &DeploymentNode{Element: &Element{Name: "Instances East"}, Environment: "Production"}
+		west  = &DeploymentNode{Element: &Element{Name: "Instances West"}, Environment: "Production"}
+		ids   []string
+	)
+	sys.Containers = Containers{api, cache}
+	api.Relationships = []*Relationship{reads}
+	for _, e := range []interface{}{sys, api, cache, reads, east, west} {
+		Identify(e)
+	}
+	newInstance := func(n *DeploymentNode, c *Container) *ContainerInstance {
+		ci := &ContainerInstance{Element: &Element{}, Parent: n, Container: c, ContainerID: c.ID, InstanceID: 1, Environment: n.Environment}
+		Identify(ci)
+		n.ContainerInstances = append(n.ContainerInstances, ci)
+		return ci
+	}
+	apiEast := newInstance(east, api)
+	cacheEast := newInstance(east, cache)
+	cacheWest := newInstance(west, cache)
+	explicitReads := &Relationship{Source: apiEast.Element, Destination: cacheEast.Element, Description: "Reads from"}
+	warms := &Relationship{Source: apiEast.Element, Destination: cacheEast.Element, Description: "Warms"}
+	apiEast.Relationships = []*Relationship{explicitReads, warms}
+	Identify(explicitReads)
+	Identify(warms)
+	defer func() {
+		for _, id := range ids {
+			delete(Registry, id)
+		}
+	}()
+	m := &Model{Systems: SoftwareSystems{sys}, DeploymentNodes: []*DeploymentNode{east, west}}
+
+	if err := m.Validate(); len(err.(*eval.ValidationErrors).Errors) != 0 {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	m.Finalize()
+
+	Iterate(func(e interface{}) {
+		switch x := e.(type) {
+		case ElementHolder:
+			ids = append(ids, x.GetElement().ID)
+		case *Relationship:
+			ids = append(ids, x.ID)
+		}
+	})
+	if Registry[explicitReads.ID] != explicitReads {
+		t.Errorf("explicit relationship was replaced in the registry")
+	}
+	if len(apiEast.Relationships) != 3 {
+		t.Fatalf("got %d relationships, want 3", len(apiEast.Relationships))
+	}
+	for i, want := range []*Element{cacheEast.Element, cacheEast.Element, cacheWest.Element} {
+		r := apiEast.Relationships[i]
+		if r.Destination != want {
+			t.Errorf("relationship %d: got destination %q, want %q", i, r.Destination.ID, want.ID)
+		}
+		if linked := r.LinkedRelationshipID != ""; linked != (i == 2) {
+			t.Errorf("relationship %d: got linked %v", i, linked)
+		}
+		if r.Environment != "Production" {
+			t.Errorf("relationship %d: got environment %q, want %q", i, r.Environment, "Production")
+		}
+	}
+}
+
+func TestModelFinalizeNoImply(t *testing.T) {
+	var (
+		sys     = &SoftwareSystem{Element: &Element{Name: "NoImply System"}}
+		other   = &SoftwareSystem{Element: &Element{Name: "NoImply Other"}}
+		api     = &Container{Element: &Element{Name: "API"}, System: sys}
+		cache   = &Container{Element: &Element{Name: "Cache"}, System: other}
+		reads   = &Relationship{Source: api.Element, Destination: cache.Element, Description: "Reads from", NoImply: true}
+		ids     []string
+		implied []*Relationship
+	)
+	sys.Containers = Containers{api}
+	other.Containers = Containers{cache}
+	api.Relationships = []*Relationship{reads}
+	for _, e := range []interface{}{sys, other, api, cache, reads} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range ids {
+			delete(Registry, id)
+		}
+	}()
+	m := &Model{Systems: SoftwareSystems{sys, other}, AddImpliedRelationships: true}
+
+	m.Finalize()
+
+	IterateRelationships(func(r *Relationship) {
+		if r.Implied {
+			implied = append(implied, r)
+		}
+	})
+	Iterate(func(e interface{}) {
+		switch x := e.(type) {
+		case ElementHolder:
+			ids = append(ids, x.GetElement().ID)
+		case *Relationship:
+			ids = append(ids, x.ID)
+		}
+	})
+	if len(implied) != 0 {
+		t.Errorf("got %d implied relationships, want 0", len(implied))
+	}
+	if len(sys.Relationships) != 0 {
+		t.Errorf("got %d software system relationships, want 0", len(sys.Relationships))
+	}
+}
+
+func TestModelCheck(t *testing.T) {
+	var (
+		user  = &Person{Element: &Element{Name: "Check User"}}
+		sys   = &SoftwareSystem{Element: &Element{Name: "Check System"}}
+		other = &SoftwareSystem{Element: &Element{Name: "Check Other"}}
+		api   = &Container{Element: &Element{Name: "API"}, System: sys}
+		cache = &Container{Element: &Element{Name: "Cache"}, System: other}
+		uses  = &Relationship{Source: user.Element, DestinationPath: "Check System/API", Description: "Uses"}
+		reads = &Relationship{Source: api.Element, Destination: cache.Element, Description: "Reads from"}
+	)
+	sys.Containers = Containers{api}
+	other.Containers = Containers{cache}
+	user.Relationships = []*Relationship{uses}
+	api.Relationships = []*Relationship{reads}
+	for _, e := range []interface{}{user, sys, other, api, cache, uses, reads} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, sys.ID, other.ID, api.ID, cache.ID, uses.ID, reads.ID} {
+			delete(Registry, id)
+		}
+	}()
+	m := &Model{People: People{user}, Systems: SoftwareSystems{sys, other}, AddImpliedRelationships: true}
+
+	if err := m.Check(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(m.ImpliedRelationships) != 0 || len(user.Relationships) != 1 || len(sys.Relationships) != 0 {
+		t.Errorf("got implied relationships added to the model")
+	}
+	if uses.Destination != nil {
+		t.Errorf("got resolved destination %q, want the model untouched", uses.Destination.Name)
+	}
+
+	uses.DestinationPath = "Check System/Unknown"
+	if err := m.Check(); err == nil {
+		t.Errorf("got no error for an unknown destination, want one")
+	}
+}
+
+func TestModelCheckDesign(t *testing.T) {
+	var (
+		user     = &Person{Element: &Element{Name: "Check Design User"}}
+		sys      = &SoftwareSystem{Element: &Element{Name: "Check Design System"}}
+		api      = &Container{Element: &Element{Name: "API"}, System: sys}
+		node     = &DeploymentNode{Element: &Element{Name: "Server"}, Environment: "Production"}
+		instance = &ContainerInstance{Element: &Element{}, Parent: node, InstanceID: 1, Environment: "Production"}
+		uses     = &Relationship{Source: user.Element, Destination: sys.Element, Description: "Uses"}
+	)
+	sys.Containers = Containers{api}
+	node.ContainerInstances = []*ContainerInstance{instance}
+	user.Relationships = []*Relationship{uses}
+	for _, e := range []interface{}{user, sys, api, node} {
+		Identify(e)
+	}
+	instance.ContainerID = api.ID
+	for _, e := range []interface{}{instance, uses} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, sys.ID, api.ID, node.ID, instance.ID, uses.ID} {
+			delete(Registry, id)
+		}
+	}()
+	defer func(m *Model, vs *Views) { Root.Model, Root.Views = m, vs }(Root.Model, Root.Views)
+	tests := []struct {
+		name        string
+		designModel bool
+		imageView   bool
+		scaling     string
+		wantErr     bool
+	}{
+		{"valid", true, false, "1", false},
+		{"invalid-view", true, true, "1", true},
+		{"invalid-view-not-design-model", false, true, "1", false},
+		{"invalid-instance", true, false, "one", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance.Properties = map[string]string{ScalingMinProperty: tt.scaling, ScalingMaxProperty: "2"}
+			link := &RelationshipView{Source: user.Element, Destination: sys.Element, Description: "Uses"}
+			views := &Views{ContextViews: []*ContextView{{
+				ViewProps: &ViewProps{
+					Key:               "context",
+					ElementViews:      []*ElementView{{Element: user.Element}, {Element: sys.Element}},
+					RelationshipViews: []*RelationshipView{link},
+				},
+				SoftwareSystemID: sys.ID,
+			}}}
+			if tt.imageView {
+				views.ImageViews = []*ImageView{{Key: "image", ElementID: sys.ID}}
+			}
+			m := &Model{People: People{user}, Systems: SoftwareSystems{sys}, DeploymentNodes: []*DeploymentNode{node}}
+			Root.Model, Root.Views = &Model{}, views
+			if tt.designModel {
+				Root.Model = m
+			}
+
+			err := m.Check()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tt.wantErr)
+			}
+			if link.RelationshipID != "" {
+				t.Errorf("got relationship view mapped to %q, want the view untouched", link.RelationshipID)
+			}
+		})
+	}
+}
+
+func TestModelWarningsAreErrors(t *testing.T) {
+	sys := &SoftwareSystem{Element: &Element{Name: "Warnings System", Description: "A description that is too long"}}
+	Identify(sys)
+	defer delete(Registry, sys.ID)
+	tests := []struct {
+		name              string
+		warningsAreErrors bool
+		wantErrors        int
+		wantSeverity      SeverityKind
+	}{
+		{"default", false, 0, SeverityWarning},
+		{"warnings-are-errors", true, 1, SeverityError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Model{Systems: SoftwareSystems{sys}, DescriptionMaxLength: 10, WarningsAreErrors: tt.warningsAreErrors}
+
+			err := m.Validate()
+
+			if got := len(err.(*eval.ValidationErrors).Errors); got != tt.wantErrors {
+				t.Errorf("got %d errors, want %d: %s", got, tt.wantErrors, err)
+			}
+			if len(m.Warnings) != 1 {
+				t.Fatalf("got %d warnings, want 1", len(m.Warnings))
+			}
+			if got := m.Warnings[0].Severity; got != tt.wantSeverity {
+				t.Errorf("got severity %s, want %s", got, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestModelAdd(t *testing.T) {
+	var (
+		user = &Person{Element: &Element{Name: "Add User"}}
+		sys  = &SoftwareSystem{Element: &Element{Name: "Add System"}}
+		api  = &Container{Element: &Element{Name: "Add API"}, System: sys}
+		m    = &Model{}
+	)
+	defer func() {
+		for _, e := range []*Element{user.Element, sys.Element} {
+			delete(Registry, e.ID)
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		eh      ElementHolder
+		wantErr bool
+	}{
+		{"person", user, false},
+		{"system", sys, false},
+		{"container", api, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Add(tt.eh)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got element %q", got.GetElement().Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.eh {
+				t.Errorf("got %v, want %v", got, tt.eh)
+			}
+		})
+	}
+	if len(m.People) != 1 || m.People[0] != user {
+		t.Errorf("got people %v, want only %q", m.People, user.Name)
+	}
+	if len(m.Systems) != 1 || m.Systems[0] != sys {
+		t.Errorf("got systems %v, want only %q", m.Systems, sys.Name)
+	}
+}
+
+func TestModelDescriptionTemplate(t *testing.T) {
+	var (
+		shop     = &SoftwareSystem{Element: &Element{Name: "Template Shop"}}
+		payments = &SoftwareSystem{Element: &Element{Name: "Template Payments"}}
+		calls    = &Relationship{Source: shop.Element, Destination: payments.Element, Templated: true}
+		reads    = &Relationship{Source: payments.Element, Destination: shop.Element, Description: "Reads from"}
+	)
+	shop.Relationships = []*Relationship{calls}
+	payments.Relationships = []*Relationship{reads}
+	for _, e := range []interface{}{shop, payments, calls, reads} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{shop.ID, payments.ID, calls.ID, reads.ID} {
+			delete(Registry, id)
+		}
+	}()
+	m := &Model{Systems: SoftwareSystems{shop, payments}, DescriptionTemplate: "{source} calls {destination}"}
+
+	if err := m.Validate(); len(err.(*eval.ValidationErrors).Errors) != 0 {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+	m.Finalize()
+
+	if want := "Template Shop calls Template Payments"; calls.Description != want {
+		t.Errorf("got description %q, want %q", calls.Description, want)
+	}
+	if reads.Description != "Reads from" {
+		t.Errorf("got description %q for relationship that is not templated, want %q", reads.Description, "Reads from")
+	}
+
+	m.DescriptionTemplate = "{source} calls {target}"
+	if err := m.Validate(); len(err.(*eval.ValidationErrors).Errors) != 1 {
+		t.Errorf("got %v, want an error for the unknown placeholder", err)
+	}
+}
+
+func TestModelRelate(t *testing.T) {
+	var (
+		web = &SoftwareSystem{Element: &Element{Name: "Relate Web"}}
+		sys = &SoftwareSystem{Element: &Element{Name: "Relate Shop"}}
+		api = &Container{Element: &Element{Name: "API"}, System: sys}
+		db  = &Container{Element: &Element{Name: "Database"}, System: sys}
+		m   = &Model{Systems: SoftwareSystems{web, sys}}
+	)
+	sys.Containers = Containers{api, db}
+	for _, e := range []interface{}{web, sys, api, db} {
+		Identify(e)
+	}
+	defer func() {
+		for _, e := range []*Element{web.Element, sys.Element, api.Element, db.Element} {
+			for _, r := range e.Relationships {
+				delete(Registry, r.ID)
+			}
+			delete(Registry, e.ID)
+		}
+	}()
+
+	_, err := m.Relate(
+		[2]string{"Relate Web", "Relate Shop/Unknown"},
+		[2]string{"Missing", "Relate Shop/API"},
+		[2]string{"Relate Web", "Relate Shop/API"},
+	)
+	if err == nil {
+		t.Fatal("got no error for unresolved paths, want one")
+	}
+	if got := len(err.(*eval.ValidationErrors).Errors); got != 2 {
+		t.Errorf("got %d errors, want 2: %s", got, err)
+	}
+	if len(web.Relationships) != 0 {
+		t.Errorf("got %d relationships created despite errors, want 0", len(web.Relationships))
+	}
+
+	rels, err := m.Relate(
+		[2]string{"Relate Web", "Relate Shop/API"},
+		[2]string{"Relate Shop/API", "Relate Shop/Database"},
+		[2]string{"Relate Web", "Relate Shop/API"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rels) != 2 {
+		t.Fatalf("got %d relationships, want 2", len(rels))
+	}
+	for _, p := range [][2]string{{"Relate Web", "Relate Shop/API"}, {"Relate Shop/API", "Relate Shop/Database"}} {
+		if !m.HasRelationship(p[0], p[1], DefaultRelateDescription) {
+			t.Errorf("missing relationship %s -> %s", p[0], p[1])
+		}
+	}
+	if _, ok := Registry[rels[0].ID]; !ok {
+		t.Errorf("relationship %q not registered", rels[0].ID)
+	}
+}
+
+func TestModelImpliedMode(t *testing.T) {
+	var (
+		shop     = &SoftwareSystem{Element: &Element{Name: "Implied Shop"}}
+		payments = &SoftwareSystem{Element: &Element{Name: "Implied Payments"}}
+		api      = &Container{Element: &Element{Name: "API"}, System: shop}
+		gateway  = &Container{Element: &Element{Name: "Gateway"}, System: payments}
+		orders   = &Component{Element: &Element{Name: "Orders"}, Container: api}
+		charges  = &Component{Element: &Element{Name: "Charges"}, Container: gateway}
+		charge   = &Relationship{Source: orders.Element, Destination: charges.Element, Description: "Charges"}
+	)
+	shop.Containers = Containers{api}
+	payments.Containers = Containers{gateway}
+	api.Components = Components{orders}
+	gateway.Components = Components{charges}
+	orders.Relationships = []*Relationship{charge}
+	for _, e := range []interface{}{shop, payments, api, gateway, orders, charges, charge} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{shop.ID, payments.ID, api.ID, gateway.ID, orders.ID, charges.ID, charge.ID} {
+			delete(Registry, id)
+		}
+	}()
+	tests := []struct {
+		name string
+		mode ImpliedModeKind
+		want []string
+	}{
+		{"full", ImpliedFull, []string{
+			"API -> Charges", "API -> Gateway", "API -> Implied Payments",
+			"Implied Shop -> Charges", "Implied Shop -> Gateway", "Implied Shop -> Implied Payments",
+		}},
+		{"direct-parent-only", ImpliedDirectParentOnly, []string{"API -> Charges", "Orders -> Gateway"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Model{
+				Systems:                      SoftwareSystems{shop, payments},
+				AddImpliedRelationships:      true,
+				SeparateImpliedRelationships: true,
+				ImpliedMode:                  tt.mode,
+			}
+
+			m.Finalize()
+
+			var got []string
+			for _, r := range m.ImpliedRelationships {
+				got = append(got, r.Source.Name+" -> "+r.Destination.Name)
+				delete(Registry, r.ID)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("got implied relationships %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelImpliedFromPeopleAndSystems(t *testing.T) {
+	var (
+		user     = &Person{Element: &Element{Name: "Implied User"}}
+		shop     = &SoftwareSystem{Element: &Element{Name: "Implied Shop"}}
+		web      = &Container{Element: &Element{Name: "Web"}, System: shop}
+		cart     = &Component{Element: &Element{Name: "Cart"}, Container: web}
+		browses  = &Relationship{Source: user.Element, Destination: web.Element, Description: "Browses"}
+		internal = &Relationship{Source: shop.Element, Destination: cart.Element, Description: "Updates"}
+	)
+	shop.Containers = Containers{web}
+	web.Components = Components{cart}
+	user.Relationships = []*Relationship{browses}
+	shop.Relationships = []*Relationship{internal}
+	for _, e := range []interface{}{user, shop, web, cart, browses, internal} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, shop.ID, web.ID, cart.ID, browses.ID, internal.ID} {
+			delete(Registry, id)
+		}
+	}()
+	m := &Model{
+		People:                       People{user},
+		Systems:                      SoftwareSystems{shop},
+		AddImpliedRelationships:      true,
+		SeparateImpliedRelationships: true,
+	}
+
+	m.Finalize()
+
+	var got []string
+	for _, r := range m.ImpliedRelationships {
+		got = append(got, r.Source.Name+" -> "+r.Destination.Name)
+		delete(Registry, r.ID)
+	}
+	sort.Strings(got)
+	want := []string{"Implied Shop -> Web", "Implied User -> Implied Shop"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got implied relationships %v, want %v", got, want)
+	}
+	if rels := m.ElementRelationships(user.Element); len(rels) != 2 {
+		t.Errorf("got %d relationships for %q, want the declared and the implied relationships", len(rels), user.Name)
+	}
+}
+
+func TestModelFlow(t *testing.T) {
+	var (
+		shop    = &SoftwareSystem{Element: &Element{ID: "Flow Shop", Name: "Flow Shop"}}
+		api     = &Container{Element: &Element{ID: "Flow API", Name: "API"}, System: shop}
+		pay     = &SoftwareSystem{Element: &Element{ID: "Flow Payments", Name: "Flow Payments"}}
+		node    = &DeploymentNode{Element: &Element{ID: "Flow Node", Name: "Node"}, Environment: "Production"}
+		inst    = &ContainerInstance{Element: &Element{ID: "Flow Instance"}, ContainerID: api.ID, Environment: "Production"}
+		charge  = &Relationship{Source: api.Element, Destination: pay.Element, Description: "Charges", Tags: FlowTagPrefix + "checkout"}
+		implied = &Relationship{Source: shop.Element, Destination: pay.Element, Description: "Charges", Tags: charge.Tags, Implied: true}
+		linked  = &Relationship{Source: inst.Element, Destination: pay.Element, Description: "Charges", Tags: charge.Tags, LinkedRelationshipID: "charge"}
+	)
+	shop.Containers = Containers{api}
+	node.ContainerInstances = ContainerInstances{inst}
+	api.Relationships = []*Relationship{charge}
+	shop.Relationships = []*Relationship{implied}
+	inst.Relationships = []*Relationship{linked}
+	m := &Model{Systems: SoftwareSystems{shop, pay}, DeploymentNodes: []*DeploymentNode{node}, WarnUnstyledRelationshipTags: true}
+
+	got := m.Flow("checkout")
+
+	if len(got) != 1 || got[0] != charge {
+		t.Errorf("got %d relationships, want only the declared relationship", len(got))
+	}
+	m.Validate()
+	for _, w := range m.Warnings {
+		if strings.Contains(w.Message, FlowTagPrefix) {
+			t.Errorf("got warning %q, want flow tags exempt", w.Message)
+		}
+	}
+}
+
+func TestModelNormalizeNames(t *testing.T) {
+	var (
+		svc   = &SoftwareSystem{Element: &Element{Name: "Payment Service"}}
+		near  = &SoftwareSystem{Element: &Element{Name: "payment  service"}}
+		other = &SoftwareSystem{Element: &Element{Name: " Billing "}}
+	)
+	for _, e := range []interface{}{svc, near, other} {
+		Identify(e)
+	}
+	defer func() {
+		for _, e := range []*Element{svc.Element, near.Element, other.Element} {
+			delete(Registry, e.ID)
+		}
+	}()
+	tests := []struct {
+		name      string
+		normalize bool
+		want      []eval.Expression
+	}{
+		{"disabled", false, nil},
+		{"enabled", true, []eval.Expression{near, other}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Model{Systems: SoftwareSystems{svc, near, other}, NormalizeNames: tt.normalize}
+
+			m.Validate()
+
+			if len(m.Warnings) != len(tt.want) {
+				t.Fatalf("got %d warnings, want %d: %v", len(m.Warnings), len(tt.want), m.Warnings)
+			}
+			for i, w := range m.Warnings {
+				if w.Expr != tt.want[i] {
+					t.Errorf("got warning %d for %s, want %s", i, w.Expr.EvalName(), tt.want[i].EvalName())
+				}
+			}
+			if other.Name != " Billing " {
+				t.Errorf("got name %q, want the name left untouched", other.Name)
+			}
+		})
+	}
+}
+
+func TestModelCheckNormalizeNames(t *testing.T) {
+	var (
+		svc  = &SoftwareSystem{Element: &Element{Name: "Ledger Service"}}
+		near = &SoftwareSystem{Element: &Element{Name: " ledger service "}}
+	)
+	for _, e := range []interface{}{svc, near} {
+		Identify(e)
+	}
+	defer func() {
+		for _, e := range []*Element{svc.Element, near.Element} {
+			delete(Registry, e.ID)
+		}
+	}()
+	tests := []struct {
+		name              string
+		warningsAreErrors bool
+		wantErr           bool
+	}{
+		{"warnings", false, false},
+		{"warnings-are-errors", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Model{Systems: SoftwareSystems{svc, near}, NormalizeNames: true, WarningsAreErrors: tt.warningsAreErrors}
+
+			err := m.Check()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tt.wantErr)
+			}
+			if near.Name != " ledger service " {
+				t.Errorf("got name %q, want the name left untouched", near.Name)
+			}
+			if len(m.Warnings) != 0 {
+				t.Errorf("got %d warnings, want the warnings reverted", len(m.Warnings))
+			}
+		})
+	}
+}
+
+func TestModelRemoveElement(t *testing.T) {
+	var (
+		user     = &Person{Element: &Element{Name: "Remove User"}}
+		sys      = &SoftwareSystem{Element: &Element{Name: "Remove System"}}
+		api      = &Container{Element: &Element{Name: "API"}, System: sys}
+		db       = &Container{Element: &Element{Name: "Database"}, System: sys}
+		handler  = &Component{Element: &Element{Name: "Handler"}, Container: api}
+		node     = &DeploymentNode{Element: &Element{Name: "Server"}, Environment: "Production"}
+		instance = &ContainerInstance{Element: &Element{}, Parent: node, ContainerID: "", InstanceID: 1, Environment: "Production"}
+		calls    = &Relationship{Source: user.Element, Destination: api.Element, Description: "Calls"}
+		reads    = &Relationship{Source: handler.Element, Destination: db.Element, Description: "Reads from"}
+		writes   = &Relationship{Source: db.Element, Destination: handler.Element, Description: "Notifies"}
+	)
+	sys.Containers = Containers{api, db}
+	api.Components = Components{handler}
+	node.ContainerInstances = []*ContainerInstance{instance}
+	user.Relationships = []*Relationship{calls}
+	handler.Relationships = []*Relationship{reads}
+	db.Relationships = []*Relationship{writes}
+	for _, e := range []interface{}{user, sys, api, db, handler, node} {
+		Identify(e)
+	}
+	instance.ContainerID = api.ID
+	for _, e := range []interface{}{instance, calls, reads, writes} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, sys.ID, api.ID, db.ID, handler.ID, node.ID, instance.ID, calls.ID, reads.ID, writes.ID} {
+			delete(Registry, id)
+		}
+	}()
+	componentView := &ComponentView{ViewProps: &ViewProps{Key: "components", ElementViews: []*ElementView{{Element: handler.Element}}}, ContainerID: api.ID}
+	containerView := &ContainerView{
+		ViewProps: &ViewProps{
+			Key:               "containers",
+			ElementViews:      []*ElementView{{Element: user.Element}, {Element: api.Element}, {Element: db.Element}},
+			RelationshipViews: []*RelationshipView{{Source: user.Element, Destination: api.Element, RelationshipID: calls.ID}},
+		},
+		SoftwareSystemID: sys.ID,
+	}
+	defer func(vs *Views) { Root.Views = vs }(Root.Views)
+	Root.Views = &Views{ContainerViews: []*ContainerView{containerView}, ComponentViews: []*ComponentView{componentView}}
+	m := &Model{People: People{user}, Systems: SoftwareSystems{sys}, DeploymentNodes: []*DeploymentNode{node}}
+
+	if err := m.RemoveElement(api); err == nil {
+		t.Fatal("got no error when removing the scope of a view, want one")
+	}
+	if len(sys.Containers) != 2 || Registry[handler.ID] == nil {
+		t.Fatal("got model modified despite error")
+	}
+
+	Root.Views.ComponentViews = nil
+	if err := m.RemoveElement(api); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(sys.Containers) != 1 || sys.Containers[0] != db {
+		t.Errorf("got containers %v, want only %q", sys.Containers, db.Name)
+	}
+	for _, id := range []string{api.ID, handler.ID, instance.ID, calls.ID, reads.ID, writes.ID} {
+		if _, ok := Registry[id]; ok {
+			t.Errorf("got %q still registered", id)
+		}
+	}
+	if len(user.Relationships) != 0 || len(db.Relationships) != 0 {
+		t.Errorf("got relationships to removed elements: %d from user, %d from database", len(user.Relationships), len(db.Relationships))
+	}
+	if len(node.ContainerInstances) != 0 {
+		t.Errorf("got %d container instances, want 0", len(node.ContainerInstances))
+	}
+	if len(containerView.ElementViews) != 2 || len(containerView.RelationshipViews) != 0 {
+		t.Errorf("got %d element views and %d relationship views, want 2 and 0", len(containerView.ElementViews), len(containerView.RelationshipViews))
+	}
+	if err := m.RemoveElement(api); err == nil {
+		t.Error("got no error when removing an element twice, want one")
+	}
+}
@@ -0,0 +1,149 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+type (
+	// openAPISpec is the subset of an OpenAPI (or Swagger) document used by
+	// FromOpenAPI.
+	openAPISpec struct {
+		OpenAPI string                                `json:"openapi"`
+		Swagger string                                `json:"swagger"`
+		Tags    []*openAPITag                         `json:"tags"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+
+	// openAPITag is an OpenAPI tag object.
+	openAPITag struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		DependsOn   []string `json:"x-depends-on"`
+	}
+
+	// openAPIOperation is the subset of an OpenAPI operation object used by
+	// FromOpenAPI.
+	openAPIOperation struct {
+		Tags      []string `json:"tags"`
+		DependsOn []string `json:"x-depends-on"`
+	}
+)
+
+// openAPIMethods lists the keys of OpenAPI path items that describe
+// operations.
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// FromOpenAPI creates components in the given container from the JSON encoded
+// OpenAPI (or Swagger) document at path. FromOpenAPI creates one component
+// per tag declared in the document or used by an operation. The component
+// description is the tag description. FromOpenAPI also creates a "Uses"
+// relationship from a component to each component listed in the
+// "x-depends-on" extension of the tag or of the operations tagged with it.
+// Components that already exist in the container are reused.
+func FromOpenAPI(path string, into *Container) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var spec openAPISpec
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return fmt.Errorf("failed to parse OpenAPI document %q: %s", path, err)
+	}
+	if spec.OpenAPI == "" && spec.Swagger == "" {
+		return fmt.Errorf("invalid OpenAPI document %q: missing openapi or swagger version", path)
+	}
+	if spec.Paths == nil {
+		return fmt.Errorf("invalid OpenAPI document %q: missing paths", path)
+	}
+
+	// Collect tags and dependencies.
+	var names []string
+	descs := make(map[string]string)
+	deps := make(map[string][]string)
+	addTag := func(name string) {
+		if _, ok := descs[name]; !ok {
+			descs[name] = ""
+			names = append(names, name)
+		}
+	}
+	for _, t := range spec.Tags {
+		if t.Name == "" {
+			return fmt.Errorf("invalid OpenAPI document %q: tag with no name", path)
+		}
+		addTag(t.Name)
+		descs[t.Name] = t.Description
+		deps[t.Name] = append(deps[t.Name], t.DependsOn...)
+	}
+	paths := make([]string, 0, len(spec.Paths))
+	for p := range spec.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		item := spec.Paths[p]
+		methods := make([]string, 0, len(item))
+		for m := range item {
+			if openAPIMethods[m] {
+				methods = append(methods, m)
+			}
+		}
+		sort.Strings(methods)
+		for _, m := range methods {
+			var op openAPIOperation
+			if err := json.Unmarshal(item[m], &op); err != nil {
+				return fmt.Errorf("invalid OpenAPI document %q: operation %s %s: %s", path, m, p, err)
+			}
+			for _, t := range op.Tags {
+				addTag(t)
+				deps[t] = append(deps[t], op.DependsOn...)
+			}
+		}
+	}
+
+	// Create components.
+	cmps := make(map[string]*Component, len(names))
+	for _, name := range names {
+		cmp := into.Component(name)
+		if cmp == nil {
+			cmp = into.AddComponent(&Component{
+				Element:   &Element{Name: name, Description: descs[name]},
+				Container: into,
+			})
+		} else if cmp.Description == "" {
+			cmp.Description = descs[name]
+		}
+		cmps[name] = cmp
+	}
+
+	// Create relationships.
+	for _, name := range names {
+		src := cmps[name]
+		seen := make(map[string]bool)
+	deps:
+		for _, dep := range deps[name] {
+			if seen[dep] || dep == name {
+				continue
+			}
+			seen[dep] = true
+			dest, ok := cmps[dep]
+			if !ok {
+				return fmt.Errorf("invalid OpenAPI document %q: tag %q depends on unknown tag %q", path, name, dep)
+			}
+			for _, r := range src.Relationships {
+				if r.Destination == dest.Element && r.Description == "Uses" {
+					continue deps
+				}
+			}
+			rel := &Relationship{Source: src.Element, Destination: dest.Element, Description: "Uses"}
+			Identify(rel)
+			src.Relationships = append(src.Relationships, rel)
+		}
+	}
+	return nil
+}
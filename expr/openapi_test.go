@@ -0,0 +1,82 @@
+package expr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFromOpenAPI(t *testing.T) {
+	const spec = `{
+		"openapi": "3.0.3",
+		"tags": [
+			{"name": "orders", "description": "Order management", "x-depends-on": ["inventory"]},
+			{"name": "inventory", "description": "Stock levels"}
+		],
+		"paths": {
+			"/orders": {
+				"get": {"tags": ["orders"]},
+				"post": {"tags": ["orders"], "x-depends-on": ["payments"]}
+			},
+			"/payments": {
+				"parameters": [],
+				"post": {"tags": ["payments"]}
+			}
+		}
+	}`
+	dir, err := ioutil.TempDir("", "openapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "openapi.json")
+	if err := ioutil.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sys := &SoftwareSystem{Element: &Element{Name: "FromOpenAPI System"}}
+	Identify(sys)
+	api := &Container{Element: &Element{Name: "API"}, System: sys}
+	Identify(api)
+	defer func() {
+		ids := []string{sys.ID, api.ID}
+		for _, c := range api.Components {
+			ids = append(ids, c.ID)
+			for _, r := range c.Relationships {
+				ids = append(ids, r.ID)
+			}
+		}
+		for _, id := range ids {
+			delete(Registry, id)
+		}
+	}()
+
+	if err := FromOpenAPI(path, api); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ name, desc string }{
+		{"orders", "Order management"},
+		{"inventory", "Stock levels"},
+		{"payments", ""},
+	}
+	if len(api.Components) != len(want) {
+		t.Fatalf("got %d components, want %d", len(api.Components), len(want))
+	}
+	for i, w := range want {
+		if c := api.Components[i]; c.Name != w.name || c.Description != w.desc {
+			t.Errorf("got component %q (%q), want %q (%q)", c.Name, c.Description, w.name, w.desc)
+		}
+	}
+	rels := api.Components[0].Relationships
+	if len(rels) != 2 || rels[0].Destination.Name != "inventory" || rels[1].Destination.Name != "payments" {
+		t.Errorf("got relationships %v, want orders -> inventory and orders -> payments", rels)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`{"paths": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := FromOpenAPI(path, api); err == nil {
+		t.Error("expected an error for a document with no version")
+	}
+}
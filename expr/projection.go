@@ -0,0 +1,79 @@
+package expr
+
+// LandscapeProjection returns a new model that only contains the people and
+// software systems of m. The relationships of containers and components are
+// rolled up to their software systems: a relationship from or to a container or
+// a component becomes a relationship from or to the parent software system.
+// Relationships between elements of the same software system are dropped and
+// relationships with the same source, destination and description are only
+// kept once. Deployment nodes are not included.
+//
+// The elements and relationships of the projection are copies that keep the
+// IDs of the original elements and relationships, the projection does not
+// modify m or the registry. The ID of a rolled up relationship is the ID of the
+// first relationship it was computed from.
+func (m *Model) LandscapeProjection() *Model {
+	res := &Model{Enterprise: m.Enterprise}
+	top := make(map[string]*Element)
+	for _, p := range m.People {
+		elem := *p.Element
+		elem.Relationships = nil
+		top[p.ID] = &elem
+		res.People = append(res.People, &Person{Element: &elem, Location: p.Location})
+	}
+	for _, s := range m.Systems {
+		elem := *s.Element
+		elem.Relationships = nil
+		top[s.ID] = &elem
+		for _, c := range s.Containers {
+			top[c.ID] = &elem
+			for _, cmp := range c.Components {
+				top[cmp.ID] = &elem
+			}
+		}
+		res.Systems = append(res.Systems, &SoftwareSystem{Element: &elem, Location: s.Location, InFocus: s.InFocus})
+	}
+
+	seen := make(map[string]bool)
+	rollup := func(e *Element) {
+		for _, r := range m.ElementRelationships(e) {
+			if r.Destination == nil {
+				continue
+			}
+			src, dest := top[r.Source.ID], top[r.Destination.ID]
+			if src == nil || dest == nil || src == dest {
+				continue
+			}
+			key := src.ID + ":" + dest.ID + ":" + r.Description
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			rel := *r
+			rel.Source = src
+			rel.Destination = dest
+			rel.Implied = false
+			rel.LinkedRelationshipID = ""
+			src.Relationships = append(src.Relationships, &rel)
+		}
+	}
+	for _, p := range m.People {
+		rollup(p.Element)
+	}
+	for _, s := range m.Systems {
+		rollup(s.Element)
+	}
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			rollup(c.Element)
+		}
+	}
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			for _, cmp := range c.Components {
+				rollup(cmp.Element)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,107 @@
+package expr
+
+import "regexp"
+
+// Query describes the conditions used by Model.Query to select elements. All
+// the conditions that are set must be met for an element to be selected, an
+// empty query selects all the elements.
+type Query struct {
+	// Types lists the accepted element types: "Person", "Software System",
+	// "Container", "Component", "Deployment Node", "Infrastructure Node"
+	// or "Container Instance". Any type is accepted if empty.
+	Types []string
+	// Tags lists the tags the element must have.
+	Tags []string
+	// Name is matched against the element name if not nil.
+	Name *regexp.Regexp
+	// HasRelationships selects the elements that are the source or the
+	// destination of at least one relationship if true and the elements
+	// that are neither if false. Implied relationships and relationships
+	// replicated onto container instances are not taken into account.
+	HasRelationships *bool
+}
+
+// Query returns the elements of the model that satisfy all the conditions of
+// q, for example the containers tagged "payments":
+//
+//    containers := m.Query(Query{Types: []string{"Container"}, Tags: []string{"payments"}})
+//
+// The elements are returned in model order: people, software systems each
+// followed by its containers each followed by its components, then the
+// deployment nodes each followed by its infrastructure nodes, container
+// instances and children.
+func (m *Model) Query(q Query) []ElementHolder {
+	var related map[string]bool
+	if q.HasRelationships != nil {
+		related = make(map[string]bool)
+		for _, e := range m.allElements() {
+			for _, r := range m.ElementRelationships(e) {
+				if r.Implied || r.LinkedRelationshipID != "" {
+					continue
+				}
+				related[r.Source.ID] = true
+				if r.Destination != nil {
+					related[r.Destination.ID] = true
+				}
+			}
+		}
+	}
+	var res []ElementHolder
+	add := func(eh ElementHolder) {
+		if q.matches(eh, related) {
+			res = append(res, eh)
+		}
+	}
+	for _, p := range m.People {
+		add(p)
+	}
+	for _, s := range m.Systems {
+		add(s)
+		for _, c := range s.Containers {
+			add(c)
+			for _, cmp := range c.Components {
+				add(cmp)
+			}
+		}
+	}
+	eachDeploymentNode(m.DeploymentNodes, func(n *DeploymentNode) {
+		add(n)
+		for _, i := range n.InfrastructureNodes {
+			add(i)
+		}
+		for _, ci := range n.ContainerInstances {
+			add(ci)
+		}
+	})
+	return res
+}
+
+// matches returns true if eh satisfies all the conditions of q. related
+// indexes the IDs of the elements that have relationships.
+func (q Query) matches(eh ElementHolder, related map[string]bool) bool {
+	e := eh.GetElement()
+	if len(q.Types) > 0 {
+		kind, found := elementKind(eh), false
+		for _, t := range q.Types {
+			if t == kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, tag := range q.Tags {
+		if !hasTag(e.Tags, tag) {
+			return false
+		}
+	}
+	if q.Name != nil && !q.Name.MatchString(e.Name) {
+		return false
+	}
+	if q.HasRelationships != nil && related[e.ID] != *q.HasRelationships {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestModelQuery(t *testing.T) {
+	t.Parallel()
+	var (
+		user     = &Person{Element: &Element{ID: "1", Name: "User", Tags: "payments"}}
+		sys      = &SoftwareSystem{Element: &Element{ID: "2", Name: "Shop", Tags: "payments"}}
+		gateway  = &Container{Element: &Element{ID: "3", Name: "Payment Gateway", Tags: "Element,Container,payments"}, System: sys}
+		ledger   = &Container{Element: &Element{ID: "4", Name: "Ledger", Tags: "payments,storage"}, System: sys}
+		catalog  = &Container{Element: &Element{ID: "5", Name: "Catalog"}, System: sys}
+		refunds  = &Component{Element: &Element{ID: "6", Name: "Refunds", Tags: "payments"}, Container: gateway}
+		pays     = &Relationship{Source: user.Element, Destination: gateway.Element, Description: "Pays using"}
+		yes, no  = true, false
+		payments = []string{"payments"}
+	)
+	sys.Containers = Containers{gateway, ledger, catalog}
+	gateway.Components = Components{refunds}
+	user.Relationships = []*Relationship{pays}
+	m := &Model{People: People{user}, Systems: SoftwareSystems{sys}}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []ElementHolder
+	}{
+		{"type-and-tag", Query{Types: []string{"Container"}, Tags: payments}, []ElementHolder{gateway, ledger}},
+		{"tag", Query{Tags: payments}, []ElementHolder{user, sys, gateway, refunds, ledger}},
+		{"tags", Query{Tags: []string{"payments", "storage"}}, []ElementHolder{ledger}},
+		{"name", Query{Name: regexp.MustCompile("^Pay")}, []ElementHolder{gateway}},
+		{"related", Query{Tags: payments, HasRelationships: &yes}, []ElementHolder{user, gateway}},
+		{"unrelated", Query{Types: []string{"Container"}, HasRelationships: &no}, []ElementHolder{ledger, catalog}},
+		{"no-match", Query{Types: []string{"Deployment Node"}}, nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := m.Query(tt.query)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d elements, want %d", len(got), len(tt.want))
+			}
+			for i, eh := range tt.want {
+				if got[i] != eh {
+					t.Errorf("got element %d %q, want %q", i, got[i].GetElement().Name, eh.GetElement().Name)
+				}
+			}
+		})
+	}
+}
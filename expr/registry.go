@@ -10,6 +10,9 @@ import (
 // Registry captures all the elements, people and relationships.
 var Registry = make(map[string]interface{})
 
+// Aliases maps element aliases to the corresponding elements, see Alias.
+var Aliases = make(map[string]ElementHolder)
+
 // Iterate iterates through all elements, people and relationships in the
 // registry in a consistent order.
 func Iterate(visitor func(elem interface{})) {
@@ -38,20 +41,22 @@ func IterateRelationships(visitor func(r *Relationship)) {
 // Identify sets the ID field of the given element or relationship and registers
 // it with the global registery. The algorithm first compute a unique moniker
 // for the element or relatioship (based on names and parent scope ID) then
-// hashes and base36 encodes the result.
+// hashes and base36 encodes the result. Identify prefixes the result with the
+// type of the element (e.g. "sys-", "cont-" or "rel-") if Model.PrefixIDs is
+// true.
 func Identify(element interface{}) {
 	switch e := element.(type) {
 	case *Person:
-		e.ID = idify(e.Name)
+		e.ID = prefixID("person-", idify(e.Name))
 		Registry[e.ID] = e
 	case *SoftwareSystem:
-		e.ID = idify(e.Name)
+		e.ID = prefixID("sys-", idify(e.Name))
 		Registry[e.ID] = e
 	case *Container:
-		e.ID = idify(e.System.ID + ":" + e.Name)
+		e.ID = prefixID("cont-", idify(e.System.ID+":"+e.Name))
 		Registry[e.ID] = e
 	case *Component:
-		e.ID = idify(e.Container.ID + ":" + e.Name)
+		e.ID = prefixID("comp-", idify(e.Container.ID+":"+e.Name))
 		Registry[e.ID] = e
 	case *DeploymentNode:
 		prefix := "dn:"
@@ -60,13 +65,13 @@ func Identify(element interface{}) {
 			prefix += f.ID + ":"
 			f = f.Parent
 		}
-		e.ID = idify(prefix + e.Name)
+		e.ID = prefixID("node-", idify(prefix+e.Name))
 		Registry[e.ID] = e
 	case *InfrastructureNode:
-		e.ID = idify(e.Parent.ID + ":" + e.Name)
+		e.ID = prefixID("infra-", idify(e.Parent.ID+":"+e.Name))
 		Registry[e.ID] = e
 	case *ContainerInstance:
-		e.ID = idify(e.Parent.ID + ":" + e.ContainerID)
+		e.ID = prefixID("inst-", idify(e.Parent.ID+":"+e.ContainerID))
 		Registry[e.ID] = e
 	case *Relationship:
 		var dest string
@@ -75,13 +80,21 @@ func Identify(element interface{}) {
 		} else {
 			dest = e.DestinationPath
 		}
-		e.ID = idify(e.Source.ID + ":" + dest + ":" + e.Description)
+		e.ID = prefixID("rel-", idify(e.Source.ID+":"+dest+":"+e.Description))
 		Registry[e.ID] = e
 	default:
 		panic(fmt.Sprintf("element of type %T does not have an ID", element)) // bug
 	}
 }
 
+// prefixID prepends prefix to id if the model is configured to prefix IDs.
+func prefixID(prefix, id string) string {
+	if Root.Model == nil || !Root.Model.PrefixIDs {
+		return id
+	}
+	return prefix + id
+}
+
 var h = fnv.New32a()
 
 func idify(s string) string {
@@ -0,0 +1,65 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+)
+
+// DefaultRelateDescription is the description of the relationships created
+// by Model.Relate.
+const DefaultRelateDescription = "Uses"
+
+// Relate creates a relationship with description DefaultRelateDescription for
+// each source and destination pair. The paths must be fully qualified, see
+// FindElement. Relate makes it possible to bootstrap a model from existing
+// dependency data (an adjacency list), for example:
+//
+//    rels, err := m.Relate(
+//        [2]string{"Web App", "API/Orders"},
+//        [2]string{"API/Orders", "Database"},
+//    )
+//
+// Pairs whose relationship already exists are skipped. Relate validates all
+// the paths before creating any relationship: it returns the validation
+// errors for every path that does not resolve and leaves the model untouched
+// in this case. Relate returns the relationships it created otherwise.
+func (m *Model) Relate(pairs ...[2]string) ([]*Relationship, error) {
+	verr := new(eval.ValidationErrors)
+	type edge struct{ src, dest *Element }
+	var edges []edge
+	for _, pair := range pairs {
+		src, err := m.FindElement(nil, pair[0])
+		if err != nil {
+			verr.Add(m, "Relate: invalid source %q: %s", pair[0], err)
+		}
+		dest, derr := m.FindElement(nil, pair[1])
+		if derr != nil {
+			verr.Add(m, "Relate: invalid destination %q: %s", pair[1], derr)
+		}
+		if err != nil || derr != nil {
+			continue
+		}
+		edges = append(edges, edge{src.GetElement(), dest.GetElement()})
+	}
+	if len(verr.Errors) > 0 {
+		return nil, verr
+	}
+	var rels []*Relationship
+loop:
+	for _, e := range edges {
+		for _, r := range e.src.Relationships {
+			if r.Destination != nil && r.Destination.ID == e.dest.ID && r.Description == DefaultRelateDescription {
+				continue loop
+			}
+		}
+		r := &Relationship{
+			Description: DefaultRelateDescription,
+			Source:      e.src,
+			Destination: e.dest,
+		}
+		r.RecordDeclaration()
+		Identify(r)
+		e.src.Relationships = append(e.src.Relationships, r)
+		rels = append(rels, r)
+	}
+	return rels, nil
+}
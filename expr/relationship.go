@@ -27,6 +27,49 @@ type (
 		// container corresponding to the container instance with this
 		// relationship.
 		LinkedRelationshipID string
+
+		// Environment is the deployment environment of the container
+		// instances, deployment nodes or infrastructure nodes linked by
+		// the relationship if any.
+		Environment string
+
+		// CrossEnvironment allows a relationship between deployment nodes
+		// or infrastructure nodes of different deployment environments.
+		CrossEnvironment bool
+
+		// Implied is true if the relationship was not defined explicitly in
+		// the design but added because of a relationship defined between
+		// children of the source or destination elements.
+		Implied bool
+
+		// DSLLocation is the file:line of the DSL that declared the
+		// relationship if known.
+		DSLLocation string
+
+		// NoImply excludes the relationship from the generation of implied
+		// relationships between the parents of its source and destination.
+		NoImply bool
+
+		// Templated causes the description of the relationship to be
+		// computed from the model DescriptionTemplate if it is empty.
+		Templated bool
+
+		// Order is the position of the relationship relative to the other
+		// relationships between the same elements in views, 0 if unset.
+		Order int
+
+		// Weight expresses the volume of interactions carried by the
+		// relationship, 0 if unset. Exporters may render relationships with
+		// a higher weight with thicker lines.
+		Weight int
+
+		// Interface is the name of the interface of the destination
+		// component targeted by the relationship if any.
+		Interface string
+
+		// seq is the declaration order of the relationship, 0 if the
+		// relationship was not declared with the DSL.
+		seq int
 	}
 
 	// InteractionStyleKind is the enum for possible interaction styles.
@@ -42,6 +85,30 @@ const (
 	InteractionAsynchronous
 )
 
+const (
+	// TagSynchronous is the tag added to synchronous relationships.
+	TagSynchronous = "Synchronous"
+	// TagAsynchronous is the tag added to asynchronous relationships.
+	TagAsynchronous = "Asynchronous"
+)
+
+// DefaultRelationshipWeight is the weight of relationships that do not
+// define one.
+const DefaultRelationshipWeight = 1
+
+// relationshipSeq is the sequence number of the last relationship declared
+// with RecordDeclaration.
+var relationshipSeq int
+
+// RecordDeclaration assigns the next declaration sequence number to the
+// relationship. The DSL calls RecordDeclaration when a relationship is
+// declared so that exporters may list relationships in declaration order, see
+// Model.RelationshipsInDeclarationOrder.
+func (r *Relationship) RecordDeclaration() {
+	relationshipSeq++
+	r.seq = relationshipSeq
+}
+
 // EvalName is the qualified name of the expression.
 func (r *Relationship) EvalName() string {
 	var src, dest = "<unknown source>", "<unknown destination>"
@@ -54,14 +121,21 @@ func (r *Relationship) EvalName() string {
 	return fmt.Sprintf("relationship %q [%s -> %s]", r.Description, src, dest)
 }
 
-// Finalize computes the destination and adds the "Relationship" tag.
+// Finalize adds the "Relationship" tag as well as the "Synchronous" or
+// "Asynchronous" tag if the interaction style is defined.
 func (r *Relationship) Finalize() {
 	r.MergeTags("Relationship")
+	switch r.InteractionStyle {
+	case InteractionSynchronous:
+		r.MergeTags(TagSynchronous)
+	case InteractionAsynchronous:
+		r.MergeTags(TagAsynchronous)
+	}
 }
 
-// Dup creates a new relationship with identical description, tags, URL,
-// technology and interaction style as r. Dup also creates a new ID for the
-// result.
+// Dup creates a new relationship between newSrc and newDest with identical
+// description, tags, URL, technology, interaction style, order and weight as
+// r. Dup also creates a new ID for the result.
 func (r *Relationship) Dup(newSrc, newDest *Element) *Relationship {
 	dup := &Relationship{
 		Source:           newSrc,
@@ -71,11 +145,35 @@ func (r *Relationship) Dup(newSrc, newDest *Element) *Relationship {
 		Destination:      newDest,
 		Description:      r.Description,
 		Technology:       r.Technology,
+		Order:            r.Order,
+		Weight:           r.Weight,
 	}
 	Identify(dup)
 	return dup
 }
 
+// EffectiveWeight returns the weight of the relationship or
+// DefaultRelationshipWeight if the weight is not set.
+func (r *Relationship) EffectiveWeight() int {
+	if r.Weight == 0 {
+		return DefaultRelationshipWeight
+	}
+	return r.Weight
+}
+
+// Label returns the text exporters use to label the relationship given the
+// description to render. Label appends the relationship technology in brackets
+// if appendTechnology is true and the relationship defines a technology.
+func (r *Relationship) Label(description string, appendTechnology bool) string {
+	if !appendTechnology || r.Technology == "" {
+		return description
+	}
+	if description == "" {
+		return "[" + r.Technology + "]"
+	}
+	return description + " [" + r.Technology + "]"
+}
+
 // MergeTags adds the given tags. It skips tags already present in e.Tags.
 func (r *Relationship) MergeTags(tags ...string) {
 	r.Tags = mergeTags(r.Tags, tags)
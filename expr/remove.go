@@ -0,0 +1,235 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+)
+
+// RemoveElement removes the given element from the model together with its
+// descendants (e.g. the containers and components of a software system or the
+// children of a deployment node), the container instances of the removed
+// containers and all the relationships from or to the removed elements,
+// including implied relationships. The removed elements and relationships are
+// also removed from the registry, the aliases and the views of the design.
+//
+// RemoveElement returns an error and leaves the model untouched if the element
+// is not part of the model, if a view is scoped to one of the removed elements
+// (e.g. the component view of a removed container) or if the removal would
+// leave a view that has elements without any element.
+func (m *Model) RemoveElement(eh ElementHolder) error {
+	if eh == nil || eh.GetElement() == nil {
+		return fmt.Errorf("RemoveElement: element cannot be nil")
+	}
+	name := eh.GetElement().Name
+	if ex, ok := eh.(eval.Expression); ok {
+		name = ex.EvalName()
+	}
+	if Registry[eh.GetElement().ID] != eh {
+		return fmt.Errorf("RemoveElement: %s is not part of the model", name)
+	}
+	removed := make(map[string]bool)
+	var mark func(ElementHolder)
+	mark = func(eh ElementHolder) {
+		removed[eh.GetElement().ID] = true
+		switch e := eh.(type) {
+		case *SoftwareSystem:
+			for _, c := range e.Containers {
+				mark(c)
+			}
+		case *Container:
+			for _, c := range e.Components {
+				mark(c)
+			}
+		case *DeploymentNode:
+			for _, c := range e.Children {
+				mark(c)
+			}
+			for _, i := range e.InfrastructureNodes {
+				mark(i)
+			}
+			for _, ci := range e.ContainerInstances {
+				mark(ci)
+			}
+		}
+	}
+	mark(eh)
+	eachDeploymentNode(m.DeploymentNodes, func(n *DeploymentNode) {
+		for _, ci := range n.ContainerInstances {
+			if removed[ci.ContainerID] {
+				removed[ci.ID] = true
+			}
+		}
+	})
+	if err := checkRemovedViews(removed); err != nil {
+		return fmt.Errorf("RemoveElement: cannot remove %s: %s", name, err)
+	}
+
+	switch e := eh.(type) {
+	case *Person:
+		var people People
+		for _, p := range m.People {
+			if p != e {
+				people = append(people, p)
+			}
+		}
+		m.People = people
+	case *SoftwareSystem:
+		var systems SoftwareSystems
+		for _, s := range m.Systems {
+			if s != e {
+				systems = append(systems, s)
+			}
+		}
+		m.Systems = systems
+	case *Container:
+		var containers Containers
+		for _, c := range e.System.Containers {
+			if c != e {
+				containers = append(containers, c)
+			}
+		}
+		e.System.Containers = containers
+	case *Component:
+		var components Components
+		for _, c := range e.Container.Components {
+			if c != e {
+				components = append(components, c)
+			}
+		}
+		e.Container.Components = components
+	case *DeploymentNode:
+		var nodes []*DeploymentNode
+		siblings := m.DeploymentNodes
+		if e.Parent != nil {
+			siblings = e.Parent.Children
+		}
+		for _, n := range siblings {
+			if n != e {
+				nodes = append(nodes, n)
+			}
+		}
+		if e.Parent != nil {
+			e.Parent.Children = nodes
+		} else {
+			m.DeploymentNodes = nodes
+		}
+	case *InfrastructureNode:
+		var infs []*InfrastructureNode
+		for _, i := range e.Parent.InfrastructureNodes {
+			if i != e {
+				infs = append(infs, i)
+			}
+		}
+		e.Parent.InfrastructureNodes = infs
+	}
+	eachDeploymentNode(m.DeploymentNodes, func(n *DeploymentNode) {
+		var cis []*ContainerInstance
+		for _, ci := range n.ContainerInstances {
+			if !removed[ci.ID] {
+				cis = append(cis, ci)
+			}
+		}
+		n.ContainerInstances = cis
+	})
+
+	m.removeRelationships(removed)
+	for id := range removed {
+		delete(Registry, id)
+	}
+	for alias, a := range Aliases {
+		if removed[a.GetElement().ID] {
+			delete(Aliases, alias)
+		}
+	}
+	if Root.Views != nil {
+		for _, v := range Root.Views.All() {
+			pruneRemovedElements(v.Props(), removed)
+		}
+	}
+	m.invalidateIndex()
+	return nil
+}
+
+// eachDeploymentNode calls fn for each of the given deployment nodes and
+// their descendants.
+func eachDeploymentNode(nodes []*DeploymentNode, fn func(*DeploymentNode)) {
+	for _, n := range nodes {
+		fn(n)
+		eachDeploymentNode(n.Children, fn)
+	}
+}
+
+// checkRemovedViews returns an error if a view of the design root is scoped to
+// one of the removed elements or if removing the elements would leave a view
+// without any element.
+func checkRemovedViews(removed map[string]bool) error {
+	if Root.Views == nil {
+		return nil
+	}
+	for _, iv := range Root.Views.ImageViews {
+		if removed[iv.ElementID] {
+			return fmt.Errorf("view %q is scoped to it", iv.Key)
+		}
+	}
+	for _, v := range Root.Views.All() {
+		vp := v.Props()
+		if removed[viewScopeID(v)] {
+			return fmt.Errorf("view %q is scoped to it", vp.Key)
+		}
+		if len(vp.ElementViews) == 0 {
+			continue
+		}
+		empty := true
+		for _, ev := range vp.ElementViews {
+			if !removed[ev.Element.ID] {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			return fmt.Errorf("view %q would not contain any element", vp.Key)
+		}
+	}
+	return nil
+}
+
+// pruneRemovedElements removes the removed elements and the relationships from
+// or to them from the given view.
+func pruneRemovedElements(vp *ViewProps, removed map[string]bool) {
+	var evs []*ElementView
+	for _, ev := range vp.ElementViews {
+		if !removed[ev.Element.ID] {
+			evs = append(evs, ev)
+		}
+	}
+	vp.ElementViews = evs
+	var rvs []*RelationshipView
+	for _, rv := range vp.RelationshipViews {
+		if !removed[rv.Source.ID] && !removed[rv.Destination.ID] {
+			rvs = append(rvs, rv)
+		}
+	}
+	vp.RelationshipViews = rvs
+	for _, step := range vp.AnimationSteps {
+		var elems []ElementHolder
+		for _, eh := range step.Elements {
+			if !removed[eh.GetElement().ID] {
+				elems = append(elems, eh)
+			}
+		}
+		step.Elements = elems
+		var ids []string
+		for _, id := range step.RelationshipIDs {
+			if _, ok := Registry[id]; ok {
+				ids = append(ids, id)
+			}
+		}
+		step.RelationshipIDs = ids
+	}
+	for id := range vp.ElementStyles {
+		if removed[id] {
+			delete(vp.ElementStyles, id)
+		}
+	}
+}
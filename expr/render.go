@@ -2,6 +2,8 @@ package expr
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -38,6 +40,26 @@ func addAllElements(view View) {
 	}
 }
 
+// addAllTaggedElements adds the elements added by addAllElements that have all
+// the required tags and none of the excluded tags. Elements already in the view
+// are kept.
+func addAllTaggedElements(view View, required, excluded []string) {
+	vp := view.Props()
+	existing := make(map[string]bool, len(vp.ElementViews))
+	for _, ev := range vp.ElementViews {
+		existing[ev.Element.ID] = true
+	}
+	addAllElements(view)
+	i := 0
+	for _, ev := range vp.ElementViews {
+		if existing[ev.Element.ID] || matchTags(ev.Element.Tags, required, excluded) {
+			vp.ElementViews[i] = ev
+			i++
+		}
+	}
+	vp.ElementViews = vp.ElementViews[:i]
+}
+
 // addDefaultElements adds a default set of elements and relationships for the
 // given view.
 func addDefaultElements(view View) {
@@ -46,7 +68,12 @@ func addDefaultElements(view View) {
 	case *LandscapeView:
 		addAllElements(v)
 	case *ContextView:
-		addNeighbors(Registry[v.SoftwareSystemID].(*SoftwareSystem).Element, v)
+		// The software system in scope and the people and software systems
+		// it is directly connected to, relationships are added by
+		// addMissingElementsAndRelationships.
+		s := Registry[v.SoftwareSystemID].(*SoftwareSystem)
+		v.AddElements(s)
+		addNeighbors(s.Element, v)
 	case *ContainerView:
 		s := Registry[v.SoftwareSystemID].(*SoftwareSystem)
 		v.AddElements(s.Containers.Elements()...)
@@ -133,12 +160,12 @@ func addInfluencers(cv *ContainerView) {
 	system := Registry[cv.SoftwareSystemID].(*SoftwareSystem)
 	m := Root.Model
 	for _, s := range m.Systems {
-		for _, r := range s.Relationships {
+		for _, r := range m.ElementRelationships(s.Element) {
 			if r.Destination.ID == cv.SoftwareSystemID {
 				cv.AddElements(s)
 			}
 		}
-		for _, r := range system.Relationships {
+		for _, r := range m.ElementRelationships(system.Element) {
 			if r.Destination.ID == s.ID {
 				cv.AddElements(s)
 			}
@@ -146,12 +173,12 @@ func addInfluencers(cv *ContainerView) {
 	}
 
 	for _, p := range m.People {
-		for _, r := range p.Relationships {
+		for _, r := range m.ElementRelationships(p.Element) {
 			if r.Destination.ID == cv.SoftwareSystemID {
 				cv.AddElements(p)
 			}
 		}
-		for _, r := range system.Relationships {
+		for _, r := range m.ElementRelationships(system.Element) {
 			if r.Destination.ID == p.ID {
 				cv.AddElements(p)
 			}
@@ -245,10 +272,38 @@ func removeElements(vp *ViewProps, elems ...*Element) {
 	}
 }
 
+// subtree returns the given element followed by all its descendants.
+func subtree(e *Element) []*Element {
+	res := []*Element{e}
+	switch eh := Registry[e.ID].(type) {
+	case *SoftwareSystem:
+		for _, c := range eh.Containers {
+			res = append(res, subtree(c.Element)...)
+		}
+	case *Container:
+		for _, c := range eh.Components {
+			res = append(res, c.Element)
+		}
+	case *DeploymentNode:
+		for _, c := range eh.Children {
+			res = append(res, subtree(c.Element)...)
+		}
+		for _, i := range eh.InfrastructureNodes {
+			res = append(res, i.Element)
+		}
+		for _, ci := range eh.ContainerInstances {
+			res = append(res, ci.Element)
+		}
+	}
+	return res
+}
+
+// removeRelationship removes the views of relationship r from the view and
+// keeps all the other relationship views.
 func removeRelationship(vp *ViewProps, r *Relationship) {
 	i := 0
 	for _, rv := range vp.RelationshipViews {
-		if rv.RelationshipID == r.ID {
+		if rv.RelationshipID != r.ID {
 			vp.RelationshipViews[i] = rv
 			i++
 		}
@@ -456,3 +511,57 @@ func tagged(v *ViewProps, tag string) (elems []*Element) {
 	}
 	return
 }
+
+// removeOtherEnvironmentRelationships removes the relationship views that link
+// container instances of a deployment environment other than the view's.
+func removeOtherEnvironmentRelationships(dv *DeploymentView) {
+	i := 0
+	for _, rv := range dv.RelationshipViews {
+		if r, ok := Registry[rv.RelationshipID].(*Relationship); ok && r.Environment != "" && r.Environment != dv.Environment {
+			continue
+		}
+		dv.RelationshipViews[i] = rv
+		i++
+	}
+	dv.RelationshipViews = dv.RelationshipViews[:i]
+}
+
+// sortRelationshipViews sorts the relationship views that share the same
+// source and destination: relationships with an explicit order come first
+// sorted by order followed by the others sorted by description. Groups of
+// relationships between different elements keep their relative positions.
+// sortRelationshipViews also sets the order of relationship views to the order
+// of the corresponding relationship if any.
+func sortRelationshipViews(vp *ViewProps) {
+	pairs := make(map[string]int)
+	orders := make(map[*RelationshipView]int)
+	for _, rv := range vp.RelationshipViews {
+		key := rv.Source.ID + ":" + rv.Destination.ID
+		if _, ok := pairs[key]; !ok {
+			pairs[key] = len(pairs)
+		}
+		if r, ok := Registry[rv.RelationshipID].(*Relationship); ok && r.Order > 0 {
+			orders[rv] = r.Order
+			if rv.Order == "" {
+				rv.Order = strconv.Itoa(r.Order)
+			}
+		}
+	}
+	sort.SliceStable(vp.RelationshipViews, func(i, j int) bool {
+		a, b := vp.RelationshipViews[i], vp.RelationshipViews[j]
+		pa, pb := pairs[a.Source.ID+":"+a.Destination.ID], pairs[b.Source.ID+":"+b.Destination.ID]
+		if pa != pb {
+			return pa < pb
+		}
+		oa, oka := orders[a]
+		ob, okb := orders[b]
+		switch {
+		case oka && okb:
+			return oa < ob
+		case oka != okb:
+			return oka
+		default:
+			return a.Description < b.Description
+		}
+	})
+}
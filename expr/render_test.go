@@ -0,0 +1,134 @@
+package expr
+
+import "testing"
+
+func TestRemoveSubtree(t *testing.T) {
+	var (
+		user = &Person{Element: &Element{Name: "RemoveSubtree User"}}
+		sys  = &SoftwareSystem{Element: &Element{Name: "RemoveSubtree System"}}
+		api  = &Container{Element: &Element{Name: "API"}, System: sys}
+		db   = &Container{Element: &Element{Name: "DB"}, System: sys}
+	)
+	sys.Containers = Containers{api, db}
+	for _, e := range []interface{}{user, sys, api, db} {
+		Identify(e)
+	}
+	defer func() {
+		for _, e := range []ElementHolder{user, sys, api, db} {
+			delete(Registry, e.GetElement().ID)
+		}
+	}()
+	rel := &Relationship{ID: "RemoveSubtree", Source: user.Element, Destination: api.Element}
+	vp := &ViewProps{
+		ElementViews: []*ElementView{
+			{Element: user.Element},
+			{Element: sys.Element},
+			{Element: api.Element},
+			{Element: db.Element},
+		},
+		RelationshipViews: []*RelationshipView{
+			{Source: user.Element, Destination: api.Element, RelationshipID: rel.ID},
+		},
+	}
+
+	removeElements(vp, subtree(sys.Element)...)
+
+	if len(vp.ElementViews) != 1 || vp.ElementViews[0].Element != user.Element {
+		var names []string
+		for _, ev := range vp.ElementViews {
+			names = append(names, ev.Element.Name)
+		}
+		t.Errorf("got elements %v, want only %q", names, user.Name)
+	}
+	if len(vp.RelationshipViews) != 0 {
+		t.Errorf("got %d relationships, want 0", len(vp.RelationshipViews))
+	}
+}
+
+func TestRemoveRelationship(t *testing.T) {
+	t.Parallel()
+	var (
+		user   = &Element{ID: "RemoveRelationship User"}
+		sys    = &Element{ID: "RemoveRelationship System"}
+		reads  = &Relationship{ID: "RemoveRelationship Reads", Source: user, Destination: sys, Description: "Reads"}
+		writes = &Relationship{ID: "RemoveRelationship Writes", Source: user, Destination: sys, Description: "Writes"}
+	)
+	vp := &ViewProps{RelationshipViews: []*RelationshipView{
+		{Source: user, Destination: sys, Description: reads.Description, RelationshipID: reads.ID},
+		{Source: user, Destination: sys, Description: writes.Description, RelationshipID: writes.ID},
+	}}
+
+	removeRelationship(vp, reads)
+
+	if len(vp.RelationshipViews) != 1 || vp.RelationshipViews[0].RelationshipID != writes.ID {
+		var ids []string
+		for _, rv := range vp.RelationshipViews {
+			ids = append(ids, rv.RelationshipID)
+		}
+		t.Errorf("got relationships %v, want only %q", ids, writes.ID)
+	}
+}
+
+func TestSortRelationshipViews(t *testing.T) {
+	var (
+		user   = &Element{ID: "SortRelationshipViews User"}
+		sys    = &Element{ID: "SortRelationshipViews System"}
+		reads  = &Relationship{ID: "SortRelationshipViews Reads", Source: user, Destination: sys, Description: "Reads", Order: 2}
+		writes = &Relationship{ID: "SortRelationshipViews Writes", Source: user, Destination: sys, Description: "Writes", Order: 1}
+		admin  = &Relationship{ID: "SortRelationshipViews Admin", Source: user, Destination: sys, Description: "Administers"}
+	)
+	for _, r := range []*Relationship{reads, writes, admin} {
+		Registry[r.ID] = r
+	}
+	defer func() {
+		for _, r := range []*Relationship{reads, writes, admin} {
+			delete(Registry, r.ID)
+		}
+	}()
+	var rvs []*RelationshipView
+	for _, r := range []*Relationship{admin, reads, writes} {
+		rvs = append(rvs, &RelationshipView{Source: user, Destination: sys, Description: r.Description, RelationshipID: r.ID})
+	}
+	vp := &ViewProps{RelationshipViews: rvs}
+
+	sortRelationshipViews(vp)
+
+	want := []struct{ id, order string }{{writes.ID, "1"}, {reads.ID, "2"}, {admin.ID, ""}}
+	for i, w := range want {
+		rv := vp.RelationshipViews[i]
+		if rv.RelationshipID != w.id || rv.Order != w.order {
+			t.Errorf("got relationship %d %q with order %q, want %q with order %q", i, rv.RelationshipID, rv.Order, w.id, w.order)
+		}
+	}
+}
+
+func TestAddAllTaggedElements(t *testing.T) {
+	var (
+		partner = &SoftwareSystem{Element: &Element{Name: "AddAllTagged Partner", Tags: "Element,Software System,external"}}
+		legacy  = &SoftwareSystem{Element: &Element{Name: "AddAllTagged Legacy", Tags: "Element,Software System,external,deprecated"}}
+		core    = &SoftwareSystem{Element: &Element{Name: "AddAllTagged Core", Tags: "Element,Software System"}}
+		user    = &Person{Element: &Element{Name: "AddAllTagged User", Tags: "Element,Person,external"}}
+	)
+	for _, e := range []interface{}{partner, legacy, core, user} {
+		Identify(e)
+	}
+	model := Root.Model
+	Root.Model = &Model{People: People{user}, Systems: SoftwareSystems{partner, legacy, core}}
+	defer func() {
+		Root.Model = model
+		for _, e := range []ElementHolder{partner, legacy, core, user} {
+			delete(Registry, e.GetElement().ID)
+		}
+	}()
+	lv := &LandscapeView{ViewProps: &ViewProps{}}
+
+	addAllTaggedElements(lv, []string{"external"}, []string{"deprecated"})
+
+	var names []string
+	for _, ev := range lv.ElementViews {
+		names = append(names, ev.Element.Name)
+	}
+	if len(names) != 2 || names[0] != user.Name || names[1] != partner.Name {
+		t.Errorf("got elements %v, want %q and %q", names, user.Name, partner.Name)
+	}
+}
@@ -0,0 +1,371 @@
+package expr
+
+import (
+	"strings"
+)
+
+// Style is implemented by ElementStyle and RelationshipStyle.
+type Style interface {
+	// Matches returns true if the style applies to an element or
+	// relationship with the given comma separated list of tags.
+	Matches(tags string) bool
+}
+
+// Compound returns true if the style matches elements using required and
+// excluded tags rather than a single tag.
+func (es *ElementStyle) Compound() bool {
+	return len(es.RequiredTags) > 0 || len(es.ExcludedTags) > 0
+}
+
+// Matches returns true if the style applies to an element with the given comma
+// separated list of tags.
+func (es *ElementStyle) Matches(tags string) bool {
+	if es.Compound() {
+		return matchTags(tags, es.RequiredTags, es.ExcludedTags)
+	}
+	return hasTag(tags, es.Tag)
+}
+
+// Compound returns true if the style matches relationships using required and
+// excluded tags rather than a single tag.
+func (rs *RelationshipStyle) Compound() bool {
+	return len(rs.RequiredTags) > 0 || len(rs.ExcludedTags) > 0
+}
+
+// Matches returns true if the style applies to a relationship with the given
+// comma separated list of tags.
+func (rs *RelationshipStyle) Matches(tags string) bool {
+	if rs.Compound() {
+		return matchTags(tags, rs.RequiredTags, rs.ExcludedTags)
+	}
+	return hasTag(tags, rs.Tag)
+}
+
+// ResolvedStyle computes the style of the element by merging all the element
+// styles that apply to it. The precedence is deterministic and matches
+// Structurizr: styles keyed by a single tag are merged in the order of the
+// element tags so that the style of a tag that appears later in the element
+// tags overrides the fields set by the styles of the tags that appear before
+// it. Styles defined for the same tag are merged in the order they are
+// defined. Styles using required and excluded tags are merged last in the
+// order they are defined.
+func (e *Element) ResolvedStyle() *ElementStyle {
+	style := &ElementStyle{}
+	if Root.Views == nil || Root.Views.Styles == nil {
+		return style
+	}
+	styles := Root.Views.Styles.Elements
+	for _, tag := range strings.Split(e.Tags, ",") {
+		tag = strings.TrimSpace(tag)
+		for _, es := range styles {
+			if !es.Compound() && es.Tag == tag {
+				style.merge(es)
+			}
+		}
+	}
+	for _, es := range styles {
+		if es.Compound() && es.Matches(e.Tags) {
+			style.merge(es)
+		}
+	}
+	return style
+}
+
+// ResolvedStyle computes the style of the element in the view: the style of
+// the element (see Element.ResolvedStyle) overridden with the view-scoped
+// style of the element view if any.
+func (ev *ElementView) ResolvedStyle() *ElementStyle {
+	style := ev.Element.ResolvedStyle()
+	if ev.Style != nil {
+		style.merge(ev.Style)
+	}
+	return style
+}
+
+// UnusedStyles returns the element and relationship styles defined in the
+// views that match no element or relationship of the model. Styles are matched
+// against the effective tags (see AllEffectiveTags) so that styles keyed on
+// implicit tags such as "Container" are used even before the design is
+// finalized. Element styles are listed first, each group in definition order.
+func (m *Model) UnusedStyles() []Style {
+	if Root.Views == nil || Root.Views.Styles == nil {
+		return nil
+	}
+	var res []Style
+	elems := m.allElementHolders()
+	for _, es := range Root.Views.Styles.Elements {
+		used := false
+		for _, eh := range elems {
+			if es.Matches(effectiveTags(eh)) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			res = append(res, es)
+		}
+	}
+	var rels []*Relationship
+	for _, eh := range elems {
+		rels = append(rels, m.ElementRelationships(eh.GetElement())...)
+	}
+	for _, rs := range Root.Views.Styles.Relationships {
+		used := false
+		for _, r := range rels {
+			if rs.Matches(effectiveRelationshipTags(r)) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			res = append(res, rs)
+		}
+	}
+	return res
+}
+
+// DeprecatedStyle is the style applied to deprecated elements unless the
+// design defines a style for the "Deprecated" tag.
+var DeprecatedStyle = ElementStyle{
+	Tag:        TagDeprecated,
+	Background: "#eeeeee",
+	Color:      "#999999",
+	Stroke:     "#999999",
+	Border:     BorderDashed,
+}
+
+// addDeprecatedStyle adds DeprecatedStyle to the view styles if the model
+// contains deprecated elements and no style is defined for the "Deprecated"
+// tag.
+func (vs *Views) addDeprecatedStyle() {
+	if vs.Styles != nil {
+		for _, es := range vs.Styles.Elements {
+			if !es.Compound() && es.Tag == TagDeprecated {
+				return
+			}
+		}
+	}
+	deprecated := false
+	Iterate(func(e interface{}) {
+		if eh, ok := e.(ElementHolder); ok && eh.GetElement().Deprecated() {
+			deprecated = true
+		}
+	})
+	if !deprecated {
+		return
+	}
+	if vs.Styles == nil {
+		vs.Styles = &Styles{}
+	}
+	style := DeprecatedStyle
+	vs.Styles.Elements = append(vs.Styles.Elements, &style)
+}
+
+// merge copies the fields set in other into es.
+func (es *ElementStyle) merge(other *ElementStyle) {
+	if other.Background != "" {
+		es.Background = other.Background
+	}
+	if other.Stroke != "" {
+		es.Stroke = other.Stroke
+	}
+	if other.Color != "" {
+		es.Color = other.Color
+	}
+	if other.Shape != ShapeUndefined {
+		es.Shape = other.Shape
+	}
+	if other.Icon != "" {
+		es.Icon = other.Icon
+	}
+	if other.Opacity != nil {
+		es.Opacity = other.Opacity
+	}
+	if other.Metadata != nil {
+		es.Metadata = other.Metadata
+	}
+	if other.Description != nil {
+		es.Description = other.Description
+	}
+	if other.Border != BorderUndefined {
+		es.Border = other.Border
+	}
+}
+
+// dedupElementStyles merges the element styles that target the same tag into
+// the first one. The fields set by later definitions override the fields set
+// by earlier ones. This happens when composing designs that share style
+// packages. Conflicting definitions are reported when the model is validated,
+// see validateStyleConflicts.
+func dedupElementStyles(styles *Styles) {
+	if styles == nil {
+		return
+	}
+	byTag := make(map[string]*ElementStyle)
+	i := 0
+	for _, es := range styles.Elements {
+		first, ok := byTag[es.Tag]
+		if !ok {
+			byTag[es.Tag] = es
+			styles.Elements[i] = es
+			i++
+			continue
+		}
+		first.merge(es)
+	}
+	styles.Elements = styles.Elements[:i]
+}
+
+// validateStyleConflicts records a warning for each element style defined
+// multiple times for the same tag, or the same required and excluded tags,
+// with different values for the same field. The warning lists the conflicting
+// fields. The styles are merged when the views are finalized, see
+// dedupElementStyles.
+func (m *Model) validateStyleConflicts() {
+	if Root.Views == nil || Root.Views.Styles == nil {
+		return
+	}
+	type definition struct {
+		first  *ElementStyle
+		merged *ElementStyle
+	}
+	byTag := make(map[string]*definition)
+	for _, es := range Root.Views.Styles.Elements {
+		key := es.Tag
+		if es.Compound() {
+			key = compoundTag(es.RequiredTags, es.ExcludedTags)
+		}
+		def, ok := byTag[key]
+		if !ok {
+			merged := &ElementStyle{}
+			merged.merge(es)
+			byTag[key] = &definition{first: es, merged: merged}
+			continue
+		}
+		if conflicts := def.merged.conflicts(es); len(conflicts) > 0 {
+			m.warn(def.first, "style is defined multiple times with different values for %s, using the last definition", strings.Join(conflicts, ", "))
+		}
+		def.merged.merge(es)
+	}
+}
+
+// conflicts returns the names of the fields set in both es and other to
+// different values.
+func (es *ElementStyle) conflicts(other *ElementStyle) []string {
+	differ := func(a, b string) bool { return a != "" && b != "" && a != b }
+	var res []string
+	if es.Shape != ShapeUndefined && other.Shape != ShapeUndefined && es.Shape != other.Shape {
+		res = append(res, "Shape")
+	}
+	if differ(es.Icon, other.Icon) {
+		res = append(res, "Icon")
+	}
+	if differ(es.Background, other.Background) {
+		res = append(res, "Background")
+	}
+	if differ(es.Color, other.Color) {
+		res = append(res, "Color")
+	}
+	if differ(es.Stroke, other.Stroke) {
+		res = append(res, "Stroke")
+	}
+	if es.Metadata != nil && other.Metadata != nil && *es.Metadata != *other.Metadata {
+		res = append(res, "Metadata")
+	}
+	if es.Description != nil && other.Description != nil && *es.Description != *other.Description {
+		res = append(res, "Description")
+	}
+	if es.Opacity != nil && other.Opacity != nil && *es.Opacity != *other.Opacity {
+		res = append(res, "Opacity")
+	}
+	if es.Border != BorderUndefined && other.Border != BorderUndefined && es.Border != other.Border {
+		res = append(res, "Border")
+	}
+	return res
+}
+
+// finalizeCompoundStyles gives each style that uses required and excluded tags
+// a synthetic tag and adds the tag to all the matching elements and
+// relationships. This makes it possible to render these styles with tools that
+// key styles by a single tag (e.g. the Structurizr service).
+func finalizeCompoundStyles(styles *Styles) {
+	if styles == nil {
+		return
+	}
+	for _, es := range styles.Elements {
+		if !es.Compound() {
+			continue
+		}
+		es.Tag = compoundTag(es.RequiredTags, es.ExcludedTags)
+		Iterate(func(e interface{}) {
+			if eh, ok := e.(ElementHolder); ok && es.Matches(eh.GetElement().Tags) {
+				eh.GetElement().MergeTags(es.Tag)
+			}
+		})
+	}
+	for _, rs := range styles.Relationships {
+		if !rs.Compound() {
+			continue
+		}
+		rs.Tag = compoundTag(rs.RequiredTags, rs.ExcludedTags)
+		IterateRelationships(func(r *Relationship) {
+			if rs.Matches(r.Tags) {
+				r.MergeTags(rs.Tag)
+			}
+		})
+	}
+}
+
+// propagateEndpointTags adds the tags listed in the styles endpoint tags to
+// the relationships whose source or destination has the tag. The tags are
+// prefixed with the corresponding prefix.
+func propagateEndpointTags(styles *Styles) {
+	if styles == nil || len(styles.EndpointTags) == 0 {
+		return
+	}
+	IterateRelationships(func(r *Relationship) {
+		for _, et := range styles.EndpointTags {
+			for _, tag := range et.Tags {
+				if hasTag(r.Source.Tags, tag) || r.Destination != nil && hasTag(r.Destination.Tags, tag) {
+					r.MergeTags(et.Prefix + tag)
+				}
+			}
+		}
+	})
+}
+
+// compoundTag returns the synthetic tag used to identify a style with the given
+// required and excluded tags, e.g. "Container+!Database".
+func compoundTag(required, excluded []string) string {
+	parts := append([]string{}, required...)
+	for _, ex := range excluded {
+		parts = append(parts, "!"+ex)
+	}
+	return strings.Join(parts, "+")
+}
+
+// matchTags returns true if tags contains all the required tags and none of
+// the excluded tags.
+func matchTags(tags string, required, excluded []string) bool {
+	for _, r := range required {
+		if !hasTag(tags, r) {
+			return false
+		}
+	}
+	for _, ex := range excluded {
+		if hasTag(tags, ex) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTag returns true if the comma separated list of tags contains tag.
+func hasTag(tags, tag string) bool {
+	for _, t := range strings.Split(tags, ",") {
+		if strings.TrimSpace(t) == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+)
+
+func TestElementStyleMatches(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		style *ElementStyle
+		tags  string
+		want  bool
+	}{
+		{"tag", &ElementStyle{Tag: "Container"}, "Element,Container", true},
+		{"missing-tag", &ElementStyle{Tag: "Person"}, "Element,Container", false},
+		{"required", &ElementStyle{RequiredTags: []string{"Container"}}, "Element,Container", true},
+		{"negated", &ElementStyle{RequiredTags: []string{"Container"}, ExcludedTags: []string{"Database"}}, "Element,Container", true},
+		{"negated-excluded", &ElementStyle{RequiredTags: []string{"Container"}, ExcludedTags: []string{"Database"}}, "Element,Container,Database", false},
+		{"negated-only", &ElementStyle{ExcludedTags: []string{"Database"}}, "Element,Person", true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.style.Matches(tt.tags); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestElementResolvedStyle(t *testing.T) {
+	styles := &Styles{Elements: []*ElementStyle{
+		{RequiredTags: []string{"Container"}, ExcludedTags: []string{"Database"}, Shape: ShapeRoundedBox},
+		{Tag: "Container", Shape: ShapeBox, Background: "#ffffff"},
+		{Tag: "Database", Shape: ShapeCylinder},
+	}}
+	defer func(s *Styles) { Root.Views.Styles = s }(Root.Views.Styles)
+	Root.Views.Styles = styles
+
+	api := &Element{Tags: "Element,Container"}
+	db := &Element{Tags: "Element,Container,Database"}
+
+	if got := api.ResolvedStyle(); got.Shape != ShapeRoundedBox || got.Background != "#ffffff" {
+		t.Errorf("got shape %d and background %q, want %d and %q", got.Shape, got.Background, ShapeRoundedBox, "#ffffff")
+	}
+	if got := db.ResolvedStyle(); got.Shape != ShapeCylinder {
+		t.Errorf("got shape %d, want %d", got.Shape, ShapeCylinder)
+	}
+}
+
+func TestElementResolvedStylePrecedence(t *testing.T) {
+	styles := &Styles{Elements: []*ElementStyle{
+		{Tag: "Critical", Background: "#ff0000", Shape: ShapeHexagon},
+		{Tag: "Legacy", Background: "#999999"},
+		{Tag: "Critical", Color: "#ffffff"},
+	}}
+	defer func(s *Styles) { Root.Views.Styles = s }(Root.Views.Styles)
+	Root.Views.Styles = styles
+	tests := []struct {
+		tags           string
+		wantBackground string
+	}{
+		{"Element,Critical,Legacy", "#999999"},
+		{"Element,Legacy,Critical", "#ff0000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tags, func(t *testing.T) {
+			e := &Element{Tags: tt.tags}
+
+			got := e.ResolvedStyle()
+
+			if got.Background != tt.wantBackground {
+				t.Errorf("got background %q, want %q", got.Background, tt.wantBackground)
+			}
+			if got.Shape != ShapeHexagon || got.Color != "#ffffff" {
+				t.Errorf("got shape %d and color %q, want %d and %q", got.Shape, got.Color, ShapeHexagon, "#ffffff")
+			}
+		})
+	}
+}
+
+func TestModelUnusedStyles(t *testing.T) {
+	var (
+		database = &ElementStyle{Tag: "Database"}
+		legacy   = &ElementStyle{Tag: "Legacy"}
+		cont     = &ElementStyle{Tag: "Container"}
+		async    = &RelationshipStyle{Tag: "Asynchronous"}
+		rel      = &RelationshipStyle{Tag: "Relationship"}
+		styles   = &Styles{
+			Elements:      []*ElementStyle{database, legacy, cont},
+			Relationships: []*RelationshipStyle{rel, async},
+		}
+	)
+	defer func(s *Styles) { Root.Views.Styles = s }(Root.Views.Styles)
+	Root.Views.Styles = styles
+	sys := &SoftwareSystem{Element: &Element{Name: "System", Tags: "Element,Software System"}}
+	db := &Container{Element: &Element{Name: "DB", Tags: "Database"}, System: sys}
+	sys.Containers = Containers{db}
+	sys.Relationships = []*Relationship{{Source: sys.Element, Destination: db.Element}}
+	m := &Model{Systems: SoftwareSystems{sys}}
+
+	got := m.UnusedStyles()
+
+	if len(got) != 2 {
+		t.Fatalf("got %d unused styles, want 2", len(got))
+	}
+	if got[0] != Style(legacy) {
+		t.Errorf("got %v, want element style %q", got[0], legacy.Tag)
+	}
+	if got[1] != Style(async) {
+		t.Errorf("got %v, want relationship style %q", got[1], async.Tag)
+	}
+}
+
+func TestModelValidateStyleConflicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		second     *ElementStyle
+		wantErrors int
+	}{
+		{"conflict", &ElementStyle{Tag: "Database", Background: "#000000"}, 1},
+		{"no-conflict", &ElementStyle{Tag: "Database", Color: "#000000"}, 0},
+		{"other-tag", &ElementStyle{Tag: "Queue", Background: "#000000"}, 0},
+	}
+	defer func(s *Styles) { Root.Views.Styles = s }(Root.Views.Styles)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Root.Views.Styles = &Styles{Elements: []*ElementStyle{{Tag: "Database", Background: "#ffffff"}, tt.second}}
+			m := &Model{WarningsAreErrors: true}
+
+			err := m.Validate()
+
+			if got := len(err.(*eval.ValidationErrors).Errors); got != tt.wantErrors {
+				t.Errorf("got %d errors, want %d: %s", got, tt.wantErrors, err)
+			}
+			if tt.wantErrors > 0 && !strings.Contains(err.Error(), "different values for Background") {
+				t.Errorf("got error %q, want it to list the Background field", err.Error())
+			}
+			if got := len(Root.Views.Styles.Elements); got != 2 {
+				t.Errorf("got %d element styles, want the styles left untouched", got)
+			}
+		})
+	}
+}
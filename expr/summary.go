@@ -0,0 +1,97 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type (
+	// modelSummary is the compact representation of a model returned by
+	// Summary.
+	modelSummary struct {
+		Elements      []*elementSummary      `json:"elements"`
+		Relationships []*relationshipSummary `json:"relationships"`
+	}
+
+	// elementSummary is the compact representation of an element.
+	elementSummary struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Type     string `json:"type"`
+		ParentID string `json:"parentId,omitempty"`
+	}
+
+	// relationshipSummary is the compact representation of a relationship.
+	relationshipSummary struct {
+		ID            string `json:"id"`
+		SourceID      string `json:"sourceId"`
+		DestinationID string `json:"destinationId"`
+		Description   string `json:"description,omitempty"`
+	}
+)
+
+// Summary returns a compact JSON representation of the model suitable for
+// quick previews. The summary lists the ID, name, type and parent ID of each
+// element and the ID, endpoints and description of each relationship. Styles
+// and views are omitted. Elements are listed in model order (see Metrics) and
+// relationships in the order of their source elements.
+func (m *Model) Summary() []byte {
+	s := modelSummary{Elements: []*elementSummary{}, Relationships: []*relationshipSummary{}}
+	add := func(e *Element, typ string, parent *Element) {
+		es := &elementSummary{ID: e.ID, Name: e.Name, Type: typ}
+		if parent != nil {
+			es.ParentID = parent.ID
+		}
+		s.Elements = append(s.Elements, es)
+	}
+	for _, p := range m.People {
+		add(p.Element, "Person", nil)
+	}
+	for _, sys := range m.Systems {
+		add(sys.Element, "SoftwareSystem", nil)
+	}
+	for _, sys := range m.Systems {
+		for _, c := range sys.Containers {
+			add(c.Element, "Container", sys.Element)
+		}
+	}
+	for _, sys := range m.Systems {
+		for _, c := range sys.Containers {
+			for _, cmp := range c.Components {
+				add(cmp.Element, "Component", c.Element)
+			}
+		}
+	}
+	var deployment func([]*DeploymentNode)
+	deployment = func(nodes []*DeploymentNode) {
+		for _, n := range nodes {
+			var parent *Element
+			if n.Parent != nil {
+				parent = n.Parent.Element
+			}
+			add(n.Element, "DeploymentNode", parent)
+			for _, i := range n.InfrastructureNodes {
+				add(i.Element, "InfrastructureNode", n.Element)
+			}
+			for _, ci := range n.ContainerInstances {
+				add(ci.Element, "ContainerInstance", n.Element)
+			}
+			deployment(n.Children)
+		}
+	}
+	deployment(m.DeploymentNodes)
+	for _, e := range m.allElements() {
+		for _, r := range m.ElementRelationships(e) {
+			rs := &relationshipSummary{ID: r.ID, SourceID: e.ID, Description: r.Description}
+			if r.Destination != nil {
+				rs.DestinationID = r.Destination.ID
+			}
+			s.Relationships = append(s.Relationships, rs)
+		}
+	}
+	js, err := json.Marshal(&s)
+	if err != nil {
+		panic(fmt.Sprintf("failed to serialize model summary: %s", err)) // bug
+	}
+	return js
+}
@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestModelSummary(t *testing.T) {
+	t.Parallel()
+	var (
+		user = &Person{Element: &Element{ID: "1", Name: "User", Description: "A user"}}
+		sys  = &SoftwareSystem{Element: &Element{ID: "2", Name: "System"}}
+		api  = &Container{Element: &Element{ID: "3", Name: "API", Technology: "Go"}, System: sys}
+		node = &DeploymentNode{Element: &Element{ID: "4", Name: "Node"}, Environment: "Production"}
+		ci   = &ContainerInstance{Element: &Element{ID: "5"}, Container: api, Parent: node}
+	)
+	sys.Containers = Containers{api}
+	node.ContainerInstances = []*ContainerInstance{ci}
+	user.Relationships = []*Relationship{{ID: "6", Source: user.Element, Destination: api.Element, Description: "Uses"}}
+	m := &Model{People: People{user}, Systems: SoftwareSystems{sys}, DeploymentNodes: []*DeploymentNode{node}}
+
+	js := m.Summary()
+
+	var got map[string][]map[string]interface{}
+	if err := json.Unmarshal(js, &got); err != nil {
+		t.Fatalf("invalid JSON %s: %s", js, err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got keys %v, want only elements and relationships", got)
+	}
+	wantElems := []struct{ id, typ, parent string }{
+		{"1", "Person", ""},
+		{"2", "SoftwareSystem", ""},
+		{"3", "Container", "2"},
+		{"4", "DeploymentNode", ""},
+		{"5", "ContainerInstance", "4"},
+	}
+	if len(got["elements"]) != len(wantElems) {
+		t.Fatalf("got %d elements, want %d", len(got["elements"]), len(wantElems))
+	}
+	for i, w := range wantElems {
+		e := got["elements"][i]
+		if e["id"] != w.id || e["type"] != w.typ || (w.parent != "" && e["parentId"] != w.parent) {
+			t.Errorf("got element %v, want id %q type %q parent %q", e, w.id, w.typ, w.parent)
+		}
+		if _, ok := e["description"]; ok {
+			t.Errorf("got description in element %v", e)
+		}
+	}
+	if len(got["relationships"]) != 1 {
+		t.Fatalf("got %d relationships, want 1", len(got["relationships"]))
+	}
+	if r := got["relationships"][0]; r["sourceId"] != "1" || r["destinationId"] != "3" {
+		t.Errorf("got relationship %v, want 1 -> 3", r)
+	}
+}
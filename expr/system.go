@@ -10,6 +10,9 @@ type (
 		*Element
 		Location   LocationKind
 		Containers Containers
+		// InFocus is true if the software system is the focus of the
+		// model, see GenerateFocusedViews.
+		InFocus bool
 	}
 
 	// SoftwareSystems is a slice of software system that can be easily
@@ -17,6 +20,10 @@ type (
 	SoftwareSystems []*SoftwareSystem
 )
 
+// EnterpriseProperty is the name of the property that holds the enterprise a
+// software system belongs to when it differs from the model enterprise.
+const EnterpriseProperty = "Enterprise"
+
 // EvalName returns the generic expression name used in error messages.
 func (s *SoftwareSystem) EvalName() string {
 	if s.Name == "" {
@@ -78,3 +85,9 @@ func (s *SoftwareSystem) AddContainer(c *Container) *Container {
 	}
 	return existing
 }
+
+// EnterpriseName returns the name of the enterprise the software system
+// belongs to as set with the "Enterprise" property, empty if none.
+func (s *SoftwareSystem) EnterpriseName() string {
+	return s.Properties[EnterpriseProperty]
+}
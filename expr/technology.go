@@ -0,0 +1,69 @@
+package expr
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultTechnologyNormalizer is the technology normalizer used when
+// Model.TechnologyNormalizer is nil. It trims and lowercases the technology.
+func DefaultTechnologyNormalizer(tech string) string {
+	return strings.ToLower(strings.TrimSpace(tech))
+}
+
+// NormalizeTechnology returns the normalized form of tech using the model
+// technology normalizer.
+func (m *Model) NormalizeTechnology(tech string) string {
+	if m.TechnologyNormalizer != nil {
+		return m.TechnologyNormalizer(tech)
+	}
+	return DefaultTechnologyNormalizer(tech)
+}
+
+// SameTechnology returns true if a and b are the same technology once
+// normalized.
+func (m *Model) SameTechnology(a, b string) bool {
+	return m.NormalizeTechnology(a) == m.NormalizeTechnology(b)
+}
+
+// Technologies returns the sorted list of distinct normalized technologies
+// used by the elements and relationships of the model. The technology of the
+// elements and relationships themselves is left unchanged.
+func (m *Model) Technologies() []string {
+	seen := make(map[string]struct{})
+	add := func(tech string) {
+		if tech == "" {
+			return
+		}
+		if t := m.NormalizeTechnology(tech); t != "" {
+			seen[t] = struct{}{}
+		}
+	}
+	for _, e := range m.allElements() {
+		add(e.Technology)
+		for _, r := range m.ElementRelationships(e) {
+			add(r.Technology)
+		}
+	}
+	res := make([]string, 0, len(seen))
+	for t := range seen {
+		res = append(res, t)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// RelationshipsByTechnology returns the relationships of the model, implied
+// relationships included, whose technology is the same as tech once normalized.
+// Relationships are returned in model order.
+func (m *Model) RelationshipsByTechnology(tech string) []*Relationship {
+	var res []*Relationship
+	for _, e := range m.allElements() {
+		for _, r := range m.ElementRelationships(e) {
+			if r.Technology != "" && m.SameTechnology(r.Technology, tech) {
+				res = append(res, r)
+			}
+		}
+	}
+	return res
+}
@@ -2,6 +2,8 @@ package expr
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"goa.design/goa/v3/eval"
 )
@@ -15,20 +17,41 @@ type (
 		Title             string
 		AutoLayout        *AutoLayout
 		PaperSize         PaperSizeKind
+		Dimensions        *Dimensions
 		ElementViews      []*ElementView
 		RelationshipViews []*RelationshipView
 		AnimationSteps    []*AnimationStep
+		// Legend controls whether the legend is shown, the renderer
+		// default applies if nil.
+		Legend *bool
+		// HideTitle causes the title of the view not to be rendered.
+		HideTitle bool
+		// ShowLegend causes exporters that do not draw a legend of their
+		// own to render a legend of the tags of the view, see
+		// LegendEntries.
+		ShowLegend bool
+		// HideEmptyGroups causes the groups that contain no element of
+		// the view, directly or in a nested group, to be omitted from
+		// Groups.
+		HideEmptyGroups bool
 
 		// The following fields are used to compute the elements and
 		// relationships that should be added to the view.
 		AddAll              bool
+		AddAllRequiredTags  []string
+		AddAllExcludedTags  []string
 		AddDefault          bool
 		AddNeighbors        []*Element
 		RemoveElements      []*Element
+		RemoveSubtrees      []*Element
 		RemoveTags          []string
 		RemoveRelationships []*Relationship
 		RemoveUnreachable   []*Element
 		RemoveUnrelated     bool
+		// ElementStyles lists the view-scoped element styles defined with
+		// StyleElement indexed by element ID. The styles are copied to the
+		// corresponding element views once the view elements are computed.
+		ElementStyles map[string]*ElementStyle
 	}
 
 	// ElementView describes an instance of a model element (Person,
@@ -38,6 +61,9 @@ type (
 		NoRelationship bool
 		X              *int
 		Y              *int
+		// Style overrides the global styles for this element in this view
+		// only, nil if none.
+		Style *ElementStyle
 	}
 
 	// RelationshipView describes an instance of a model relationship in a
@@ -50,18 +76,43 @@ type (
 		Vertices    []*Vertex
 		Routing     RoutingKind
 		Position    *int
+		// Return is the relationship view created with Return that renders
+		// the response to this relationship in a dynamic view if any.
+		Return *RelationshipView
+		// Response is true if the relationship view renders the response
+		// to the relationship in a dynamic view, the relationship is then
+		// drawn from its destination to its source.
+		Response bool
 
 		// RelationshipID is computed in finalize.
 		RelationshipID string
 	}
 
+	// ViewGroup describes a group of elements in a view.
+	ViewGroup struct {
+		// Path is the path of the group, see Element.Group.
+		Path string
+		// Name is the name of the group, the last segment of Path.
+		Name string
+		// Elements lists the elements of the view that belong directly
+		// to the group in view order.
+		Elements []*Element
+	}
+
 	// AutoLayout describes an automatic layout.
 	AutoLayout struct {
-		RankDirection RankDirectionKind
-		RankSep       *int
-		NodeSep       *int
-		EdgeSep       *int
-		Vertices      *bool
+		Implementation ImplementationKind
+		RankDirection  RankDirectionKind
+		RankSep        *int
+		NodeSep        *int
+		EdgeSep        *int
+		Vertices       *bool
+	}
+
+	// Dimensions describes the default size of a rendered view.
+	Dimensions struct {
+		Width  int
+		Height int
 	}
 
 	// Vertex describes the x and y coordinate of a bend in a line.
@@ -86,6 +137,10 @@ type (
 	// RankDirectionKind is the enum for possible automatic layout rank
 	// directions.
 	RankDirectionKind int
+
+	// ImplementationKind is the enum for possible automatic layout
+	// implementations.
+	ImplementationKind int
 )
 
 const (
@@ -128,6 +183,16 @@ const (
 	RankRightLeft
 )
 
+const (
+	// ImplementationUndefined means no automatic layout implementation is
+	// set.
+	ImplementationUndefined ImplementationKind = iota
+	// ImplementationGraphviz uses Graphviz for automatic layout.
+	ImplementationGraphviz
+	// ImplementationDagre uses Dagre for automatic layout.
+	ImplementationDagre
+)
+
 // ElementView returns the element view for the element with the given ID if
 // any.
 func (v *ViewProps) ElementView(id string) *ElementView {
@@ -139,6 +204,120 @@ func (v *ViewProps) ElementView(id string) *ElementView {
 	return nil
 }
 
+// Groups returns the groups of the elements of the view and of the elements
+// at the same level (the other people and software systems, the other
+// containers of the same software system or the other components of the same
+// container) sorted by path so that nested groups follow their parent. The
+// parents of nested groups are included. The groups that contain no element of
+// the view are omitted if HideEmptyGroups is true.
+func (v *ViewProps) Groups() []*ViewGroup {
+	byPath := make(map[string]*ViewGroup)
+	add := func(path string) {
+		segments := strings.Split(path, GroupSeparator)
+		for i := range segments {
+			p := strings.Join(segments[:i+1], GroupSeparator)
+			if _, ok := byPath[p]; !ok {
+				byPath[p] = &ViewGroup{Path: p, Name: segments[i]}
+			}
+		}
+	}
+	for _, ev := range v.ElementViews {
+		if ev.Element.Group != "" {
+			add(ev.Element.Group)
+		}
+		for _, eh := range siblings(ev.Element) {
+			if g := eh.GetElement().Group; g != "" {
+				add(g)
+			}
+		}
+	}
+	nonEmpty := make(map[string]bool)
+	for _, ev := range v.ElementViews {
+		g, ok := byPath[ev.Element.Group]
+		if !ok {
+			continue
+		}
+		g.Elements = append(g.Elements, ev.Element)
+		segments := strings.Split(g.Path, GroupSeparator)
+		for i := range segments {
+			nonEmpty[strings.Join(segments[:i+1], GroupSeparator)] = true
+		}
+	}
+	groups := make([]*ViewGroup, 0, len(byPath))
+	for p, g := range byPath {
+		if v.HideEmptyGroups && !nonEmpty[p] {
+			continue
+		}
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a := strings.Split(groups[i].Path, GroupSeparator)
+		b := strings.Split(groups[j].Path, GroupSeparator)
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return groups
+}
+
+// siblings returns the elements at the same level as e in the model.
+func siblings(e *Element) []ElementHolder {
+	switch eh := Registry[e.ID].(type) {
+	case *Person, *SoftwareSystem:
+		return append(Root.Model.People.Elements(), Root.Model.Systems.Elements()...)
+	case *Container:
+		return eh.System.Containers.Elements()
+	case *Component:
+		return eh.Container.Components.Elements()
+	}
+	return nil
+}
+
+// clone returns a copy of the view properties with the given key. The element
+// and relationship views are copied, the elements of the element views are
+// copied as well and the transform function, if any, is applied to each copied
+// element view.
+func (v *ViewProps) clone(key string, transform func(*ElementView)) *ViewProps {
+	c := *v
+	c.Key = key
+	c.ElementViews = make([]*ElementView, len(v.ElementViews))
+	for i, ev := range v.ElementViews {
+		cev := *ev
+		elem := *ev.Element
+		cev.Element = &elem
+		if ev.X != nil {
+			x := *ev.X
+			cev.X = &x
+		}
+		if ev.Y != nil {
+			y := *ev.Y
+			cev.Y = &y
+		}
+		if transform != nil {
+			transform(&cev)
+		}
+		c.ElementViews[i] = &cev
+	}
+	c.RelationshipViews = make([]*RelationshipView, len(v.RelationshipViews))
+	copies := make(map[*RelationshipView]*RelationshipView, len(v.RelationshipViews))
+	for i, rv := range v.RelationshipViews {
+		crv := *rv
+		crv.Vertices = append([]*Vertex(nil), rv.Vertices...)
+		c.RelationshipViews[i] = &crv
+		copies[rv] = &crv
+	}
+	for _, crv := range c.RelationshipViews {
+		if crv.Return != nil {
+			crv.Return = copies[crv.Return]
+		}
+	}
+	c.AnimationSteps = append([]*AnimationStep(nil), v.AnimationSteps...)
+	return &c
+}
+
 // Props returns the underlying properties object.
 func (v *ViewProps) Props() *ViewProps { return v }
 
@@ -2,6 +2,8 @@ package expr
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"goa.design/goa/v3/eval"
 )
@@ -16,6 +18,7 @@ type (
 		DynamicViews    []*DynamicView
 		DeploymentViews []*DeploymentView
 		FilteredViews   []*FilteredView
+		ImageViews      []*ImageView
 		Styles          *Styles
 		DSLFunc         func()
 	}
@@ -24,6 +27,23 @@ type (
 	LandscapeView struct {
 		*ViewProps
 		EnterpriseBoundaryVisible *bool
+		// GroupByEnterprise causes renderers to draw a boundary around
+		// the software systems of each enterprise.
+		GroupByEnterprise bool
+		// AggregateRelationships causes multiple relationships between
+		// the same source and destination to be rendered as a single
+		// relationship.
+		AggregateRelationships bool
+	}
+
+	// EnterpriseGroup lists the elements of a view that belong to the same
+	// enterprise.
+	EnterpriseGroup struct {
+		// Name of enterprise.
+		Name string
+		// Elements lists the software systems of the view that belong to
+		// the enterprise in view order.
+		Elements []*Element
 	}
 
 	// ContextView describes a system context view.
@@ -60,6 +80,10 @@ type (
 		*ViewProps
 		SoftwareSystemID string
 		Environment      string
+		// RemoveNodeTags lists the tags of the deployment nodes and
+		// infrastructure nodes removed from the view together with
+		// their children.
+		RemoveNodeTags []string
 	}
 
 	// Styles describes the styles for a view.
@@ -68,20 +92,32 @@ type (
 		Relationships            []*RelationshipStyle
 		StructurizrElements      []*StructurizrElementStyle
 		StructurizrRelationships []*StructurizrRelationshipStyle
+		EndpointTags             []*EndpointTags
+	}
+
+	// EndpointTags describes tags copied from relationship endpoints onto
+	// the relationships.
+	EndpointTags struct {
+		// Prefix is prepended to the copied tags.
+		Prefix string
+		// Tags lists the endpoint tags to copy.
+		Tags []string
 	}
 
 	// ElementStyle defines an element style.
 	ElementStyle struct {
-		Tag         string
-		Shape       ShapeKind
-		Icon        string
-		Background  string
-		Color       string
-		Stroke      string
-		Metadata    *bool
-		Description *bool
-		Opacity     *int
-		Border      BorderKind
+		Tag          string
+		RequiredTags []string
+		ExcludedTags []string
+		Shape        ShapeKind
+		Icon         string
+		Background   string
+		Color        string
+		Stroke       string
+		Metadata     *bool
+		Description  *bool
+		Opacity      *int
+		Border       BorderKind
 	}
 
 	// StructurizrElementStyle defines additional element styles that only
@@ -97,13 +133,15 @@ type (
 
 	// RelationshipStyle defines a relationship style.
 	RelationshipStyle struct {
-		Tag     string
-		Thick   *bool
-		Color   string
-		Stroke  string
-		Dashed  *bool
-		Routing RoutingKind
-		Opacity *int
+		Tag          string
+		RequiredTags []string
+		ExcludedTags []string
+		Thick        *bool
+		Color        string
+		Stroke       string
+		Dashed       *bool
+		Routing      RoutingKind
+		Opacity      *int
 	}
 
 	// StructurizrRelationshipStyle defines additional relationship styles that
@@ -119,6 +157,7 @@ type (
 	// View is the common interface for all views.
 	View interface {
 		Props() *ViewProps
+		AutoLayout() *AutoLayout
 	}
 
 	// ViewAdder is the interface implemented by views that allow adding
@@ -196,8 +235,9 @@ func (vs *Views) EvalName() string {
 	return "views"
 }
 
-// Validate makes sure the right element are in the right views, it also makes
-// sure all animation steps have elements.
+// Validate makes sure the right element are in the right views and that the
+// elements and relationships referenced by the views exist, it also makes
+// sure all animation steps have elements and that image views have content.
 func (vs *Views) Validate() error {
 	verr := new(eval.ValidationErrors)
 
@@ -206,6 +246,8 @@ func (vs *Views) Validate() error {
 	checkElements := func(title string, evs []*ElementView, allowContainers bool) {
 		for _, ev := range evs {
 			switch Registry[ev.Element.ID].(type) {
+			case nil:
+				// reported below
 			case *SoftwareSystem, *Person:
 				// all good
 			case *Container:
@@ -231,11 +273,45 @@ func (vs *Views) Validate() error {
 		checkElements("container views", cv.ElementViews, true)
 	}
 
+	// Relationships with no destination were already reported by
+	// model.Validate, the elements of the views cannot be computed.
+	var unresolved bool
+	IterateRelationships(func(r *Relationship) {
+		if r.Destination == nil {
+			unresolved = true
+		}
+	})
+
 	for _, view := range vs.All() {
 		v := view.Props()
 
+		// Make sure the elements and relationships referenced by the view
+		// still exist, views built programmatically may refer to elements
+		// that were since removed from the model.
+		if id := viewScopeID(view); id != "" {
+			if _, ok := Registry[id]; !ok {
+				verr.Add(v, "view %q is scoped to element with ID %q which does not exist in the model", v.Key, id)
+			}
+		}
+		for _, ev := range v.ElementViews {
+			if _, ok := Registry[ev.Element.ID]; !ok {
+				verr.Add(v, "view %q references element %q with ID %q which does not exist in the model", v.Key, ev.Element.Name, ev.Element.ID)
+			}
+		}
+		for _, rv := range v.RelationshipViews {
+			if rv.RelationshipID == "" {
+				continue
+			}
+			if _, ok := Registry[rv.RelationshipID]; !ok {
+				verr.Add(v, "view %q references relationship %q [%s -> %s] with ID %q which does not exist in the model", v.Key, rv.Description, rv.Source.Name, rv.Destination.Name, rv.RelationshipID)
+			}
+		}
+
 		// Map relationship views created explicitly to model relationships.
 		for _, rv := range v.RelationshipViews {
+			if rv.Response {
+				continue // mapped with the relationship view it responds to
+			}
 			srcID := rv.Source.ID
 			destID := rv.Destination.ID
 			desc := rv.Description
@@ -250,6 +326,23 @@ func (vs *Views) Validate() error {
 			if rv.RelationshipID == "" {
 				verr.Add(rv, "could not find relationship %q [%s -> %s] to add to view %q", rv.Description, rv.Source.Name, rv.Destination.Name, v.Key)
 			}
+			if rv.Return != nil {
+				rv.Return.RelationshipID = rv.RelationshipID
+			}
+		}
+
+		// Make sure all dynamic view steps are labeled, the description
+		// of the relationship is used if the step does not define one.
+		if _, ok := view.(*DynamicView); ok {
+			for i, rv := range v.RelationshipViews {
+				if strings.TrimSpace(rv.Description) != "" {
+					continue
+				}
+				if r, ok := Registry[rv.RelationshipID].(*Relationship); ok && strings.TrimSpace(r.Description) != "" {
+					continue
+				}
+				verr.Add(rv, "step %d [%s -> %s] of dynamic view %q must have a description", i+1, rv.Source.Name, rv.Destination.Name, v.Key)
+			}
 		}
 
 		// Make sure all elements used to remove unreachable are in scope.
@@ -257,6 +350,22 @@ func (vs *Views) Validate() error {
 			validateElementInView(v, e, "RemoveUnreachable", verr)
 		}
 
+		// Make sure the subtrees removed with ExcludeSubtree are in the
+		// view once the elements added by the view DSL are computed.
+		scope := viewScopeID(view)
+		if len(v.RemoveSubtrees) > 0 && !unresolved && (scope == "" || Registry[scope] != nil) {
+			elems := computedElements(view)
+		subtrees:
+			for _, e := range v.RemoveSubtrees {
+				for _, d := range subtree(e) {
+					if elems[d.ID] {
+						continue subtrees
+					}
+				}
+				verr.Add(v, "element %q used in ExcludeSubtree is not in the view %q", e.Name, v.Key)
+			}
+		}
+
 		for i, s := range v.AnimationSteps {
 			// Make sure all animation steps define at least one element.
 			if len(s.Elements) == 0 {
@@ -269,30 +378,63 @@ func (vs *Views) Validate() error {
 		}
 	}
 
+	// Make sure image views define exactly one content source and refer to
+	// existing elements.
+	for _, iv := range vs.ImageViews {
+		if _, ok := Registry[iv.ElementID]; !ok {
+			verr.Add(iv, "associated element with ID %q does not exist in the model", iv.ElementID)
+		}
+		switch {
+		case iv.Content == "" && iv.ContentFile == "":
+			verr.Add(iv, "content must be defined with Content or ContentFile")
+		case iv.Content != "" && iv.ContentFile != "":
+			verr.Add(iv, "cannot use both Content and ContentFile")
+		default:
+			if _, _, err := iv.ContentData(); err != nil {
+				verr.AddError(iv, err)
+			}
+		}
+	}
+
 	return verr
 }
 
+// viewScopeID returns the ID of the element the view is scoped to if any.
+func viewScopeID(view View) string {
+	switch v := view.(type) {
+	case *ContextView:
+		return v.SoftwareSystemID
+	case *ContainerView:
+		return v.SoftwareSystemID
+	case *ComponentView:
+		return v.ContainerID
+	case *DynamicView:
+		return v.ElementID
+	case *DeploymentView:
+		return v.SoftwareSystemID
+	}
+	return ""
+}
+
 // Finalize relationships.
 func (vs *Views) Finalize() {
-	// Add influencers to container views.
-	for _, view := range vs.ContainerViews {
-		if view.AddInfluencers {
-			addInfluencers(view)
-		}
-	}
+	// Style deprecated elements, copy endpoint tags onto relationships, tag
+	// elements and relationships matched by compound styles and merge
+	// element styles defined multiple times for the same tag.
+	vs.addDeprecatedStyle()
+	propagateEndpointTags(vs.Styles)
+	finalizeCompoundStyles(vs.Styles)
+	dedupElementStyles(vs.Styles)
 
 	for _, view := range vs.All() {
 		vp := view.Props()
 
-		if vp.AddAll {
-			addAllElements(view)
-		} else if vp.AddDefault {
-			addDefaultElements(view)
-		}
-		for _, e := range vp.AddNeighbors {
-			addNeighbors(e, vp)
+		addViewElements(view)
+		_, dynamic := view.(*DynamicView)
+		dedupViewElements(vp, !dynamic)
+		if dv, ok := view.(*DeploymentView); ok {
+			removeOtherEnvironmentRelationships(dv)
 		}
-		addMissingElementsAndRelationships(vp)
 		addAnimationStepRelationships(vp)
 
 		// Then remove elements and relationships that need to be removed
@@ -300,18 +442,36 @@ func (vs *Views) Finalize() {
 		for _, e := range vp.RemoveElements {
 			removeElements(vp, e)
 		}
+		for _, e := range vp.RemoveSubtrees {
+			removeElements(vp, subtree(e)...)
+		}
 		for _, r := range vp.RemoveRelationships {
 			removeRelationship(vp, r)
 		}
 		for _, tag := range vp.RemoveTags {
 			removeElements(vp, tagged(vp, tag)...)
 		}
+		if dv, ok := view.(*DeploymentView); ok {
+			for _, tag := range dv.RemoveNodeTags {
+				for _, e := range tagged(vp, tag) {
+					switch Registry[e.ID].(type) {
+					case *DeploymentNode, *InfrastructureNode:
+						removeElements(vp, subtree(e)...)
+					}
+				}
+			}
+		}
 		for _, e := range vp.RemoveUnreachable {
 			removeElements(vp, unreachable(vp, e)...)
 		}
 		if vp.RemoveUnrelated {
 			removeElements(vp, unrelated(vp)...)
 		}
+		for id, style := range vp.ElementStyles {
+			if ev := vp.ElementView(id); ev != nil {
+				ev.Style = style
+			}
+		}
 		for _, ev := range vp.ElementViews {
 			if ev.NoRelationship {
 				i := 0
@@ -324,7 +484,126 @@ func (vs *Views) Finalize() {
 				vp.RelationshipViews = vp.RelationshipViews[:i]
 			}
 		}
+
+		// Order relationships between the same elements deterministically,
+		// the order of relationships in dynamic views is explicit.
+		if _, ok := view.(*DynamicView); !ok {
+			sortRelationshipViews(vp)
+		}
+		if lv, ok := view.(*LandscapeView); ok && lv.AggregateRelationships {
+			aggregateRelationships(vp)
+		}
+	}
+}
+
+// dedupViewElements removes the element views that render an element already
+// rendered by a previous element view so that adding the same element
+// multiple times (e.g. with AddAll and Add) produces a single element view.
+// The coordinates of a removed element view are kept if the first element view
+// has none. If dedupRelationships is true the relationship views that render
+// the same relationship are deduplicated the same way, dynamic views may
+// render the same relationship multiple times.
+func dedupViewElements(vp *ViewProps, dedupRelationships bool) {
+	evs := make(map[string]*ElementView, len(vp.ElementViews))
+	i := 0
+	for _, ev := range vp.ElementViews {
+		first, ok := evs[ev.Element.ID]
+		if !ok {
+			evs[ev.Element.ID] = ev
+			vp.ElementViews[i] = ev
+			i++
+			continue
+		}
+		if first.X == nil && first.Y == nil {
+			first.X, first.Y = ev.X, ev.Y
+		}
+		first.NoRelationship = first.NoRelationship || ev.NoRelationship
 	}
+	vp.ElementViews = vp.ElementViews[:i]
+	if !dedupRelationships {
+		return
+	}
+	rvs := make(map[string]*RelationshipView, len(vp.RelationshipViews))
+	i = 0
+	for _, rv := range vp.RelationshipViews {
+		if rv.RelationshipID == "" {
+			vp.RelationshipViews[i] = rv
+			i++
+			continue
+		}
+		first, ok := rvs[rv.RelationshipID]
+		if !ok {
+			rvs[rv.RelationshipID] = rv
+			vp.RelationshipViews[i] = rv
+			i++
+			continue
+		}
+		if len(first.Vertices) == 0 {
+			first.Vertices = rv.Vertices
+		}
+		if first.Routing == RoutingUndefined {
+			first.Routing = rv.Routing
+		}
+		if first.Position == nil {
+			first.Position = rv.Position
+		}
+	}
+	vp.RelationshipViews = vp.RelationshipViews[:i]
+}
+
+// aggregateRelationships collapses the relationship views that share the same
+// source and destination into the first one. The description of the resulting
+// relationship view combines the distinct descriptions of the collapsed
+// relationship views. The model relationships are left untouched.
+func aggregateRelationships(vp *ViewProps) {
+	byEnds := make(map[string]*RelationshipView)
+	i := 0
+	for _, rv := range vp.RelationshipViews {
+		key := rv.Source.ID + ":" + rv.Destination.ID
+		first, ok := byEnds[key]
+		if !ok {
+			byEnds[key] = rv
+			vp.RelationshipViews[i] = rv
+			i++
+			continue
+		}
+		if rv.Description == "" || strings.Contains(", "+first.Description+", ", ", "+rv.Description+", ") {
+			continue
+		}
+		if first.Description == "" {
+			first.Description = rv.Description
+			continue
+		}
+		first.Description += ", " + rv.Description
+	}
+	vp.RelationshipViews = vp.RelationshipViews[:i]
+}
+
+// EnterpriseGroups returns the software systems of the view grouped by the
+// enterprise they belong to, sorted by enterprise name. Software systems that
+// do not define an enterprise are not included.
+func (lv *LandscapeView) EnterpriseGroups() []*EnterpriseGroup {
+	byName := make(map[string]*EnterpriseGroup)
+	var groups []*EnterpriseGroup
+	for _, ev := range lv.ElementViews {
+		s, ok := Registry[ev.Element.ID].(*SoftwareSystem)
+		if !ok {
+			continue
+		}
+		name := s.EnterpriseName()
+		if name == "" {
+			continue
+		}
+		g, ok := byName[name]
+		if !ok {
+			g = &EnterpriseGroup{Name: name}
+			byName[name] = g
+			groups = append(groups, g)
+		}
+		g.Elements = append(g.Elements, ev.Element)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
 }
 
 // All returns all the views in a single slice.
@@ -350,6 +629,159 @@ func (vs Views) All() (vps []View) {
 	return
 }
 
+// CloneView adds a copy of the view with key baseKey to the design views
+// using newKey as key and returns it. The transform function, if not nil, is
+// called with each element view of the copy. The element of each element view
+// is a copy of the model element so that the transform may override fields
+// such as the description or the position without affecting the model or the
+// base view. CloneView should be called once the design has been evaluated so
+// that the elements of the base view are computed. CloneView returns an error
+// if there is no view with key baseKey or if a view with key newKey already
+// exists.
+func (m *Model) CloneView(baseKey, newKey string, transform func(*ElementView)) (View, error) {
+	if Root.Views == nil {
+		return nil, fmt.Errorf("view %q not found", baseKey)
+	}
+	vs := Root.Views
+	if newKey == "" {
+		return nil, fmt.Errorf("cannot clone view %q: new key cannot be empty", baseKey)
+	}
+	if newKey == baseKey {
+		return nil, fmt.Errorf("cannot clone view %q: key %q is already in use", baseKey, newKey)
+	}
+	var base View
+	for _, v := range vs.All() {
+		switch v.Props().Key {
+		case baseKey:
+			base = v
+		case newKey:
+			return nil, fmt.Errorf("cannot clone view %q: key %q is already in use", baseKey, newKey)
+		}
+	}
+	for _, fv := range vs.FilteredViews {
+		if fv.Key == newKey {
+			return nil, fmt.Errorf("cannot clone view %q: key %q is already in use", baseKey, newKey)
+		}
+	}
+	for _, iv := range vs.ImageViews {
+		if iv.Key == newKey {
+			return nil, fmt.Errorf("cannot clone view %q: key %q is already in use", baseKey, newKey)
+		}
+	}
+	if base == nil {
+		return nil, fmt.Errorf("view %q not found", baseKey)
+	}
+	props := base.Props().clone(newKey, transform)
+	var res View
+	switch v := base.(type) {
+	case *LandscapeView:
+		c := *v
+		c.ViewProps = props
+		vs.LandscapeViews = append(vs.LandscapeViews, &c)
+		res = &c
+	case *ContextView:
+		c := *v
+		c.ViewProps = props
+		vs.ContextViews = append(vs.ContextViews, &c)
+		res = &c
+	case *ContainerView:
+		c := *v
+		c.ViewProps = props
+		vs.ContainerViews = append(vs.ContainerViews, &c)
+		res = &c
+	case *ComponentView:
+		c := *v
+		c.ViewProps = props
+		vs.ComponentViews = append(vs.ComponentViews, &c)
+		res = &c
+	case *DynamicView:
+		c := *v
+		c.ViewProps = props
+		vs.DynamicViews = append(vs.DynamicViews, &c)
+		res = &c
+	case *DeploymentView:
+		c := *v
+		c.ViewProps = props
+		vs.DeploymentViews = append(vs.DeploymentViews, &c)
+		res = &c
+	}
+	return res, nil
+}
+
+// GenerateComponentViews adds a component view to the design views for each
+// container of the model for which match returns true and returns the new
+// views. The views include all the components of the container and their
+// neighbors (see AddAll) and use a top to bottom automatic layout. The views
+// are keyed "<System><Container>Components" where spaces are removed from the
+// names, containers that already have a view with that key are skipped.
+// GenerateComponentViews must be called before the views are finalized, for
+// example from the Views DSL.
+func (m *Model) GenerateComponentViews(match func(*Container) bool) []*ComponentView {
+	if Root.Views == nil {
+		Root.Views = &Views{}
+	}
+	vs := Root.Views
+	keys := make(map[string]bool)
+	for _, v := range vs.All() {
+		keys[v.Props().Key] = true
+	}
+	var res []*ComponentView
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			if !match(c) {
+				continue
+			}
+			key := strings.ReplaceAll(s.Name, " ", "") + strings.ReplaceAll(c.Name, " ", "") + "Components"
+			if keys[key] {
+				continue
+			}
+			keys[key] = true
+			r, n, e := 300, 600, 200
+			v := &ComponentView{
+				ViewProps: &ViewProps{
+					Key:         key,
+					Description: fmt.Sprintf("Components of %s.", c.Name),
+					AddAll:      true,
+					AutoLayout: &AutoLayout{
+						RankDirection: RankTopBottom,
+						RankSep:       &r,
+						NodeSep:       &n,
+						EdgeSep:       &e,
+					},
+				},
+				ContainerID: c.ID,
+			}
+			vs.ComponentViews = append(vs.ComponentViews, v)
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// AutoLayout returns the automatic layout configuration of the view, nil if
+// the view uses manual positions.
+func (lv *LandscapeView) AutoLayout() *AutoLayout { return lv.ViewProps.AutoLayout }
+
+// AutoLayout returns the automatic layout configuration of the view, nil if
+// the view uses manual positions.
+func (cv *ContextView) AutoLayout() *AutoLayout { return cv.ViewProps.AutoLayout }
+
+// AutoLayout returns the automatic layout configuration of the view, nil if
+// the view uses manual positions.
+func (cv *ContainerView) AutoLayout() *AutoLayout { return cv.ViewProps.AutoLayout }
+
+// AutoLayout returns the automatic layout configuration of the view, nil if
+// the view uses manual positions.
+func (cv *ComponentView) AutoLayout() *AutoLayout { return cv.ViewProps.AutoLayout }
+
+// AutoLayout returns the automatic layout configuration of the view, nil if
+// the view uses manual positions.
+func (dv *DynamicView) AutoLayout() *AutoLayout { return dv.ViewProps.AutoLayout }
+
+// AutoLayout returns the automatic layout configuration of the view, nil if
+// the view uses manual positions.
+func (dv *DeploymentView) AutoLayout() *AutoLayout { return dv.ViewProps.AutoLayout }
+
 // AddElements adds the given elements to the view if not already present.
 func (cv *LandscapeView) AddElements(ehs ...ElementHolder) error {
 	for _, eh := range ehs {
@@ -628,6 +1060,45 @@ loop:
 	return nil
 }
 
+// addViewElements adds the elements implied by AddInfluencers, AddAll,
+// AddDefault and AddNeighbors to the view as well as the elements and
+// relationships missing from the relationship views.
+func addViewElements(view View) {
+	vp := view.Props()
+	if cv, ok := view.(*ContainerView); ok && cv.AddInfluencers {
+		addInfluencers(cv)
+	}
+	if vp.AddAll {
+		if len(vp.AddAllRequiredTags) > 0 || len(vp.AddAllExcludedTags) > 0 {
+			addAllTaggedElements(view, vp.AddAllRequiredTags, vp.AddAllExcludedTags)
+		} else {
+			addAllElements(view)
+		}
+	} else if vp.AddDefault {
+		addDefaultElements(view)
+	}
+	for _, e := range vp.AddNeighbors {
+		addNeighbors(e, view)
+	}
+	addMissingElementsAndRelationships(vp)
+}
+
+// computedElements returns the IDs of the elements of the view once the
+// elements added by addViewElements are computed. The view is left unchanged.
+func computedElements(view View) map[string]bool {
+	vp := view.Props()
+	evs, rvs := vp.ElementViews, vp.RelationshipViews
+	defer func() { vp.ElementViews, vp.RelationshipViews = evs, rvs }()
+	vp.ElementViews = append([]*ElementView(nil), evs...)
+	vp.RelationshipViews = append([]*RelationshipView(nil), rvs...)
+	addViewElements(view)
+	ids := make(map[string]bool, len(vp.ElementViews))
+	for _, ev := range vp.ElementViews {
+		ids[ev.Element.ID] = true
+	}
+	return ids
+}
+
 // validateElementInView makes sure there is an ElementView corresponding to e
 // in v. It adds an error to verr using title if that's not the case.
 func validateElementInView(v *ViewProps, e *Element, title string, verr *eval.ValidationErrors) {
@@ -0,0 +1,602 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+)
+
+func TestViewAutoLayout(t *testing.T) {
+	t.Parallel()
+	sep := 100
+	tests := []struct {
+		name string
+		view View
+		want *AutoLayout
+	}{
+		{"manual", &ContainerView{ViewProps: &ViewProps{}}, nil},
+		{"left-right", &ContainerView{ViewProps: &ViewProps{AutoLayout: &AutoLayout{RankDirection: RankLeftRight, RankSep: &sep}}}, &AutoLayout{RankDirection: RankLeftRight, RankSep: &sep}},
+		{"dagre", &DeploymentView{ViewProps: &ViewProps{AutoLayout: &AutoLayout{Implementation: ImplementationDagre}}}, &AutoLayout{Implementation: ImplementationDagre}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.view.AutoLayout()
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("got %v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("got nil layout")
+			}
+			if got.RankDirection != tt.want.RankDirection {
+				t.Errorf("got rank direction %d, want %d", got.RankDirection, tt.want.RankDirection)
+			}
+			if got.Implementation != tt.want.Implementation {
+				t.Errorf("got implementation %d, want %d", got.Implementation, tt.want.Implementation)
+			}
+			if got.RankSep != tt.want.RankSep {
+				t.Errorf("got rank separation %v, want %v", got.RankSep, tt.want.RankSep)
+			}
+		})
+	}
+}
+
+func TestDeploymentViewEnvironmentRelationships(t *testing.T) {
+	var (
+		sys  = &SoftwareSystem{Element: &Element{Name: "Environments System"}}
+		api  = &Container{Element: &Element{Name: "API"}, System: sys}
+		db   = &Container{Element: &Element{Name: "DB"}, System: sys}
+		rel  = &Relationship{Source: api.Element, Destination: db.Element, Description: "Reads"}
+		ids  []string
+		envs = []string{"Production", "Staging"}
+	)
+	sys.Containers = Containers{api, db}
+	api.Relationships = []*Relationship{rel}
+	for _, e := range []interface{}{sys, api, db, rel} {
+		Identify(e)
+	}
+	nodes := make(map[string]*DeploymentNode)
+	for _, env := range envs {
+		n := &DeploymentNode{Element: &Element{Name: "Environments " + env}, Environment: env}
+		Identify(n)
+		for _, c := range []*Container{api, db} {
+			ci := &ContainerInstance{Element: &Element{}, Parent: n, Container: c, ContainerID: c.ID, InstanceID: 1, Environment: env}
+			Identify(ci)
+			n.ContainerInstances = append(n.ContainerInstances, ci)
+		}
+		nodes[env] = n
+	}
+	defer func() {
+		for _, id := range ids {
+			delete(Registry, id)
+		}
+	}()
+	m := &Model{Systems: SoftwareSystems{sys}, DeploymentNodes: []*DeploymentNode{nodes["Production"], nodes["Staging"]}}
+
+	m.Finalize()
+
+	Iterate(func(e interface{}) {
+		switch x := e.(type) {
+		case ElementHolder:
+			ids = append(ids, x.GetElement().ID)
+		case *Relationship:
+			ids = append(ids, x.ID)
+		}
+	})
+	for _, env := range envs {
+		ci := nodes[env].ContainerInstances[0]
+		if len(ci.Relationships) != 1 {
+			t.Fatalf("%s: got %d instance relationships, want 1", env, len(ci.Relationships))
+		}
+		r := ci.Relationships[0]
+		if dest := Registry[r.Destination.ID].(*ContainerInstance); dest.Environment != env || r.Environment != env {
+			t.Errorf("%s: got relationship to %s instance with environment %q", env, dest.Environment, r.Environment)
+		}
+	}
+
+	dv := &DeploymentView{ViewProps: &ViewProps{}, Environment: "Staging"}
+	if err := dv.AddElements(nodes["Staging"]); err != nil {
+		t.Fatal(err)
+	}
+	addMissingElementsAndRelationships(dv.ViewProps)
+	removeOtherEnvironmentRelationships(dv)
+
+	if len(dv.RelationshipViews) != 1 {
+		t.Fatalf("got %d relationship views, want 1", len(dv.RelationshipViews))
+	}
+	for _, rv := range dv.RelationshipViews {
+		for _, e := range []*Element{rv.Source, rv.Destination} {
+			if ci := Registry[e.ID].(*ContainerInstance); ci.Environment != "Staging" {
+				t.Errorf("got relationship view to %s instance", ci.Environment)
+			}
+		}
+	}
+}
+
+func TestDeploymentViewNodeRelationships(t *testing.T) {
+	var (
+		regionA = &DeploymentNode{Element: &Element{Name: "Nodes Region A"}, Environment: "Production"}
+		regionB = &DeploymentNode{Element: &Element{Name: "Nodes Region B"}, Environment: "Production"}
+		dr      = &DeploymentNode{Element: &Element{Name: "Nodes DR Region"}, Environment: "DR"}
+		lbA     = &InfrastructureNode{Element: &Element{Name: "LB"}, Parent: regionA, Environment: "Production"}
+		lbB     = &InfrastructureNode{Element: &Element{Name: "LB"}, Parent: regionB, Environment: "Production"}
+		peers   = &Relationship{Source: regionA.Element, Destination: regionB.Element, Description: "Peers with"}
+		repl    = &Relationship{Source: regionB.Element, Destination: dr.Element, Description: "Replicates to", CrossEnvironment: true}
+	)
+	regionA.InfrastructureNodes = []*InfrastructureNode{lbA}
+	regionB.InfrastructureNodes = []*InfrastructureNode{lbB}
+	regionA.Relationships = []*Relationship{peers}
+	regionB.Relationships = []*Relationship{repl}
+	for _, e := range []interface{}{regionA, regionB, dr, lbA, lbB, peers, repl} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{regionA.ID, regionB.ID, dr.ID, lbA.ID, lbB.ID, peers.ID, repl.ID} {
+			delete(Registry, id)
+		}
+	}()
+	m := &Model{DeploymentNodes: []*DeploymentNode{regionA, regionB, dr}}
+
+	if err := m.Validate(); len(err.(*eval.ValidationErrors).Errors) != 0 {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+	m.Finalize()
+
+	if peers.Environment != "Production" {
+		t.Errorf("got environment %q, want %q", peers.Environment, "Production")
+	}
+	if repl.Environment != "" {
+		t.Errorf("got environment %q for cross environment relationship, want none", repl.Environment)
+	}
+	dv := &DeploymentView{ViewProps: &ViewProps{}, Environment: "Production"}
+	if err := dv.AddElements(regionA, regionB); err != nil {
+		t.Fatal(err)
+	}
+	addMissingElementsAndRelationships(dv.ViewProps)
+	removeOtherEnvironmentRelationships(dv)
+
+	if len(dv.RelationshipViews) != 1 {
+		t.Fatalf("got %d relationship views, want 1", len(dv.RelationshipViews))
+	}
+	if rv := dv.RelationshipViews[0]; rv.RelationshipID != peers.ID {
+		t.Errorf("got relationship view %q -> %q, want %q -> %q", rv.Source.Name, rv.Destination.Name, regionA.Name, regionB.Name)
+	}
+
+	repl.CrossEnvironment = false
+	if err := m.Validate(); len(err.(*eval.ValidationErrors).Errors) != 1 {
+		t.Errorf("got %v, want an error for the relationship across environments", err)
+	}
+}
+
+func TestLandscapeViewEnterpriseGroups(t *testing.T) {
+	var (
+		acme    = &SoftwareSystem{Element: &Element{Name: "Acme System", Properties: map[string]string{EnterpriseProperty: "Acme"}}}
+		partner = &SoftwareSystem{Element: &Element{Name: "Partner System", Properties: map[string]string{EnterpriseProperty: "Partner"}}}
+		other   = &SoftwareSystem{Element: &Element{Name: "Other System"}}
+	)
+	for _, s := range []*SoftwareSystem{acme, partner, other} {
+		Identify(s)
+	}
+	defer func() {
+		for _, s := range []*SoftwareSystem{acme, partner, other} {
+			delete(Registry, s.ID)
+		}
+	}()
+	lv := &LandscapeView{ViewProps: &ViewProps{}, GroupByEnterprise: true}
+	if err := lv.AddElements(partner, other, acme); err != nil {
+		t.Fatal(err)
+	}
+
+	groups := lv.EnterpriseGroups()
+
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	for i, want := range []*SoftwareSystem{acme, partner} {
+		g := groups[i]
+		if g.Name != want.EnterpriseName() {
+			t.Errorf("group %d: got name %q, want %q", i, g.Name, want.EnterpriseName())
+		}
+		if len(g.Elements) != 1 || g.Elements[0] != want.Element {
+			t.Errorf("group %d: got %d elements, want only %q", i, len(g.Elements), want.Name)
+		}
+	}
+}
+
+func TestLandscapeViewAggregateRelationships(t *testing.T) {
+	var (
+		api   = &SoftwareSystem{Element: &Element{Name: "API"}}
+		store = &SoftwareSystem{Element: &Element{Name: "Store"}}
+		reads = &Relationship{Source: api.Element, Destination: store.Element, Description: "Reads from"}
+		write = &Relationship{Source: api.Element, Destination: store.Element, Description: "Writes to"}
+	)
+	api.Relationships = []*Relationship{reads, write}
+	for _, e := range []interface{}{api, store, reads, write} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{api.ID, store.ID, reads.ID, write.ID} {
+			delete(Registry, id)
+		}
+	}()
+	lv := &LandscapeView{ViewProps: &ViewProps{}, AggregateRelationships: true}
+	if err := lv.AddElements(api, store); err != nil {
+		t.Fatal(err)
+	}
+	addMissingElementsAndRelationships(lv.ViewProps)
+	sortRelationshipViews(lv.ViewProps)
+
+	aggregateRelationships(lv.ViewProps)
+
+	if len(lv.RelationshipViews) != 1 {
+		t.Fatalf("got %d relationship views, want 1", len(lv.RelationshipViews))
+	}
+	if got, want := lv.RelationshipViews[0].Description, "Reads from, Writes to"; got != want {
+		t.Errorf("got description %q, want %q", got, want)
+	}
+	if len(api.Relationships) != 2 || reads.Description != "Reads from" || write.Description != "Writes to" {
+		t.Errorf("model relationships were modified")
+	}
+}
+
+func TestModelCloneView(t *testing.T) {
+	var (
+		api = &SoftwareSystem{Element: &Element{Name: "Clone API", Description: "Serves requests"}}
+		db  = &SoftwareSystem{Element: &Element{Name: "Clone DB", Description: "Stores data"}}
+		x   = 10
+	)
+	for _, s := range []*SoftwareSystem{api, db} {
+		Identify(s)
+	}
+	views := Root.Views
+	defer func() {
+		Root.Views = views
+		for _, s := range []*SoftwareSystem{api, db} {
+			delete(Registry, s.ID)
+		}
+	}()
+	base := &LandscapeView{ViewProps: &ViewProps{Key: "base", ElementViews: []*ElementView{
+		{Element: api.Element, X: &x},
+		{Element: db.Element},
+	}}}
+	Root.Views = &Views{LandscapeViews: []*LandscapeView{base}}
+
+	v, err := Root.Model.CloneView("base", "clone", func(ev *ElementView) {
+		ev.Element.Description = "Cloned: " + ev.Element.Description
+		if ev.X != nil {
+			*ev.X += 5
+		}
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	clone, ok := v.(*LandscapeView)
+	if !ok {
+		t.Fatalf("got view of type %T, want *LandscapeView", v)
+	}
+	if clone.Key != "clone" {
+		t.Errorf("got key %q, want %q", clone.Key, "clone")
+	}
+	if len(Root.Views.LandscapeViews) != 2 || Root.Views.LandscapeViews[1] != clone {
+		t.Errorf("clone not added to the design views")
+	}
+	for i, want := range []string{"Cloned: Serves requests", "Cloned: Stores data"} {
+		if got := clone.ElementViews[i].Element.Description; got != want {
+			t.Errorf("element %d: got description %q, want %q", i, got, want)
+		}
+		if clone.ElementViews[i].Element.ID != base.ElementViews[i].Element.ID {
+			t.Errorf("element %d: got ID %q, want %q", i, clone.ElementViews[i].Element.ID, base.ElementViews[i].Element.ID)
+		}
+	}
+	if *clone.ElementViews[0].X != 15 {
+		t.Errorf("got X %d, want 15", *clone.ElementViews[0].X)
+	}
+	if api.Description != "Serves requests" || db.Description != "Stores data" {
+		t.Errorf("transform modified the model elements")
+	}
+	if x != 10 {
+		t.Errorf("transform modified the base view position")
+	}
+
+	if _, err := Root.Model.CloneView("base", "clone", nil); err == nil {
+		t.Error("expected error when cloning to an existing key")
+	}
+	if _, err := Root.Model.CloneView("unknown", "other", nil); err == nil {
+		t.Error("expected error when cloning an unknown view")
+	}
+}
+
+func TestModelGenerateComponentViews(t *testing.T) {
+	var (
+		sys    = &SoftwareSystem{Element: &Element{Name: "Generated System"}}
+		api    = &Container{Element: &Element{Name: "API", Tags: "Container,Service"}, System: sys}
+		worker = &Container{Element: &Element{Name: "Worker", Tags: "Container,Service"}, System: sys}
+		db     = &Container{Element: &Element{Name: "DB", Tags: "Container,Database"}, System: sys}
+		m      = &Model{Systems: SoftwareSystems{sys}}
+	)
+	sys.Containers = Containers{api, worker, db}
+	for _, e := range []interface{}{sys, api, worker, db} {
+		Identify(e)
+	}
+	views := Root.Views
+	defer func() {
+		Root.Views = views
+		for _, id := range []string{sys.ID, api.ID, worker.ID, db.ID} {
+			delete(Registry, id)
+		}
+	}()
+	Root.Views = &Views{}
+
+	got := m.GenerateComponentViews(func(c *Container) bool { return hasTag(c.Tags, "Service") })
+
+	want := []struct {
+		key       string
+		container *Container
+	}{
+		{"GeneratedSystemAPIComponents", api},
+		{"GeneratedSystemWorkerComponents", worker},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d views, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		v := got[i]
+		if v.Key != w.key {
+			t.Errorf("view %d: got key %q, want %q", i, v.Key, w.key)
+		}
+		if v.ContainerID != w.container.ID {
+			t.Errorf("view %d: got container ID %q, want %q", i, v.ContainerID, w.container.ID)
+		}
+		if !v.AddAll {
+			t.Errorf("view %d: AddAll not set", i)
+		}
+		if v.AutoLayout() == nil {
+			t.Errorf("view %d: no automatic layout", i)
+		}
+	}
+	if len(Root.Views.ComponentViews) != 2 {
+		t.Errorf("got %d component views in design, want 2", len(Root.Views.ComponentViews))
+	}
+	if again := m.GenerateComponentViews(func(*Container) bool { return true }); len(again) != 1 || again[0].ContainerID != db.ID {
+		t.Errorf("got %d views when generating again, want only the view of %q", len(again), db.Name)
+	}
+}
+
+func TestViewsValidateRemovedElement(t *testing.T) {
+	var (
+		kept    = &SoftwareSystem{Element: &Element{Name: "Stale Kept"}}
+		removed = &SoftwareSystem{Element: &Element{Name: "Stale Removed"}}
+	)
+	for _, s := range []*SoftwareSystem{kept, removed} {
+		Identify(s)
+	}
+	defer delete(Registry, kept.ID)
+	lv := &LandscapeView{ViewProps: &ViewProps{Key: "landscape"}}
+	if err := lv.AddElements(kept, removed); err != nil {
+		t.Fatal(err)
+	}
+	vs := &Views{LandscapeViews: []*LandscapeView{lv}}
+	delete(Registry, removed.ID)
+
+	err := vs.Validate()
+
+	verr := err.(*eval.ValidationErrors)
+	if len(verr.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1: %s", len(verr.Errors), err)
+	}
+	msg := verr.Errors[0].Error()
+	for _, want := range []string{`"landscape"`, `"Stale Removed"`, removed.ID} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("got error %q, want it to contain %s", msg, want)
+		}
+	}
+}
+
+func TestViewsFinalizeIdempotentAdd(t *testing.T) {
+	var (
+		user = &Person{Element: &Element{Name: "Idempotent User"}}
+		sys  = &SoftwareSystem{Element: &Element{Name: "Idempotent System"}}
+		uses = &Relationship{Source: user.Element, Destination: sys.Element, Description: "Uses"}
+	)
+	user.Relationships = []*Relationship{uses}
+	for _, e := range []interface{}{user, sys, uses} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, sys.ID, uses.ID} {
+			delete(Registry, id)
+		}
+	}()
+	model, views := Root.Model, Root.Views
+	defer func() { Root.Model, Root.Views = model, views }()
+	Root.Model = &Model{People: People{user}, Systems: SoftwareSystems{sys}}
+	lv := &LandscapeView{ViewProps: &ViewProps{Key: "landscape", AddAll: true}}
+	if err := lv.AddElements(user); err != nil {
+		t.Fatal(err)
+	}
+	x, y := 10, 20
+	lv.ElementViews = append(lv.ElementViews, &ElementView{Element: user.Element, X: &x, Y: &y})
+	for i := 0; i < 2; i++ {
+		lv.RelationshipViews = append(lv.RelationshipViews, &RelationshipView{Source: user.Element, Destination: sys.Element, Description: "Uses", RelationshipID: uses.ID})
+	}
+	Root.Views = &Views{LandscapeViews: []*LandscapeView{lv}}
+
+	Root.Views.Finalize()
+
+	if len(lv.ElementViews) != 2 {
+		t.Fatalf("got %d element views, want 2", len(lv.ElementViews))
+	}
+	if ev := lv.ElementView(user.ID); ev.X == nil || *ev.X != x || *ev.Y != y {
+		t.Errorf("got coordinates %v, %v, want %d, %d", ev.X, ev.Y, x, y)
+	}
+	if len(lv.RelationshipViews) != 1 {
+		t.Errorf("got %d relationship views, want 1", len(lv.RelationshipViews))
+	}
+}
+
+func TestViewsValidateExcludeSubtree(t *testing.T) {
+	var (
+		sys    = &SoftwareSystem{Element: &Element{Name: "Exclude System"}}
+		other  = &SoftwareSystem{Element: &Element{Name: "Exclude Other"}}
+		db     = &Container{Element: &Element{Name: "Exclude DB"}, System: other}
+		absent = &SoftwareSystem{Element: &Element{Name: "Exclude Absent"}}
+		uses   = &Relationship{Source: db.Element, Destination: sys.Element, Description: "Notifies"}
+	)
+	other.Containers = Containers{db}
+	db.Relationships = []*Relationship{uses}
+	for _, e := range []interface{}{sys, other, db, absent, uses} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{sys.ID, other.ID, db.ID, absent.ID, uses.ID} {
+			delete(Registry, id)
+		}
+	}()
+	model := Root.Model
+	defer func() { Root.Model = model }()
+	Root.Model = &Model{Systems: SoftwareSystems{sys, other}}
+
+	tests := []struct {
+		name    string
+		view    View
+		exclude *Element
+		wantErr bool
+	}{
+		{"added-by-add-all", &LandscapeView{ViewProps: &ViewProps{Key: "landscape", AddAll: true}}, other.Element, false},
+		{"descendant-added-by-neighbors", &ContainerView{ViewProps: &ViewProps{Key: "containers", AddNeighbors: []*Element{sys.Element}}, SoftwareSystemID: sys.ID}, other.Element, false},
+		{"not-in-view", &LandscapeView{ViewProps: &ViewProps{Key: "landscape", AddAll: true}}, absent.Element, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vp := tt.view.Props()
+			vp.RemoveSubtrees = []*Element{tt.exclude}
+			vs := &Views{}
+			switch v := tt.view.(type) {
+			case *LandscapeView:
+				vs.LandscapeViews = []*LandscapeView{v}
+			case *ContainerView:
+				vs.ContainerViews = []*ContainerView{v}
+			}
+
+			err := vs.Validate()
+
+			verr := err.(*eval.ValidationErrors)
+			if tt.wantErr {
+				if len(verr.Errors) != 1 || !strings.Contains(verr.Errors[0].Error(), "ExcludeSubtree") {
+					t.Errorf("got errors %v, want an ExcludeSubtree error", err)
+				}
+			} else if len(verr.Errors) != 0 {
+				t.Errorf("got errors %s, want none", err)
+			}
+			if len(vp.ElementViews) != 0 || len(vp.RelationshipViews) != 0 {
+				t.Errorf("got %d element and %d relationship views, want the view unchanged", len(vp.ElementViews), len(vp.RelationshipViews))
+			}
+		})
+	}
+}
+
+func TestViewsValidateDynamicStepDescriptions(t *testing.T) {
+	var (
+		user  = &Person{Element: &Element{Name: "Steps User"}}
+		sys   = &SoftwareSystem{Element: &Element{Name: "Steps System"}}
+		quote = &Relationship{Source: user.Element, Destination: sys.Element}
+	)
+	user.Relationships = []*Relationship{quote}
+	for _, e := range []interface{}{user, sys, quote} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, sys.ID, quote.ID} {
+			delete(Registry, id)
+		}
+	}()
+
+	tests := []struct {
+		name            string
+		relationship    string
+		step            *RelationshipView
+		wantDescription bool
+	}{
+		{"no-description", "", &RelationshipView{Source: user.Element, Destination: sys.Element}, true},
+		{"step-description", "Requests quote", &RelationshipView{Source: user.Element, Destination: sys.Element, Description: "Requests quote"}, false},
+		{"relationship-description", "Requests quote", &RelationshipView{Source: user.Element, Destination: sys.Element, RelationshipID: quote.ID}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote.Description = tt.relationship
+			dv := &DynamicView{ViewProps: &ViewProps{Key: "steps", RelationshipViews: []*RelationshipView{tt.step}}}
+			vs := &Views{DynamicViews: []*DynamicView{dv}}
+
+			err := vs.Validate()
+
+			verr := err.(*eval.ValidationErrors)
+			if tt.wantDescription {
+				if len(verr.Errors) != 1 || !strings.Contains(verr.Errors[0].Error(), "must have a description") {
+					t.Errorf("got errors %v, want a step description error", err)
+				}
+			} else if len(verr.Errors) != 0 {
+				t.Errorf("got errors %s, want none", err)
+			}
+		})
+	}
+}
+
+func TestViewPropsGroups(t *testing.T) {
+	var (
+		user = &Person{Element: &Element{Name: "Groups User"}}
+		shop = &SoftwareSystem{Element: &Element{Name: "Groups Shop", Group: "Team A/Backend"}}
+		crm  = &SoftwareSystem{Element: &Element{Name: "Groups CRM", Group: "Team B"}}
+		api  = &Container{Element: &Element{Name: "Groups API", Group: "Team C"}, System: crm}
+	)
+	crm.Containers = Containers{api}
+	for _, e := range []interface{}{user, shop, crm, api} {
+		Identify(e)
+	}
+	defer func() {
+		for _, id := range []string{user.ID, shop.ID, crm.ID, api.ID} {
+			delete(Registry, id)
+		}
+	}()
+	model := Root.Model
+	defer func() { Root.Model = model }()
+	Root.Model = &Model{People: People{user}, Systems: SoftwareSystems{shop, crm}}
+
+	tests := []struct {
+		name string
+		hide bool
+		want []string
+	}{
+		{"all", false, []string{"Team A", "Team A/Backend", "Team B"}},
+		{"hide-empty", true, []string{"Team A", "Team A/Backend"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lv := &LandscapeView{ViewProps: &ViewProps{Key: "landscape", HideEmptyGroups: tt.hide}}
+			if err := lv.AddElements(user, shop); err != nil {
+				t.Fatal(err)
+			}
+
+			groups := lv.Groups()
+
+			var paths []string
+			for _, g := range groups {
+				paths = append(paths, g.Path)
+			}
+			if strings.Join(paths, ", ") != strings.Join(tt.want, ", ") {
+				t.Fatalf("got groups %v, want %v", paths, tt.want)
+			}
+			if g := groups[1]; g.Name != "Backend" || len(g.Elements) != 1 || g.Elements[0] != shop.Element {
+				t.Errorf("got group %q with %d elements, want %q with %q", g.Name, len(g.Elements), "Backend", shop.Name)
+			}
+			if len(groups[0].Elements) != 0 {
+				t.Errorf("got %d elements in the parent group, want 0", len(groups[0].Elements))
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+)
+
+type (
+	// Warning describes a non fatal issue found while validating the
+	// design.
+	Warning struct {
+		// Expr is the expression that caused the warning.
+		Expr eval.Expression
+		// Message describes the issue.
+		Message string
+		// Severity of the issue, SeverityError if the model
+		// WarningsAreErrors option is set.
+		Severity SeverityKind
+	}
+
+	// SeverityKind is the enum for the possible severities of validation
+	// issues.
+	SeverityKind int
+)
+
+const (
+	// SeverityWarning is the severity of issues that do not cause the
+	// validation to fail.
+	SeverityWarning SeverityKind = iota
+	// SeverityError is the severity of issues that cause the validation to
+	// fail.
+	SeverityError
+)
+
+// Error returns the warning message prefixed with the name of the expression
+// that caused it.
+func (w *Warning) Error() string {
+	if w.Expr == nil {
+		return w.Message
+	}
+	return fmt.Sprintf("[%s] %s", w.Expr.EvalName(), w.Message)
+}
+
+// warn records a warning for the given expression.
+func (m *Model) warn(e eval.Expression, format string, vals ...interface{}) {
+	m.Warnings = append(m.Warnings, &Warning{Expr: e, Message: fmt.Sprintf(format, vals...)})
+}
+
+// String returns the name of the severity.
+func (s SeverityKind) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// escalateWarnings turns the recorded warnings into validation errors if the
+// model WarningsAreErrors option is set.
+func (m *Model) escalateWarnings(verr *eval.ValidationErrors) {
+	if !m.WarningsAreErrors {
+		return
+	}
+	for _, w := range m.Warnings {
+		w.Severity = SeverityError
+		verr.AddError(w.Expr, errors.New(w.Message))
+	}
+}
@@ -1,6 +1,9 @@
 package mdl
 
 import (
+	"sort"
+	"strings"
+
 	"goa.design/goa/v3/codegen"
 	"goa.design/model/expr"
 )
@@ -58,6 +61,27 @@ func findElement(dv *expr.DeploymentView, elem *expr.Element) *expr.ElementView
 	return nil
 }
 
+// instanceTooltip returns the properties of the container instance rendered by
+// the given element view formatted as "name: value" pairs sorted by name and
+// separated with sep. It returns an empty string if the element is not a
+// container instance or if the instance does not define properties.
+func instanceTooltip(ev *expr.ElementView, sep string) string {
+	ci, ok := expr.Registry[ev.Element.ID].(*expr.ContainerInstance)
+	if !ok || len(ci.Properties) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(ci.Properties))
+	for name := range ci.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + ": " + ci.Properties[name]
+	}
+	return strings.Join(pairs, sep)
+}
+
 const deploymentNodeStartT = `{{ indent .Indent }}subgraph {{ .ID }} [{{ .BoundaryName }}{{ if gt .Instances 1 }} x{{ .Instances }}{{ end }}]
 `
 
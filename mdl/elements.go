@@ -15,6 +15,9 @@ type (
 		// BoundaryName is the name of the subgraph rendered around the elements
 		// if any.
 		BoundaryName string
+		// BoundaryID is the ID of the subgraph rendered around the elements
+		// if any.
+		BoundaryID string
 		// Elements to render
 		Elements []*elementData
 	}
@@ -34,6 +37,10 @@ type (
 		Technology string
 		// URL to redirect to when element is clicked if any
 		URL string
+		// URLTooltip is the tooltip shown when hovering over an element
+		// with a URL if any. Container instances use their properties as
+		// tooltip.
+		URLTooltip string
 		// IconURL is the URL to an icon if any
 		IconURL string
 		// Background is the background color defined in the design if any
@@ -78,9 +85,10 @@ func elements(evs []*expr.ElementView, boundary string, ind int) *codegen.Sectio
 			Start:       start,
 			End:         end,
 			Name:        ev.Element.Name,
-			Description: ev.Element.Description,
+			Description: ev.Element.DescriptionWithResponsibilities(),
 			Technology:  tech,
 			URL:         ev.Element.URL,
+			URLTooltip:  strings.ReplaceAll(instanceTooltip(ev, ", "), `"`, "'"),
 			IconURL:     es.Icon,
 			Background:  es.Background,
 			Stroke:      es.Stroke,
@@ -88,6 +96,7 @@ func elements(evs []*expr.ElementView, boundary string, ind int) *codegen.Sectio
 	}
 	data := &elementsData{
 		BoundaryName: boundary,
+		BoundaryID:   "boundary",
 		Elements:     elems,
 	}
 	funcs := map[string]interface{}{"wrap": wrap, "stroke": stroke, "indent": indent}
@@ -186,7 +195,7 @@ func nodeStartEnd(ev *expr.ElementView) (string, string) {
 }
 
 // input: ElementsData
-const elementT = `{{ if .BoundaryName }}{{ indent 1 }}subgraph boundary [{{ .BoundaryName }}]
+const elementT = `{{ if .BoundaryName }}{{ indent 1 }}subgraph {{ .BoundaryID }} [{{ .BoundaryName }}]
 {{ end }}
 {{- range .Elements }}{{ indent .Indent }}{{ .ID }}{{ .Start }}"
 {{- if .IconURL }}<img src='{{ .IconURL }}'/>
@@ -200,5 +209,5 @@ const elementT = `{{ if .BoundaryName }}{{ indent 1 }}subgraph boundary [{{ .Bou
 {{- end }}
 {{ end }}
 {{- if .BoundaryName }}{{ indent 1 }}end
-{{ indent 1 }}style boundary fill:#ffffff,stroke:#909090,color:#000000,stroke-dasharray: 15 5;
+{{ indent 1 }}style {{ .BoundaryID }} fill:#ffffff,stroke:#909090,color:#000000,stroke-dasharray: 15 5;
 {{ end }}`
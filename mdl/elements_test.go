@@ -0,0 +1,36 @@
+package mdl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"goa.design/model/expr"
+)
+
+func TestElementsClickableURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{"with-url", "https://example.com/docs", true},
+		{"without-url", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elem := &expr.Element{ID: "elem", Name: "Element", URL: tt.url}
+			var buf bytes.Buffer
+			if err := elements([]*expr.ElementView{{Element: elem}}, "", 1).Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			got := buf.String()
+			if has := strings.Contains(got, "click elem"); has != tt.want {
+				t.Errorf("got click directive %v, want %v in %q", has, tt.want, got)
+			}
+			if tt.want && !strings.Contains(got, `click elem "`+tt.url+`"`) {
+				t.Errorf("got %q, want it to link to %q", got, tt.url)
+			}
+		})
+	}
+}
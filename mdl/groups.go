@@ -0,0 +1,93 @@
+package mdl
+
+import (
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/model/expr"
+)
+
+type (
+	// Group describes a group of elements of a rendered view.
+	Group struct {
+		// Path is the path of the group, the names of nested groups are
+		// separated with expr.GroupSeparator.
+		Path string
+		// Elements lists the IDs of the elements of the view that belong
+		// directly to the group.
+		Elements []string `json:",omitempty"`
+	}
+
+	// groupLineData is the data structure used to render a line of the
+	// groups template.
+	groupLineData struct {
+		// Indent of line in rendered mermaid source
+		Indent int
+		// ID of the group subgraph or of the element
+		ID string
+		// Name of the group for the line that starts the group subgraph
+		Name string
+		// Start is true for the line that starts the group subgraph.
+		Start bool
+		// End is true for the line that ends the group subgraph.
+		End bool
+	}
+)
+
+// renderedGroups returns the groups of the view.
+func renderedGroups(vp *expr.ViewProps) []*Group {
+	var res []*Group
+	for _, g := range vp.Groups() {
+		rg := &Group{Path: g.Path}
+		for _, e := range g.Elements {
+			rg.Elements = append(rg.Elements, e.ID)
+		}
+		res = append(res, rg)
+	}
+	return res
+}
+
+// groupBoundaries returns a section that renders a boundary around the
+// elements of each group of the view, nested groups are rendered in the
+// boundary of their parent. groupBoundaries returns nil if the view has no
+// group.
+func groupBoundaries(vp *expr.ViewProps) *codegen.SectionTemplate {
+	gs := vp.Groups()
+	if len(gs) == 0 {
+		return nil
+	}
+	type open struct{ path, id string }
+	var (
+		lines []*groupLineData
+		stack []open
+	)
+	pop := func() {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		lines = append(lines, &groupLineData{Indent: len(stack) + 1, ID: top.id, End: true})
+	}
+	for i, g := range gs {
+		for len(stack) > 0 && !strings.HasPrefix(g.Path, stack[len(stack)-1].path+expr.GroupSeparator) {
+			pop()
+		}
+		id := "group" + strconv.Itoa(i+1)
+		lines = append(lines, &groupLineData{Indent: len(stack) + 1, ID: id, Name: g.Name, Start: true})
+		stack = append(stack, open{g.Path, id})
+		for _, e := range g.Elements {
+			lines = append(lines, &groupLineData{Indent: len(stack) + 1, ID: e.ID})
+		}
+	}
+	for len(stack) > 0 {
+		pop()
+	}
+	return &codegen.SectionTemplate{Name: "groups", Source: groupsT, Data: lines, FuncMap: funcs}
+}
+
+// input: []*groupLineData
+const groupsT = `{{ range . }}{{ indent .Indent }}
+{{- if .Start }}subgraph {{ .ID }} [{{ .Name }}]
+{{ else if .End }}end
+{{ indent .Indent }}style {{ .ID }} fill:#ffffff,stroke:#909090,color:#000000,stroke-dasharray: 5 5;
+{{ else }}{{ .ID }}
+{{ end }}{{ end }}`
@@ -0,0 +1,71 @@
+package mdl
+
+import (
+	"bytes"
+	"testing"
+
+	"goa.design/model/expr"
+)
+
+func TestGroupBoundaries(t *testing.T) {
+	var (
+		shop = &expr.SoftwareSystem{Element: &expr.Element{Name: "Boundaries Shop", Group: "Team A/Backend"}}
+		crm  = &expr.SoftwareSystem{Element: &expr.Element{Name: "Boundaries CRM", Group: "Team B"}}
+	)
+	for _, s := range []*expr.SoftwareSystem{shop, crm} {
+		expr.Identify(s)
+	}
+	defer func() {
+		for _, s := range []*expr.SoftwareSystem{shop, crm} {
+			delete(expr.Registry, s.ID)
+		}
+	}()
+	model := expr.Root.Model
+	defer func() { expr.Root.Model = model }()
+	expr.Root.Model = &expr.Model{Systems: expr.SoftwareSystems{shop, crm}}
+
+	tests := []struct {
+		name string
+		hide bool
+		want string
+	}{
+		{"all", false, "    subgraph group1 [Team A]\n" +
+			"        subgraph group2 [Backend]\n" +
+			"            " + shop.ID + "\n" +
+			"        end\n" +
+			"        style group2 fill:#ffffff,stroke:#909090,color:#000000,stroke-dasharray: 5 5;\n" +
+			"    end\n" +
+			"    style group1 fill:#ffffff,stroke:#909090,color:#000000,stroke-dasharray: 5 5;\n" +
+			"    subgraph group3 [Team B]\n" +
+			"    end\n" +
+			"    style group3 fill:#ffffff,stroke:#909090,color:#000000,stroke-dasharray: 5 5;\n"},
+		{"hide-empty", true, "    subgraph group1 [Team A]\n" +
+			"        subgraph group2 [Backend]\n" +
+			"            " + shop.ID + "\n" +
+			"        end\n" +
+			"        style group2 fill:#ffffff,stroke:#909090,color:#000000,stroke-dasharray: 5 5;\n" +
+			"    end\n" +
+			"    style group1 fill:#ffffff,stroke:#909090,color:#000000,stroke-dasharray: 5 5;\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vp := &expr.ViewProps{Key: "landscape", HideEmptyGroups: tt.hide, ElementViews: []*expr.ElementView{{Element: shop.Element}}}
+			var buf bytes.Buffer
+
+			if err := groupBoundaries(vp).Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+			groups := renderedGroups(vp)
+			if g := groups[1]; g.Path != "Team A/Backend" || len(g.Elements) != 1 || g.Elements[0] != shop.ID {
+				t.Errorf("got group %q with elements %v, want %q with %q", g.Path, g.Elements, "Team A/Backend", shop.ID)
+			}
+			if tt.hide && len(groups) != 2 {
+				t.Errorf("got %d rendered groups, want the empty group omitted", len(groups))
+			}
+		})
+	}
+}
@@ -20,16 +20,24 @@ type (
 
 func relationships(rvs []*expr.RelationshipView) *codegen.SectionTemplate {
 	data := make([]*relationshipData, len(rvs))
+	appendTech := expr.Root.Model.AppendTechnologyToLabels
 	for i, rv := range rvs {
 		rel := expr.Registry[rv.RelationshipID].(*expr.Relationship)
 		start, end := lineStartEnd(relStyle(rv))
+		src, dest := rv.Source, rv.Destination
+		if rv.Response {
+			src, dest = dest, src
+		}
 		data[i] = &relationshipData{
-			SourceID:      rv.Source.ID,
-			DestinationID: rv.Destination.ID,
-			Description:   rv.Description,
+			SourceID:      src.ID,
+			DestinationID: dest.ID,
+			Description:   rel.Label(rv.Description, appendTech),
 			Start:         start,
 			End:           end,
-			Technology:    rel.Technology,
+		}
+		if !appendTech {
+			// Technology is rendered separately.
+			data[i].Technology = rel.Technology
 		}
 	}
 	funcs := map[string]interface{}{"wrap": wrap, "indent": indent}
@@ -0,0 +1,40 @@
+package mdl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"goa.design/model/expr"
+)
+
+func TestRelationshipsLabel(t *testing.T) {
+	src := &expr.Element{ID: "src", Name: "Source"}
+	dest := &expr.Element{ID: "dest", Name: "Destination"}
+	rel := &expr.Relationship{ID: "rel", Source: src, Destination: dest, Description: "Reads from", Technology: "SQL"}
+	expr.Registry[rel.ID] = rel
+	defer delete(expr.Registry, rel.ID)
+	rvs := []*expr.RelationshipView{{Source: src, Destination: dest, Description: rel.Description, RelationshipID: rel.ID}}
+
+	tests := []struct {
+		name       string
+		appendTech bool
+		want       string
+	}{
+		{"separate", false, "<div class='relationship-label'>Reads from</div><div class='relationship-technology'>[SQL]</div>"},
+		{"appended", true, "<div class='relationship-label'>Reads from [SQL]</div></div>"},
+	}
+	defer func(v bool) { expr.Root.Model.AppendTechnologyToLabels = v }(expr.Root.Model.AppendTechnologyToLabels)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr.Root.Model.AppendTechnologyToLabels = tt.appendTech
+			var buf bytes.Buffer
+			if err := relationships(rvs).Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
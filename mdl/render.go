@@ -15,14 +15,19 @@ type (
 		Key string
 		// Title of view
 		Title string
+		// HideTitle is true if the title should not be rendered.
+		HideTitle bool `json:",omitempty"`
 		// Version of design
 		Version string
 		// Description of view if any
 		Description string
 		// Mermaid contains the Mermaid source for the diagram.
 		Mermaid string
-		// Legend contains the Mermaid source for the legend.
+		// Legend contains the Mermaid source for the legend, empty if
+		// the legend is disabled.
 		Legend string
+		// Groups lists the groups of the view, see expr.ViewProps.Groups.
+		Groups []*Group `json:",omitempty"`
 		// Nodes contains additional information for each node rendered in the
 		// diagram and is indexed by node ID (which corresponds to the ID of the
 		// underlying element).
@@ -120,12 +125,14 @@ func render(sourceFile, legendFile *codegen.File, view expr.View, d *expr.Design
 			panic("render: " + err.Error()) // bug
 		}
 	}
-	for _, s := range legendFile.SectionTemplates {
-		if err := s.Write(&legend); err != nil {
-			panic("render: " + err.Error()) // bug
+	vp := view.Props()
+	if vp.Legend == nil || *vp.Legend {
+		for _, s := range legendFile.SectionTemplates {
+			if err := s.Write(&legend); err != nil {
+				panic("render: " + err.Error()) // bug
+			}
 		}
 	}
-	vp := view.Props()
 	nodes := make(map[string]*Node, len(vp.ElementViews))
 	for _, ev := range vp.ElementViews {
 		var evk string
@@ -159,10 +166,12 @@ func render(sourceFile, legendFile *codegen.File, view expr.View, d *expr.Design
 	return &RenderedView{
 		Key:         vp.Key,
 		Title:       title,
+		HideTitle:   vp.HideTitle,
 		Version:     d.Version,
 		Description: vp.Description,
 		Mermaid:     source.String(),
 		Legend:      legend.String(),
+		Groups:      renderedGroups(vp),
 		Nodes:       nodes,
 	}
 }
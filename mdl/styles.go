@@ -48,49 +48,10 @@ func interpolate(rs *expr.RelationshipStyle) string {
 
 // elementStyle compute the style of the given element view. It does that by
 // merging all the styling information from all styles that apply (i.e. that
-// apply to a tag of the corresponding element).
-func elemStyle(ev *expr.ElementView) (style *expr.ElementStyle) {
-	style = &expr.ElementStyle{}
-	styles := expr.Root.Views.Styles
-	if styles == nil {
-		return
-	}
-loop:
-	for _, tag := range strings.Split(ev.Element.Tags, ",") {
-		for _, es := range styles.Elements {
-			if tag == es.Tag {
-				if es.Background != "" {
-					style.Background = es.Background
-				}
-				if es.Stroke != "" {
-					style.Stroke = es.Stroke
-				}
-				if es.Color != "" {
-					style.Color = es.Color
-				}
-				if es.Shape != expr.ShapeUndefined {
-					style.Shape = es.Shape
-				}
-				if es.Icon != "" {
-					style.Icon = es.Icon
-				}
-				if es.Opacity != nil {
-					style.Opacity = es.Opacity
-				}
-				if es.Metadata != nil {
-					style.Metadata = es.Metadata
-				}
-				if es.Description != nil {
-					style.Description = es.Description
-				}
-				if es.Border != expr.BorderUndefined {
-					style.Border = es.Border
-				}
-				continue loop
-			}
-		}
-	}
-	return
+// apply to a tag of the corresponding element) and the view-scoped style of
+// the element if any.
+func elemStyle(ev *expr.ElementView) *expr.ElementStyle {
+	return ev.ResolvedStyle()
 }
 
 // relationshipStyle compute the style of the given relationship view. It does that by
@@ -0,0 +1,286 @@
+package mdl
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	"goa.design/model/expr"
+)
+
+const (
+	// svgElementWidth is the width of rendered elements.
+	svgElementWidth = 450
+	// svgElementHeight is the height of rendered elements.
+	svgElementHeight = 300
+	// svgMargin is the margin added around the diagram.
+	svgMargin = 50
+	// svgLegendWidth is the width of the legend box.
+	svgLegendWidth = 400
+	// svgLegendRowHeight is the height of each row of the legend.
+	svgLegendRowHeight = 40
+	// svgLegendPadding is the padding inside the legend box.
+	svgLegendPadding = 15
+)
+
+type (
+	// svgPoint is a point in the SVG coordinate system.
+	svgPoint struct{ X, Y float64 }
+
+	// svgBox is the area covered by a rendered element.
+	svgBox struct{ X, Y, W, H float64 }
+)
+
+// RenderSVG writes a best-effort SVG rendering of the given view to w.
+// RenderSVG does not compute a layout: all the elements of the view must have
+// coordinates, either set in the design or loaded from a Structurizr
+// workspace. Elements are drawn as boxes with their shape, name and technology
+// at the given coordinates using the element size if set. Relationships are
+// drawn as straight lines or as orthogonal lines depending on their routing,
+// going through their vertices if any. If the view sets ShowLegend, a legend
+// mapping the tags of the view to their styles is rendered below the diagram.
+func RenderSVG(v expr.View, w io.Writer) error {
+	vp := v.Props()
+	boxes := make(map[string]svgBox, len(vp.ElementViews))
+	var width, height float64
+	for _, ev := range vp.ElementViews {
+		if ev.X == nil || ev.Y == nil {
+			return fmt.Errorf("view %q: element %q has no coordinates", vp.Key, ev.Element.Name)
+		}
+		b := svgBox{float64(*ev.X + svgMargin), float64(*ev.Y + svgMargin), svgElementWidth, svgElementHeight}
+		if size := ev.Element.Size; size != nil {
+			b.W, b.H = float64(size.Width), float64(size.Height)
+		}
+		boxes[ev.Element.ID] = b
+		width = math.Max(width, b.X+b.W+svgMargin)
+		height = math.Max(height, b.Y+b.H+svgMargin)
+	}
+	var legend []*expr.LegendEntry
+	legendY := height
+	if vp.ShowLegend {
+		legend = vp.LegendEntries()
+	}
+	if len(legend) > 0 {
+		width = math.Max(width, svgMargin+svgLegendWidth+svgMargin)
+		height += float64(svgLegendHeight(legend) + svgMargin)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%g\" height=\"%g\" viewBox=\"0 0 %g %g\" font-family=\"sans-serif\">\n", width, height, width, height)
+	sb.WriteString("<defs><marker id=\"arrow\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"8\" markerHeight=\"8\" orient=\"auto-start-reverse\"><path d=\"M 0 0 L 10 5 L 0 10 z\"/></marker></defs>\n")
+	for _, rv := range vp.RelationshipViews {
+		src, ok := boxes[rv.Source.ID]
+		if !ok {
+			continue
+		}
+		dest, ok := boxes[rv.Destination.ID]
+		if !ok {
+			continue
+		}
+		if rv.Response {
+			src, dest = dest, src
+		}
+		svgRelationship(&sb, rv, src, dest)
+	}
+	for _, ev := range vp.ElementViews {
+		svgElement(&sb, ev, boxes[ev.Element.ID])
+	}
+	if len(legend) > 0 {
+		svgLegend(&sb, legend, svgMargin, legendY)
+	}
+	sb.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// svgElement renders the given element view in the given box. Elements with a
+// URL are wrapped in a link. Container instances with properties are grouped
+// with a title listing the properties so that they show as a tooltip.
+func svgElement(sb *strings.Builder, ev *expr.ElementView, b svgBox) {
+	style := elemStyle(ev)
+	bg := style.Background
+	if bg == "" {
+		bg = "#dddddd"
+	}
+	color := style.Color
+	if color == "" {
+		color = "#000000"
+	}
+	if u := ev.Element.URL; u != "" {
+		fmt.Fprintf(sb, "<a href=\"%s\" target=\"_blank\">\n", html.EscapeString(u))
+		defer sb.WriteString("</a>\n")
+	}
+	if tooltip := instanceTooltip(ev, "\n"); tooltip != "" {
+		fmt.Fprintf(sb, "<g>\n<title>%s</title>\n", html.EscapeString(tooltip))
+		defer sb.WriteString("</g>\n")
+	}
+	attrs := fmt.Sprintf("fill=\"%s\" stroke=\"%s\" stroke-width=\"2\"", bg, stroke(&elementData{Background: bg, Stroke: style.Stroke}))
+	svgShape(sb, style.Shape, b, attrs)
+	cx, cy := b.X+b.W/2, b.Y+b.H/2
+	fmt.Fprintf(sb, "<text x=\"%g\" y=\"%g\" fill=\"%s\" font-size=\"24\" font-weight=\"bold\" text-anchor=\"middle\">%s</text>\n", cx, cy, color, html.EscapeString(ev.Element.Name))
+	if tech := ev.Element.Technology; tech != "" {
+		fmt.Fprintf(sb, "<text x=\"%g\" y=\"%g\" fill=\"%s\" font-size=\"18\" text-anchor=\"middle\">[%s]</text>\n", cx, cy+28, color, html.EscapeString(tech))
+	}
+}
+
+// svgShape renders the given shape in the given box using the given SVG
+// attributes.
+func svgShape(sb *strings.Builder, shape expr.ShapeKind, b svgBox, attrs string) {
+	cx, cy := b.X+b.W/2, b.Y+b.H/2
+	switch shape {
+	case expr.ShapeRoundedBox:
+		fmt.Fprintf(sb, "<rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" rx=\"%g\" %s/>\n", b.X, b.Y, b.W, b.H, math.Min(20, b.H/4), attrs)
+	case expr.ShapeCircle, expr.ShapeEllipse:
+		fmt.Fprintf(sb, "<ellipse cx=\"%g\" cy=\"%g\" rx=\"%g\" ry=\"%g\" %s/>\n", cx, cy, b.W/2, b.H/2, attrs)
+	case expr.ShapeCylinder:
+		e := b.H / 10
+		fmt.Fprintf(sb, "<path d=\"M %g %g A %g %g 0 0 1 %g %g V %g A %g %g 0 0 1 %g %g Z M %g %g A %g %g 0 0 0 %g %g\" %s/>\n",
+			b.X, b.Y+e, b.W/2, e, b.X+b.W, b.Y+e, b.Y+b.H-e, b.W/2, e, b.X, b.Y+b.H-e,
+			b.X, b.Y+e, b.W/2, e, b.X+b.W, b.Y+e, attrs)
+	default:
+		fmt.Fprintf(sb, "<rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" %s/>\n", b.X, b.Y, b.W, b.H, attrs)
+	}
+}
+
+// svgLegend renders the tag legend of the view (see expr.ViewProps.LegendEntries)
+// at the given position. Each element style is drawn as a small shape and
+// each relationship style as a short line followed by the tag.
+func svgLegend(sb *strings.Builder, entries []*expr.LegendEntry, x, y float64) {
+	fmt.Fprintf(sb, "<g class=\"legend\">\n<rect x=\"%g\" y=\"%g\" width=\"%d\" height=\"%d\" fill=\"#ffffff\" stroke=\"#909090\"/>\n", x, y, svgLegendWidth, svgLegendHeight(entries))
+	fmt.Fprintf(sb, "<text x=\"%g\" y=\"%g\" font-size=\"20\" font-weight=\"bold\">Legend</text>\n", x+svgLegendPadding, y+svgLegendPadding+16)
+	row := y + svgLegendPadding + svgLegendRowHeight
+	for _, e := range entries {
+		if es := e.Element; es != nil {
+			bg := es.Background
+			if bg == "" {
+				bg = "#dddddd"
+			}
+			attrs := fmt.Sprintf("fill=\"%s\" stroke=\"%s\" stroke-width=\"2\"", bg, stroke(&elementData{Background: bg, Stroke: es.Stroke}))
+			svgShape(sb, es.Shape, svgBox{x + svgLegendPadding, row + 5, 60, 30}, attrs)
+			fmt.Fprintf(sb, "<text x=\"%g\" y=\"%g\" font-size=\"18\">%s</text>\n", x+2*svgLegendPadding+60, row+26, html.EscapeString(e.Tag))
+			row += svgLegendRowHeight
+		}
+		if rs := e.Relationship; rs != nil {
+			color := rs.Color
+			if color == "" {
+				color = "#707070"
+			}
+			thickness := 2
+			if rs.Thick != nil && *rs.Thick {
+				thickness = 4
+			}
+			var dash string
+			if rs.Dashed != nil && *rs.Dashed {
+				dash = " stroke-dasharray=\"10,6\""
+			}
+			fmt.Fprintf(sb, "<line x1=\"%g\" y1=\"%g\" x2=\"%g\" y2=\"%g\" stroke=\"%s\" stroke-width=\"%d\"%s marker-end=\"url(#arrow)\"/>\n", x+svgLegendPadding, row+20, x+svgLegendPadding+60, row+20, color, thickness, dash)
+			fmt.Fprintf(sb, "<text x=\"%g\" y=\"%g\" font-size=\"18\">%s</text>\n", x+2*svgLegendPadding+60, row+26, html.EscapeString(e.Tag))
+			row += svgLegendRowHeight
+		}
+	}
+	sb.WriteString("</g>\n")
+}
+
+// svgLegendHeight returns the height of the legend box rendering the given
+// entries.
+func svgLegendHeight(entries []*expr.LegendEntry) int {
+	rows := 1
+	for _, e := range entries {
+		if e.Element != nil {
+			rows++
+		}
+		if e.Relationship != nil {
+			rows++
+		}
+	}
+	return rows*svgLegendRowHeight + 2*svgLegendPadding
+}
+
+// svgRelationship renders the given relationship view between the src and
+// dest boxes.
+func svgRelationship(sb *strings.Builder, rv *expr.RelationshipView, src, dest svgBox) {
+	style := &expr.RelationshipStyle{}
+	description := rv.Description
+	if rel, ok := expr.Registry[rv.RelationshipID].(*expr.Relationship); ok {
+		style = relStyle(rv)
+		if description == "" {
+			description = rel.Description
+		}
+		description = rel.Label(description, expr.Root.Model.AppendTechnologyToLabels)
+	}
+	routing := rv.Routing
+	if routing == expr.RoutingUndefined {
+		routing = style.Routing
+	}
+
+	points := []svgPoint{src.center()}
+	for _, v := range rv.Vertices {
+		points = append(points, svgPoint{float64(v.X + svgMargin), float64(v.Y + svgMargin)})
+	}
+	points = append(points, dest.center())
+	if routing == expr.RoutingOrthogonal {
+		ortho := []svgPoint{points[0]}
+		for _, p := range points[1:] {
+			prev := ortho[len(ortho)-1]
+			if prev.X != p.X && prev.Y != p.Y {
+				ortho = append(ortho, svgPoint{p.X, prev.Y})
+			}
+			ortho = append(ortho, p)
+		}
+		points = ortho
+	}
+	points[0] = src.clip(points[0], points[1])
+	points[len(points)-1] = dest.clip(points[len(points)-1], points[len(points)-2])
+
+	color := style.Color
+	if color == "" {
+		color = "#707070"
+	}
+	thickness := 2
+	if style.Thick != nil && *style.Thick {
+		thickness = 4
+	}
+	var dash string
+	if style.Dashed != nil && *style.Dashed {
+		dash = " stroke-dasharray=\"10,6\""
+	}
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = fmt.Sprintf("%g,%g", p.X, p.Y)
+	}
+	fmt.Fprintf(sb, "<polyline points=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%d\"%s marker-end=\"url(#arrow)\"/>\n", strings.Join(coords, " "), color, thickness, dash)
+	if description != "" {
+		i := len(points) / 2
+		mid := svgPoint{(points[i-1].X + points[i].X) / 2, (points[i-1].Y + points[i].Y) / 2}
+		fmt.Fprintf(sb, "<text x=\"%g\" y=\"%g\" fill=\"%s\" font-size=\"18\" text-anchor=\"middle\">%s</text>\n", mid.X, mid.Y-6, color, html.EscapeString(description))
+	}
+}
+
+// center returns the center of the box.
+func (b svgBox) center() svgPoint {
+	return svgPoint{b.X + b.W/2, b.Y + b.H/2}
+}
+
+// clip returns the point where the segment going from p (inside the box) to
+// toward intersects the box border.
+func (b svgBox) clip(p, toward svgPoint) svgPoint {
+	dx, dy := toward.X-p.X, toward.Y-p.Y
+	t := math.Inf(1)
+	if dx > 0 {
+		t = math.Min(t, (b.X+b.W-p.X)/dx)
+	} else if dx < 0 {
+		t = math.Min(t, (b.X-p.X)/dx)
+	}
+	if dy > 0 {
+		t = math.Min(t, (b.Y+b.H-p.Y)/dy)
+	} else if dy < 0 {
+		t = math.Min(t, (b.Y-p.Y)/dy)
+	}
+	if math.IsInf(t, 1) || t > 1 {
+		return p
+	}
+	return svgPoint{p.X + t*dx, p.Y + t*dy}
+}
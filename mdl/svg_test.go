@@ -0,0 +1,152 @@
+package mdl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"goa.design/model/expr"
+)
+
+func TestRenderSVG(t *testing.T) {
+	src := &expr.Element{ID: "src", Name: "Source"}
+	dest := &expr.Element{ID: "dest", Name: "Destination", Technology: "Go"}
+	rel := &expr.Relationship{ID: "rel", Source: src, Destination: dest, Description: "Calls"}
+	expr.Registry[rel.ID] = rel
+	defer delete(expr.Registry, rel.ID)
+	coords := func(x, y int) (*int, *int) { return &x, &y }
+
+	tests := []struct {
+		name    string
+		elems   func() []*expr.ElementView
+		routing expr.RoutingKind
+		want    []string
+		wantErr string
+	}{
+		{"straight", func() []*expr.ElementView {
+			sx, sy := coords(0, 0)
+			dx, dy := coords(1000, 0)
+			return []*expr.ElementView{{Element: src, X: sx, Y: sy}, {Element: dest, X: dx, Y: dy}}
+		}, expr.RoutingUndefined, []string{
+			`<rect x="50" y="50" width="450" height="300"`,
+			`<rect x="1050" y="50" width="450" height="300"`,
+			`>Source</text>`,
+			`>[Go]</text>`,
+			`<polyline points="500,200 1050,200"`,
+			`>Calls</text>`,
+		}, ""},
+		{"orthogonal", func() []*expr.ElementView {
+			sx, sy := coords(0, 0)
+			dx, dy := coords(1000, 600)
+			return []*expr.ElementView{{Element: src, X: sx, Y: sy}, {Element: dest, X: dx, Y: dy}}
+		}, expr.RoutingOrthogonal, []string{
+			`<polyline points="500,200 1275,200 1275,650"`,
+		}, ""},
+		{"missing-coordinates", func() []*expr.ElementView {
+			sx, sy := coords(0, 0)
+			return []*expr.ElementView{{Element: src, X: sx, Y: sy}, {Element: dest}}
+		}, expr.RoutingUndefined, nil, `element "Destination" has no coordinates`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &expr.LandscapeView{ViewProps: &expr.ViewProps{
+				Key:               "landscape",
+				ElementViews:      tt.elems(),
+				RelationshipViews: []*expr.RelationshipView{{Source: src, Destination: dest, RelationshipID: rel.ID, Routing: tt.routing}},
+			}}
+			var buf bytes.Buffer
+
+			err := RenderSVG(v, &buf)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("got %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderSVGInstanceTooltip(t *testing.T) {
+	x, y := 0, 0
+	tests := []struct {
+		name  string
+		props map[string]string
+		want  string
+	}{
+		{"with-properties", map[string]string{"version": "1.2", "region": "us-east-1"}, "<title>region: us-east-1\nversion: 1.2</title>"},
+		{"without-properties", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ci := &expr.ContainerInstance{Element: &expr.Element{ID: "instance", Name: "API", Properties: tt.props}}
+			expr.Registry[ci.ID] = ci
+			defer delete(expr.Registry, ci.ID)
+			dv := &expr.DeploymentView{ViewProps: &expr.ViewProps{
+				Key:          "deployment",
+				ElementViews: []*expr.ElementView{{Element: ci.Element, X: &x, Y: &y}},
+			}}
+			var buf bytes.Buffer
+			if err := RenderSVG(dv, &buf); err != nil {
+				t.Fatal(err)
+			}
+			got := buf.String()
+			if tt.want == "" {
+				if strings.Contains(got, "<title>") {
+					t.Errorf("got %q, want no title", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSVGLabel(t *testing.T) {
+	x, y := 0, 0
+	destX := 1000
+	src := &expr.Element{ID: "src", Name: "Source"}
+	dest := &expr.Element{ID: "dest", Name: "Destination"}
+	rel := &expr.Relationship{ID: "rel", Source: src, Destination: dest, Description: "Reads from", Technology: "SQL"}
+	expr.Registry[rel.ID] = rel
+	defer delete(expr.Registry, rel.ID)
+	sv := &expr.LandscapeView{ViewProps: &expr.ViewProps{
+		Key:               "context",
+		ElementViews:      []*expr.ElementView{{Element: src, X: &x, Y: &y}, {Element: dest, X: &destX, Y: &y}},
+		RelationshipViews: []*expr.RelationshipView{{Source: src, Destination: dest, RelationshipID: rel.ID}},
+	}}
+
+	tests := []struct {
+		name       string
+		appendTech bool
+		want       string
+	}{
+		{"description", false, ">Reads from</text>"},
+		{"appended", true, ">Reads from [SQL]</text>"},
+	}
+	defer func(v bool) { expr.Root.Model.AppendTechnologyToLabels = v }(expr.Root.Model.AppendTechnologyToLabels)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr.Root.Model.AppendTechnologyToLabels = tt.appendTech
+			var buf bytes.Buffer
+			if err := RenderSVG(sv, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -48,7 +48,11 @@ func landscapeDiagram(lv *expr.LandscapeView) *codegen.File {
 	if lv.EnterpriseBoundaryVisible != nil {
 		ebv = *lv.EnterpriseBoundaryVisible
 	}
-	return landscapeOrContextDiagram(lv.ViewProps, ebv)
+	var groups []*expr.EnterpriseGroup
+	if lv.GroupByEnterprise {
+		groups = lv.EnterpriseGroups()
+	}
+	return landscapeOrContextDiagram(lv.ViewProps, ebv, groups)
 }
 
 // contextDiagram produces a file that contains Mermaid code representing the
@@ -58,14 +62,25 @@ func contextDiagram(cv *expr.ContextView) *codegen.File {
 	if cv.EnterpriseBoundaryVisible != nil {
 		ebv = *cv.EnterpriseBoundaryVisible
 	}
-	return landscapeOrContextDiagram(cv.ViewProps, ebv)
+	return landscapeOrContextDiagram(cv.ViewProps, ebv, nil)
 }
 
 // landscapeOrContextDiagram contains the shared logic between landscapeDiagram
-// and contextDiagram.
-func landscapeOrContextDiagram(vp *expr.ViewProps, ebv bool) *codegen.File {
+// and contextDiagram. The elements of the given enterprise groups are rendered
+// in a boundary per enterprise and the groups of the view in a boundary per
+// group.
+func landscapeOrContextDiagram(vp *expr.ViewProps, ebv bool, groups []*expr.EnterpriseGroup) *codegen.File {
+	grouped := make(map[string]bool)
+	for _, g := range groups {
+		for _, e := range g.Elements {
+			grouped[e.ID] = true
+		}
+	}
 	var internal, external []*expr.ElementView
 	for _, ev := range vp.ElementViews {
+		if grouped[ev.Element.ID] {
+			continue
+		}
 		switch a := expr.Registry[ev.Element.ID].(type) {
 		case *expr.Person:
 			if a.Location == expr.LocationUndefined || a.Location == expr.LocationInternal {
@@ -96,6 +111,18 @@ func landscapeOrContextDiagram(vp *expr.ViewProps, ebv bool) *codegen.File {
 	if len(internal) > 0 {
 		sections = append(sections, elements(internal, boundaryName, 1))
 	}
+	for i, g := range groups {
+		evs := make([]*expr.ElementView, len(g.Elements))
+		for j, e := range g.Elements {
+			evs[j] = vp.ElementView(e.ID)
+		}
+		section := elements(evs, g.Name, 1)
+		section.Data.(*elementsData).BoundaryID = "enterprise" + strconv.Itoa(i+1)
+		sections = append(sections, section)
+	}
+	if section := groupBoundaries(vp); section != nil {
+		sections = append(sections, section)
+	}
 	if len(vp.RelationshipViews) > 0 {
 		sections = append(sections, relationships(vp.RelationshipViews))
 	}
@@ -165,6 +192,9 @@ func deploymentDiagram(dv *expr.DeploymentView) *codegen.File {
 			sections = append(sections, deploymentNodeSections(dv, dn, 1)...)
 		}
 	}
+	if len(dv.RelationshipViews) > 0 {
+		sections = append(sections, relationships(dv.RelationshipViews))
+	}
 	return viewDiagram(dv.ViewProps, sections)
 }
 
@@ -0,0 +1,67 @@
+/*
+Package styles provides reusable style definitions that follow the conventions
+of the Structurizr service.
+
+The functions in this package are DSL helpers and must be called inside
+Styles, for example:
+
+    Views(func() {
+        Styles(func() {
+            styles.DefaultInteractionStyles()
+        })
+    })
+*/
+package styles
+
+import (
+	"goa.design/goa/v3/eval"
+	. "goa.design/model/dsl"
+	"goa.design/model/expr"
+)
+
+// DefaultInteractionStyles defines the relationship styles used to render
+// relationships by interaction style: synchronous relationships are rendered
+// with solid lines and asynchronous relationships with dashed lines.
+//
+// DefaultInteractionStyles must appear in Styles.
+func DefaultInteractionStyles() {
+	RelationshipStyle(expr.TagSynchronous, func() {
+		Solid()
+	})
+	RelationshipStyle(expr.TagAsynchronous, func() {
+		Dashed()
+	})
+}
+
+// PropagateEndpointTags copies tags from relationship endpoints onto the
+// relationships so that relationships can be styled according to the elements
+// they connect. For each relationship whose source or destination has one of
+// the given tags, the tag prefixed with prefix is added to the relationship
+// tags when the design is finalized.
+//
+// PropagateEndpointTags must appear in Styles.
+//
+// PropagateEndpointTags takes the prefix as first argument followed by the
+// tags to copy.
+//
+// Example:
+//
+//    Styles(func() {
+//        styles.PropagateEndpointTags("To ", "External", "Database")
+//        RelationshipStyle("To External", func() {
+//            Dashed()
+//        })
+//    })
+//
+func PropagateEndpointTags(prefix string, tags ...string) {
+	s, ok := eval.Current().(*expr.Styles)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(tags) == 0 {
+		eval.ReportError("PropagateEndpointTags: at least one tag is required")
+		return
+	}
+	s.EndpointTags = append(s.EndpointTags, &expr.EndpointTags{Prefix: prefix, Tags: tags})
+}
@@ -25,6 +25,9 @@ type (
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
+		// Group is the name of the group the element belongs to if any,
+		// nested group names are separated with slashes.
+		Group string `json:"group,omitempty"`
 		// Location of person.
 		Location LocationKind `json:"location,omitempty"`
 	}
@@ -48,6 +51,9 @@ type (
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
+		// Group is the name of the group the element belongs to if any,
+		// nested group names are separated with slashes.
+		Group string `json:"group,omitempty"`
 		// Location of element.
 		Location LocationKind `json:"location,omitempty"`
 		// Containers list the containers within the software system.
@@ -73,6 +79,9 @@ type (
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
+		// Group is the name of the group the element belongs to if any,
+		// nested group names are separated with slashes.
+		Group string `json:"group,omitempty"`
 		// Components list the components within the container.
 		Components []*Component `json:"components,omitempty"`
 	}
@@ -96,6 +105,26 @@ type (
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
+		// Group is the name of the group the element belongs to if any,
+		// nested group names are separated with slashes.
+		Group string `json:"group,omitempty"`
+		// Code lists the code elements that implement the component.
+		Code []*CodeElement `json:"code,omitempty"`
+	}
+
+	// CodeElement describes a code element (e.g. a class) that implements
+	// a component.
+	CodeElement struct {
+		// Role of the code element, "Primary" or "Supporting".
+		Role string `json:"role,omitempty"`
+		// Name of the code element.
+		Name string `json:"name"`
+		// Type is the fully qualified type name of the code element.
+		Type string `json:"type,omitempty"`
+		// URL of the source code of the code element if any.
+		URL string `json:"url,omitempty"`
+		// Category of the code element, e.g. "class" or "interface".
+		Category string `json:"category,omitempty"`
 	}
 
 	// LocationKind is the enum for possible locations.
@@ -1,10 +1,32 @@
 package stz
 
 import (
+	"strconv"
+	"strings"
+
 	"goa.design/goa/v3/eval"
 	"goa.design/model/expr"
 )
 
+const (
+	// LegendProperty is the name of the view property that records whether
+	// the legend should be rendered.
+	LegendProperty = "structurizr.legend"
+	// TitleProperty is the name of the view property that records whether
+	// the title should be rendered.
+	TitleProperty = "structurizr.title"
+	// WeightProperty is the name of the relationship property that records
+	// the weight of the relationship.
+	WeightProperty = "weight"
+	// InterfaceProperty is the name of the relationship property that
+	// records the interface of the destination component targeted by the
+	// relationship.
+	InterfaceProperty = "interface"
+	// GroupSeparatorProperty is the name of the model property that
+	// records the separator used in the names of nested groups.
+	GroupSeparatorProperty = "structurizr.groupSeparator"
+)
+
 // RunDSL runs the DSL defined in a global variable and returns the corresponding
 // Structurize workspace.
 func RunDSL() (*Workspace, error) {
@@ -24,13 +46,16 @@ func WorkspaceFromDesign(d *expr.Design) *Workspace {
 	}
 	model.People = make([]*Person, len(m.People))
 	for i, p := range m.People {
-		model.People[i] = modelizePerson(p)
+		model.People[i] = modelizePerson(m, p)
 	}
 	model.Systems = make([]*SoftwareSystem, len(m.Systems))
 	for i, sys := range m.Systems {
-		model.Systems[i] = modelizeSystem(sys)
+		model.Systems[i] = modelizeSystem(m, sys)
+	}
+	model.DeploymentNodes = modelizeDeploymentNodes(m, m.DeploymentNodes)
+	if hasNestedGroups(m) {
+		model.Properties = map[string]string{GroupSeparatorProperty: expr.GroupSeparator}
 	}
-	model.DeploymentNodes = modelizeDeploymentNodes(m.DeploymentNodes)
 
 	views := &Views{}
 	v := d.Views
@@ -95,6 +120,19 @@ func WorkspaceFromDesign(d *expr.Design) *Workspace {
 			Tags:        lv.FilterTags,
 		}
 	}
+	views.ImageViews = make([]*ImageView, len(v.ImageViews))
+	for i, iv := range v.ImageViews {
+		// Errors are reported when validating the views.
+		content, contentType, _ := iv.ContentData()
+		views.ImageViews[i] = &ImageView{
+			Key:         iv.Key,
+			ElementID:   iv.ElementID,
+			Title:       iv.Title,
+			Description: iv.Description,
+			Content:     content,
+			ContentType: contentType,
+		}
+	}
 	views.Configuration = &Configuration{Styles: modelizeStyles(v.Styles)}
 
 	return &Workspace{
@@ -106,7 +144,7 @@ func WorkspaceFromDesign(d *expr.Design) *Workspace {
 	}
 }
 
-func modelizePerson(p *expr.Person) *Person {
+func modelizePerson(m *expr.Model, p *expr.Person) *Person {
 	return &Person{
 		ID:            p.Element.ID,
 		Name:          p.Element.Name,
@@ -115,11 +153,39 @@ func modelizePerson(p *expr.Person) *Person {
 		Tags:          p.Element.Tags,
 		URL:           p.Element.URL,
 		Properties:    p.Element.Properties,
-		Relationships: modelizeRelationships(p.Relationships),
+		Relationships: modelizeRelationships(m.ElementRelationships(p.Element)),
+		Group:         p.Element.Group,
 		Location:      LocationKind(p.Location),
 	}
 }
 
+// hasNestedGroups returns true if an element of the model belongs to a nested
+// group.
+func hasNestedGroups(m *expr.Model) bool {
+	nested := func(e *expr.Element) bool { return strings.Contains(e.Group, expr.GroupSeparator) }
+	for _, p := range m.People {
+		if nested(p.Element) {
+			return true
+		}
+	}
+	for _, s := range m.Systems {
+		if nested(s.Element) {
+			return true
+		}
+		for _, c := range s.Containers {
+			if nested(c.Element) {
+				return true
+			}
+			for _, cmp := range c.Components {
+				if nested(cmp.Element) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 func modelizeRelationships(rels []*expr.Relationship) []*Relationship {
 	res := make([]*Relationship, len(rels))
 	for i, r := range rels {
@@ -134,11 +200,20 @@ func modelizeRelationships(rels []*expr.Relationship) []*Relationship {
 			InteractionStyle:     InteractionStyleKind(r.InteractionStyle),
 			LinkedRelationshipID: r.LinkedRelationshipID,
 		}
+		if r.Weight > 0 || r.Interface != "" {
+			res[i].Properties = make(map[string]string)
+			if r.Weight > 0 {
+				res[i].Properties[WeightProperty] = strconv.Itoa(r.Weight)
+			}
+			if r.Interface != "" {
+				res[i].Properties[InterfaceProperty] = r.Interface
+			}
+		}
 	}
 	return res
 }
 
-func modelizeSystem(sys *expr.SoftwareSystem) *SoftwareSystem {
+func modelizeSystem(m *expr.Model, sys *expr.SoftwareSystem) *SoftwareSystem {
 	return &SoftwareSystem{
 		ID:            sys.ID,
 		Name:          sys.Name,
@@ -147,13 +222,14 @@ func modelizeSystem(sys *expr.SoftwareSystem) *SoftwareSystem {
 		Tags:          sys.Tags,
 		URL:           sys.URL,
 		Properties:    sys.Properties,
-		Relationships: modelizeRelationships(sys.Relationships),
+		Relationships: modelizeRelationships(m.ElementRelationships(sys.Element)),
+		Group:         sys.Group,
 		Location:      LocationKind(sys.Location),
-		Containers:    modelizeContainers(sys.Containers),
+		Containers:    modelizeContainers(m, sys.Containers),
 	}
 }
 
-func modelizeContainers(cs []*expr.Container) []*Container {
+func modelizeContainers(m *expr.Model, cs []*expr.Container) []*Container {
 	res := make([]*Container, len(cs))
 	for i, c := range cs {
 		res[i] = &Container{
@@ -164,14 +240,15 @@ func modelizeContainers(cs []*expr.Container) []*Container {
 			Tags:          c.Tags,
 			URL:           c.URL,
 			Properties:    c.Properties,
-			Relationships: modelizeRelationships(c.Relationships),
-			Components:    modelizeComponents(c.Components),
+			Relationships: modelizeRelationships(m.ElementRelationships(c.Element)),
+			Group:         c.Group,
+			Components:    modelizeComponents(m, c.Components),
 		}
 	}
 	return res
 }
 
-func modelizeComponents(cs []*expr.Component) []*Component {
+func modelizeComponents(m *expr.Model, cs []*expr.Component) []*Component {
 	res := make([]*Component, len(cs))
 	for i, c := range cs {
 		res[i] = &Component{
@@ -182,16 +259,42 @@ func modelizeComponents(cs []*expr.Component) []*Component {
 			Tags:          c.Tags,
 			URL:           c.URL,
 			Properties:    c.Properties,
-			Relationships: modelizeRelationships(c.Relationships),
+			Relationships: modelizeRelationships(m.ElementRelationships(c.Element)),
+			Group:         c.Group,
+			Code:          modelizeCodeElements(c.CodeElements),
+		}
+	}
+	return res
+}
+
+// modelizeCodeElements converts the component code elements, the first code
+// element is the primary one. The name of the code element is used as fully
+// qualified type name and its last dot separated segment as name.
+func modelizeCodeElements(ces []*expr.CodeElement) []*CodeElement {
+	if len(ces) == 0 {
+		return nil
+	}
+	res := make([]*CodeElement, len(ces))
+	for i, ce := range ces {
+		role := "Supporting"
+		if i == 0 {
+			role = "Primary"
+		}
+		res[i] = &CodeElement{
+			Role:     role,
+			Name:     ce.Name[strings.LastIndex(ce.Name, ".")+1:],
+			Type:     ce.Name,
+			URL:      ce.URL,
+			Category: strings.ToLower(ce.Type),
 		}
 	}
 	return res
 }
 
-func modelizeDeploymentNodes(dns []*expr.DeploymentNode) []*DeploymentNode {
+func modelizeDeploymentNodes(m *expr.Model, dns []*expr.DeploymentNode) []*DeploymentNode {
 	res := make([]*DeploymentNode, len(dns))
 	for i, dn := range dns {
-		children := modelizeDeploymentNodes(dn.Children)
+		children := modelizeDeploymentNodes(m, dn.Children)
 		infs := make([]*InfrastructureNode, len(dn.InfrastructureNodes))
 		for i, inf := range dn.InfrastructureNodes {
 			infs[i] = &InfrastructureNode{
@@ -202,7 +305,7 @@ func modelizeDeploymentNodes(dns []*expr.DeploymentNode) []*DeploymentNode {
 				Tags:          inf.Tags,
 				URL:           inf.URL,
 				Properties:    inf.Properties,
-				Relationships: modelizeRelationships(inf.Relationships),
+				Relationships: modelizeRelationships(m.ElementRelationships(inf.Element)),
 				Environment:   inf.Environment,
 			}
 		}
@@ -213,7 +316,7 @@ func modelizeDeploymentNodes(dns []*expr.DeploymentNode) []*DeploymentNode {
 				Tags:          ci.Tags,
 				URL:           ci.URL,
 				Properties:    ci.Properties,
-				Relationships: modelizeRelationships(ci.Relationships),
+				Relationships: modelizeRelationships(m.ElementRelationships(ci.Element)),
 				ContainerID:   ci.ContainerID,
 				InstanceID:    ci.InstanceID,
 				Environment:   ci.Environment,
@@ -232,6 +335,7 @@ func modelizeDeploymentNodes(dns []*expr.DeploymentNode) []*DeploymentNode {
 			Instances:           dn.Instances,
 			Tags:                dn.Tags,
 			URL:                 dn.URL,
+			Relationships:       modelizeRelationships(m.ElementRelationships(dn.Element)),
 		}
 	}
 	return res
@@ -263,11 +367,24 @@ func modelizeProps(prop *expr.ViewProps) *ViewProps {
 	}
 	if layout := prop.AutoLayout; layout != nil {
 		props.AutoLayout = &AutoLayout{
-			RankDirection: RankDirectionKind(layout.RankDirection),
-			RankSep:       layout.RankSep,
-			NodeSep:       layout.NodeSep,
-			EdgeSep:       layout.EdgeSep,
-			Vertices:      layout.Vertices,
+			Implementation: ImplementationKind(layout.Implementation),
+			RankDirection:  RankDirectionKind(layout.RankDirection),
+			RankSep:        layout.RankSep,
+			NodeSep:        layout.NodeSep,
+			EdgeSep:        layout.EdgeSep,
+			Vertices:       layout.Vertices,
+		}
+	}
+	if d := prop.Dimensions; d != nil {
+		props.Dimensions = &Dimensions{Width: d.Width, Height: d.Height}
+	}
+	if prop.Legend != nil || prop.HideTitle {
+		props.Properties = make(map[string]string)
+		if prop.Legend != nil {
+			props.Properties[LegendProperty] = strconv.FormatBool(*prop.Legend)
+		}
+		if prop.HideTitle {
+			props.Properties[TitleProperty] = "false"
 		}
 	}
 	return props
@@ -281,6 +398,14 @@ func modelizeElementViews(evs []*expr.ElementView) []*ElementView {
 			X:  ev.X,
 			Y:  ev.Y,
 		}
+		if st := ev.Style; st != nil {
+			res[i].Style = &ElementViewStyle{
+				Background: st.Background,
+				Stroke:     st.Stroke,
+				Color:      st.Color,
+				Shape:      ShapeKind(st.Shape),
+			}
+		}
 	}
 	return res
 }
@@ -299,6 +424,7 @@ func modelizeRelationshipViews(rvs []*expr.RelationshipView) []*RelationshipView
 			Vertices:    vertices,
 			Routing:     RoutingKind(rv.Routing),
 			Position:    rv.Position,
+			Response:    rv.Response,
 		}
 	}
 	return res
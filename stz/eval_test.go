@@ -0,0 +1,108 @@
+package stz
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"goa.design/model/expr"
+)
+
+func TestModelizePropsLegendAndTitle(t *testing.T) {
+	t.Parallel()
+	enabled, disabled := true, false
+	tests := []struct {
+		name      string
+		legend    *bool
+		hideTitle bool
+		want      string
+	}{
+		{"default", nil, false, ""},
+		{"enable-legend", &enabled, false, `"properties":{"structurizr.legend":"true"}`},
+		{"disable-legend", &disabled, false, `"properties":{"structurizr.legend":"false"}`},
+		{"hide-title", nil, true, `"properties":{"structurizr.title":"false"}`},
+		{"disable-legend-hide-title", &disabled, true, `"properties":{"structurizr.legend":"false","structurizr.title":"false"}`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			props := modelizeProps(&expr.ViewProps{Key: "view", Legend: tt.legend, HideTitle: tt.hideTitle})
+
+			js, err := json.Marshal(props)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if strings.Contains(string(js), `"properties"`) {
+					t.Errorf("got %s, want no properties", js)
+				}
+				return
+			}
+			if !strings.Contains(string(js), tt.want) {
+				t.Errorf("got %s, want it to contain %s", js, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkspaceFromDesignImpliedRelationships(t *testing.T) {
+	t.Parallel()
+	var (
+		user  = &expr.Person{Element: &expr.Element{ID: "user", Name: "User"}}
+		sys   = &expr.SoftwareSystem{Element: &expr.Element{ID: "sys", Name: "System"}}
+		api   = &expr.Container{Element: &expr.Element{ID: "api", Name: "API"}, System: sys}
+		cmp   = &expr.Component{Element: &expr.Element{ID: "cmp", Name: "Handler"}, Container: api}
+		node  = &expr.DeploymentNode{Element: &expr.Element{ID: "node", Name: "Node"}, Environment: "Production"}
+		infra = &expr.InfrastructureNode{Element: &expr.Element{ID: "infra", Name: "LB"}, Environment: "Production"}
+		inst  = &expr.ContainerInstance{Element: &expr.Element{ID: "inst"}, ContainerID: api.ID, Environment: "Production"}
+		other = &expr.SoftwareSystem{Element: &expr.Element{ID: "other", Name: "Other"}}
+	)
+	sys.Containers = expr.Containers{api}
+	api.Components = expr.Components{cmp}
+	node.InfrastructureNodes = expr.InfrastructureNodes{infra}
+	node.ContainerInstances = expr.ContainerInstances{inst}
+	var implied []*expr.Relationship
+	for _, e := range []*expr.Element{user.Element, sys.Element, api.Element, cmp.Element, node.Element, infra.Element, inst.Element} {
+		implied = append(implied, &expr.Relationship{ID: e.ID + "-implied", Source: e, Destination: other.Element, Implied: true})
+	}
+	d := &expr.Design{
+		Model: &expr.Model{
+			People:                       expr.People{user},
+			Systems:                      expr.SoftwareSystems{sys, other},
+			DeploymentNodes:              []*expr.DeploymentNode{node},
+			SeparateImpliedRelationships: true,
+			ImpliedRelationships:         implied,
+		},
+		Views: &expr.Views{Styles: &expr.Styles{}},
+	}
+
+	w := WorkspaceFromDesign(d)
+
+	m := w.Model
+	tests := []struct {
+		name string
+		rels []*Relationship
+		want string
+	}{
+		{"person", m.People[0].Relationships, "user-implied"},
+		{"software-system", m.Systems[0].Relationships, "sys-implied"},
+		{"container", m.Systems[0].Containers[0].Relationships, "api-implied"},
+		{"component", m.Systems[0].Containers[0].Components[0].Relationships, "cmp-implied"},
+		{"deployment-node", m.DeploymentNodes[0].Relationships, "node-implied"},
+		{"infrastructure-node", m.DeploymentNodes[0].InfrastructureNodes[0].Relationships, "infra-implied"},
+		{"container-instance", m.DeploymentNodes[0].ContainerInstances[0].Relationships, "inst-implied"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if len(tt.rels) != 1 {
+				t.Fatalf("got %d relationships, want 1", len(tt.rels))
+			}
+			if got := tt.rels[0].ID; got != tt.want {
+				t.Errorf("got relationship %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
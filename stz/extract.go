@@ -0,0 +1,332 @@
+package stz
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExtractView returns a new workspace that contains only the view with the
+// given key, the elements and relationships it references and the styles that
+// apply to them. The parents of the elements in the view (e.g. the software
+// system of a container or the deployment nodes of a container instance) are
+// kept so that the resulting workspace is self-contained. The documentation is
+// not included. If the view is a filtered view then its base view is kept as
+// well.
+func (w *Workspace) ExtractView(key string) (*Workspace, error) {
+	if w.Views == nil {
+		return nil, fmt.Errorf("view %q not found", key)
+	}
+	views, props := w.Views.extract(key)
+	if views == nil {
+		return nil, fmt.Errorf("view %q not found", key)
+	}
+
+	// Compute elements and relationships referenced by the view.
+	idx := newModelIndex(w.Model)
+	elems := make(map[string]bool)
+	rels := make(map[string]bool)
+	for _, vp := range props {
+		for _, ev := range vp.ElementViews {
+			elems[ev.ID] = true
+		}
+		for _, rv := range vp.RelationshipViews {
+			rels[rv.ID] = true
+		}
+	}
+	for _, id := range views.scopeIDs() {
+		elems[id] = true
+	}
+	for id := range rels {
+		if r, ok := idx.rels[id]; ok && r.LinkedRelationshipID != "" {
+			rels[r.LinkedRelationshipID] = true
+		}
+	}
+	for id := range rels {
+		if r, ok := idx.rels[id]; ok {
+			elems[r.SourceID] = true
+			elems[r.DestinationID] = true
+		}
+	}
+	for _, id := range keys(elems) {
+		if ci, ok := idx.instances[id]; ok {
+			elems[ci.ContainerID] = true
+		}
+	}
+	for _, id := range keys(elems) {
+		for p := idx.parents[id]; p != ""; p = idx.parents[p] {
+			elems[p] = true
+		}
+	}
+
+	// Prune the model.
+	tags := make(map[string]bool)
+	addTags := func(t string) {
+		for _, tag := range strings.Split(t, ",") {
+			tags[strings.TrimSpace(tag)] = true
+		}
+	}
+	filterRels := func(rs []*Relationship) []*Relationship {
+		var res []*Relationship
+		for _, r := range rs {
+			if rels[r.ID] {
+				res = append(res, r)
+				addTags(r.Tags)
+			}
+		}
+		return res
+	}
+	var model *Model
+	if w.Model != nil {
+		model = &Model{Enterprise: w.Model.Enterprise}
+		for _, p := range w.Model.People {
+			if elems[p.ID] {
+				cp := *p
+				cp.Relationships = filterRels(p.Relationships)
+				addTags(p.Tags)
+				model.People = append(model.People, &cp)
+			}
+		}
+		for _, s := range w.Model.Systems {
+			if !elems[s.ID] {
+				continue
+			}
+			cs := *s
+			cs.Relationships = filterRels(s.Relationships)
+			cs.Containers = nil
+			addTags(s.Tags)
+			for _, c := range s.Containers {
+				if !elems[c.ID] {
+					continue
+				}
+				cc := *c
+				cc.Relationships = filterRels(c.Relationships)
+				cc.Components = nil
+				addTags(c.Tags)
+				for _, cmp := range c.Components {
+					if !elems[cmp.ID] {
+						continue
+					}
+					ccmp := *cmp
+					ccmp.Relationships = filterRels(cmp.Relationships)
+					addTags(cmp.Tags)
+					cc.Components = append(cc.Components, &ccmp)
+				}
+				cs.Containers = append(cs.Containers, &cc)
+			}
+			model.Systems = append(model.Systems, &cs)
+		}
+		var prune func([]*DeploymentNode) []*DeploymentNode
+		prune = func(nodes []*DeploymentNode) []*DeploymentNode {
+			var res []*DeploymentNode
+			for _, n := range nodes {
+				if !elems[n.ID] {
+					continue
+				}
+				cn := *n
+				cn.Relationships = filterRels(n.Relationships)
+				cn.Children = prune(n.Children)
+				cn.InfrastructureNodes = nil
+				cn.ContainerInstances = nil
+				addTags(n.Tags)
+				for _, i := range n.InfrastructureNodes {
+					if elems[i.ID] {
+						ci := *i
+						ci.Relationships = filterRels(i.Relationships)
+						addTags(i.Tags)
+						cn.InfrastructureNodes = append(cn.InfrastructureNodes, &ci)
+					}
+				}
+				for _, i := range n.ContainerInstances {
+					if elems[i.ID] {
+						ci := *i
+						ci.Relationships = filterRels(i.Relationships)
+						addTags(i.Tags)
+						cn.ContainerInstances = append(cn.ContainerInstances, &ci)
+					}
+				}
+				res = append(res, &cn)
+			}
+			return res
+		}
+		model.DeploymentNodes = prune(w.Model.DeploymentNodes)
+	}
+
+	// Keep the styles that apply to the remaining elements and relationships.
+	if c := w.Views.Configuration; c != nil {
+		cc := *c
+		if c.Styles != nil {
+			styles := &Styles{}
+			for _, es := range c.Styles.Elements {
+				if tags[es.Tag] {
+					styles.Elements = append(styles.Elements, es)
+				}
+			}
+			for _, rs := range c.Styles.Relationships {
+				if tags[rs.Tag] {
+					styles.Relationships = append(styles.Relationships, rs)
+				}
+			}
+			cc.Styles = styles
+		}
+		cc.DefaultView = ""
+		cc.LastSavedView = ""
+		views.Configuration = &cc
+	}
+
+	return &Workspace{
+		Name:        w.Name,
+		Description: w.Description,
+		Version:     w.Version,
+		Model:       model,
+		Views:       views,
+	}, nil
+}
+
+// extract returns a Views struct that contains only the view with the given
+// key as well as the properties of the views that were kept. extract returns
+// nil if there is no view with the given key.
+func (vs *Views) extract(key string) (*Views, []*ViewProps) {
+	res := &Views{}
+	for _, fv := range vs.FilteredViews {
+		if fv.Key == key {
+			res.FilteredViews = []*FilteredView{fv}
+			key = fv.BaseKey
+			break
+		}
+	}
+	var props []*ViewProps
+	for _, v := range vs.LandscapeViews {
+		if v.Key == key {
+			res.LandscapeViews = []*LandscapeView{v}
+			props = append(props, v.ViewProps)
+		}
+	}
+	for _, v := range vs.ContextViews {
+		if v.Key == key {
+			res.ContextViews = []*ContextView{v}
+			props = append(props, v.ViewProps)
+		}
+	}
+	for _, v := range vs.ContainerViews {
+		if v.Key == key {
+			res.ContainerViews = []*ContainerView{v}
+			props = append(props, v.ViewProps)
+		}
+	}
+	for _, v := range vs.ComponentViews {
+		if v.Key == key {
+			res.ComponentViews = []*ComponentView{v}
+			props = append(props, v.ViewProps)
+		}
+	}
+	for _, v := range vs.DynamicViews {
+		if v.Key == key {
+			res.DynamicViews = []*DynamicView{v}
+			props = append(props, v.ViewProps)
+		}
+	}
+	for _, v := range vs.DeploymentViews {
+		if v.Key == key {
+			res.DeploymentViews = []*DeploymentView{v}
+			props = append(props, v.ViewProps)
+		}
+	}
+	if len(props) == 0 {
+		return nil, nil
+	}
+	return res, props
+}
+
+// scopeIDs returns the IDs of the elements the views are scoped to.
+func (vs *Views) scopeIDs() []string {
+	var ids []string
+	for _, v := range vs.ContextViews {
+		ids = append(ids, v.SoftwareSystemID)
+	}
+	for _, v := range vs.ContainerViews {
+		ids = append(ids, v.SoftwareSystemID)
+	}
+	for _, v := range vs.ComponentViews {
+		ids = append(ids, v.ContainerID)
+	}
+	for _, v := range vs.DynamicViews {
+		if v.ElementID != "" {
+			ids = append(ids, v.ElementID)
+		}
+	}
+	for _, v := range vs.DeploymentViews {
+		if v.SoftwareSystemID != "" {
+			ids = append(ids, v.SoftwareSystemID)
+		}
+	}
+	return ids
+}
+
+// modelIndex indexes the relationships, container instances and element
+// parents of a model by ID.
+type modelIndex struct {
+	rels      map[string]*Relationship
+	instances map[string]*ContainerInstance
+	parents   map[string]string
+}
+
+// newModelIndex indexes the given model.
+func newModelIndex(m *Model) *modelIndex {
+	idx := &modelIndex{
+		rels:      make(map[string]*Relationship),
+		instances: make(map[string]*ContainerInstance),
+		parents:   make(map[string]string),
+	}
+	if m == nil {
+		return idx
+	}
+	addRels := func(rs []*Relationship) {
+		for _, r := range rs {
+			idx.rels[r.ID] = r
+		}
+	}
+	for _, p := range m.People {
+		addRels(p.Relationships)
+	}
+	for _, s := range m.Systems {
+		addRels(s.Relationships)
+		for _, c := range s.Containers {
+			idx.parents[c.ID] = s.ID
+			addRels(c.Relationships)
+			for _, cmp := range c.Components {
+				idx.parents[cmp.ID] = c.ID
+				addRels(cmp.Relationships)
+			}
+		}
+	}
+	var walk func(parent string, nodes []*DeploymentNode)
+	walk = func(parent string, nodes []*DeploymentNode) {
+		for _, n := range nodes {
+			if parent != "" {
+				idx.parents[n.ID] = parent
+			}
+			addRels(n.Relationships)
+			for _, i := range n.InfrastructureNodes {
+				idx.parents[i.ID] = n.ID
+				addRels(i.Relationships)
+			}
+			for _, ci := range n.ContainerInstances {
+				idx.parents[ci.ID] = n.ID
+				idx.instances[ci.ID] = ci
+				addRels(ci.Relationships)
+			}
+			walk(n.ID, n.Children)
+		}
+	}
+	walk("", m.DeploymentNodes)
+	return idx
+}
+
+// keys returns the keys of m.
+func keys(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
@@ -0,0 +1,130 @@
+package stz
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestExtractView(t *testing.T) {
+	t.Parallel()
+	var (
+		user = &Person{ID: "user", Name: "User", Tags: "Element,Person", Relationships: []*Relationship{
+			{ID: "r1", SourceID: "user", DestinationID: "shop", Tags: "Relationship,Async"},
+		}}
+		api = &Container{ID: "api", Name: "API", Tags: "Element,Container",
+			Components: []*Component{{ID: "handler", Name: "Handler", Tags: "Element,Component"}},
+			Relationships: []*Relationship{
+				{ID: "r2", SourceID: "api", DestinationID: "db", Tags: "Relationship,SQL"},
+			}}
+		db    = &Container{ID: "db", Name: "DB", Tags: "Element,Container"}
+		shop  = &SoftwareSystem{ID: "shop", Name: "Shop", Tags: "Element,Software System", Containers: []*Container{api, db}}
+		other = &SoftwareSystem{ID: "other", Name: "Other", Tags: "Element,Software System,Unused"}
+		ci1   = &ContainerInstance{ID: "ci1", ContainerID: "api", Tags: "Container Instance", Relationships: []*Relationship{
+			{ID: "r3", SourceID: "ci1", DestinationID: "ci2", LinkedRelationshipID: "r2"},
+		}}
+		ci2    = &ContainerInstance{ID: "ci2", ContainerID: "db", Tags: "Container Instance"}
+		docker = &DeploymentNode{ID: "docker", Name: "Docker", Tags: "Element,Deployment Node", ContainerInstances: []*ContainerInstance{ci1, ci2}}
+		server = &DeploymentNode{ID: "server", Name: "Server", Tags: "Element,Deployment Node", Children: []*DeploymentNode{docker},
+			InfrastructureNodes: []*InfrastructureNode{{ID: "lb", Name: "LB", Tags: "Element,Infrastructure Node"}}}
+		unused = &DeploymentNode{ID: "unused", Name: "Unused", Tags: "Element,Deployment Node"}
+	)
+	w := &Workspace{
+		Name:  "Shop",
+		Model: &Model{People: []*Person{user}, Systems: []*SoftwareSystem{shop, other}, DeploymentNodes: []*DeploymentNode{server, unused}},
+		Views: &Views{
+			LandscapeViews: []*LandscapeView{{ViewProps: &ViewProps{
+				Key:               "landscape",
+				ElementViews:      []*ElementView{{ID: "user"}, {ID: "shop"}, {ID: "other"}},
+				RelationshipViews: []*RelationshipView{{ID: "r1"}},
+			}}},
+			DeploymentViews: []*DeploymentView{{Environment: "Production", ViewProps: &ViewProps{
+				Key:               "deploy",
+				ElementViews:      []*ElementView{{ID: "ci1"}, {ID: "ci2"}},
+				RelationshipViews: []*RelationshipView{{ID: "r3"}},
+			}}},
+			FilteredViews: []*FilteredView{{Key: "filtered", BaseKey: "deploy", Mode: "Include", Tags: []string{"Container Instance"}}},
+			Configuration: &Configuration{
+				DefaultView: "landscape",
+				Styles: &Styles{
+					Elements: []*ElementStyle{
+						{Tag: "Person"}, {Tag: "Container"}, {Tag: "Deployment Node"}, {Tag: "Component"}, {Tag: "Unused"},
+					},
+					Relationships: []*RelationshipStyle{{Tag: "SQL"}, {Tag: "Async"}},
+				},
+			},
+		},
+	}
+
+	got, err := w.ExtractView("filtered")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Views.FilteredViews) != 1 || len(got.Views.DeploymentViews) != 1 || len(got.Views.LandscapeViews) != 0 {
+		t.Errorf("got %d filtered, %d deployment and %d landscape views, want 1, 1 and 0",
+			len(got.Views.FilteredViews), len(got.Views.DeploymentViews), len(got.Views.LandscapeViews))
+	}
+	var elems, rels []string
+	addRels := func(rs []*Relationship) {
+		for _, r := range rs {
+			rels = append(rels, r.ID)
+		}
+	}
+	for _, p := range got.Model.People {
+		elems = append(elems, p.ID)
+	}
+	for _, s := range got.Model.Systems {
+		elems = append(elems, s.ID)
+		for _, c := range s.Containers {
+			elems = append(elems, c.ID)
+			addRels(c.Relationships)
+			for _, cmp := range c.Components {
+				elems = append(elems, cmp.ID)
+			}
+		}
+	}
+	var walk func([]*DeploymentNode)
+	walk = func(nodes []*DeploymentNode) {
+		for _, n := range nodes {
+			elems = append(elems, n.ID)
+			for _, i := range n.InfrastructureNodes {
+				elems = append(elems, i.ID)
+			}
+			for _, ci := range n.ContainerInstances {
+				elems = append(elems, ci.ID)
+				addRels(ci.Relationships)
+			}
+			walk(n.Children)
+		}
+	}
+	walk(got.Model.DeploymentNodes)
+	sort.Strings(elems)
+	sort.Strings(rels)
+	if want := "api, ci1, ci2, db, docker, server, shop"; strings.Join(elems, ", ") != want {
+		t.Errorf("got elements %s, want %s", strings.Join(elems, ", "), want)
+	}
+	if want := "r2, r3"; strings.Join(rels, ", ") != want {
+		t.Errorf("got relationships %s, want %s", strings.Join(rels, ", "), want)
+	}
+	var styles []string
+	for _, es := range got.Views.Configuration.Styles.Elements {
+		styles = append(styles, es.Tag)
+	}
+	for _, rs := range got.Views.Configuration.Styles.Relationships {
+		styles = append(styles, rs.Tag)
+	}
+	if want := "Container, Deployment Node, SQL"; strings.Join(styles, ", ") != want {
+		t.Errorf("got styles %s, want %s", strings.Join(styles, ", "), want)
+	}
+	if got.Views.Configuration.DefaultView != "" {
+		t.Errorf("got default view %q, want none", got.Views.Configuration.DefaultView)
+	}
+	if len(w.Model.Systems) != 2 || len(api.Components) != 1 || len(server.InfrastructureNodes) != 1 {
+		t.Error("got modified source workspace, want it unchanged")
+	}
+
+	if _, err := w.ExtractView("unknown"); err == nil {
+		t.Error("got no error for an unknown view, want one")
+	}
+}
@@ -0,0 +1,72 @@
+package stz
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ToWorkspaceJSONIndented returns an indented JSON representation of the
+// workspace suitable for version control: object keys are sorted and arrays of
+// objects are sorted deterministically. Relationships are sorted by source ID,
+// destination ID and description, views by key and all other objects that
+// define an ID by ID. Arrays of objects that define neither are left in their
+// original order as their order is meaningful (e.g. animation steps).
+func ToWorkspaceJSONIndented(w *Workspace, indent string) ([]byte, error) {
+	js, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(js, &v); err != nil {
+		return nil, err
+	}
+	sortJSON(v)
+	// encoding/json sorts map keys.
+	return json.MarshalIndent(v, "", indent)
+}
+
+// sortJSON recursively sorts the arrays of objects contained in v.
+func sortJSON(v interface{}) {
+	switch a := v.(type) {
+	case map[string]interface{}:
+		for _, val := range a {
+			sortJSON(val)
+		}
+	case []interface{}:
+		for _, val := range a {
+			sortJSON(val)
+		}
+		keys := make([]string, len(a))
+		for i, val := range a {
+			obj, ok := val.(map[string]interface{})
+			if !ok {
+				return
+			}
+			switch {
+			case obj["sourceId"] != nil && obj["destinationId"] != nil:
+				keys[i] = fmt.Sprintf("%v\x00%v\x00%v\x00%v", obj["sourceId"], obj["destinationId"], obj["description"], obj["id"])
+			case obj["key"] != nil:
+				keys[i] = fmt.Sprint(obj["key"])
+			case obj["id"] != nil:
+				keys[i] = fmt.Sprint(obj["id"])
+			default:
+				return
+			}
+		}
+		sort.Stable(byKeys{a, keys})
+	}
+}
+
+// byKeys sorts values using the corresponding keys.
+type byKeys struct {
+	vals []interface{}
+	keys []string
+}
+
+func (b byKeys) Len() int           { return len(b.vals) }
+func (b byKeys) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKeys) Swap(i, j int) {
+	b.vals[i], b.vals[j] = b.vals[j], b.vals[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
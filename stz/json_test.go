@@ -0,0 +1,57 @@
+package stz
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToWorkspaceJSONIndented(t *testing.T) {
+	newWorkspace := func(reverse bool) *Workspace {
+		alice := &Person{ID: "1", Name: "Alice"}
+		bob := &Person{ID: "2", Name: "Bob"}
+		sys := &SoftwareSystem{ID: "3", Name: "System"}
+		uses := &Relationship{ID: "4", SourceID: "1", DestinationID: "3", Description: "Uses"}
+		reads := &Relationship{ID: "5", SourceID: "1", DestinationID: "3", Description: "Reads"}
+		people := []*Person{alice, bob}
+		rels := []*Relationship{uses, reads}
+		elems := []*ElementView{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+		views := []*LandscapeView{
+			{ViewProps: &ViewProps{Key: "a", ElementViews: elems}},
+			{ViewProps: &ViewProps{Key: "b"}},
+		}
+		if reverse {
+			people = []*Person{bob, alice}
+			rels = []*Relationship{reads, uses}
+			elems[0], elems[2] = elems[2], elems[0]
+			views[0], views[1] = views[1], views[0]
+		}
+		alice.Relationships = rels
+		return &Workspace{
+			Name:  "test",
+			Model: &Model{People: people, Systems: []*SoftwareSystem{sys}},
+			Views: &Views{LandscapeViews: views},
+		}
+	}
+
+	a, err := ToWorkspaceJSONIndented(newWorkspace(false), "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ToWorkspaceJSONIndented(newWorkspace(true), "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) != string(b) {
+		t.Errorf("got different outputs:\n%s\n\n%s", a, b)
+	}
+	js := string(a)
+	if !strings.Contains(js, "\n  \"model\": {") {
+		t.Errorf("expected two spaces indentation, got:\n%s", js)
+	}
+	if strings.Index(js, `"model"`) > strings.Index(js, `"name"`) {
+		t.Errorf("expected sorted keys, got:\n%s", js)
+	}
+	if strings.Index(js, `"Reads"`) > strings.Index(js, `"Uses"`) {
+		t.Errorf("expected relationships sorted by description, got:\n%s", js)
+	}
+}
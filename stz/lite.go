@@ -0,0 +1,104 @@
+package stz
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LiteFilename is the name of the workspace file read by Structurizr Lite.
+const LiteFilename = "workspace.json"
+
+// WriteLite writes the workspace to the given directory in the form expected
+// by Structurizr Lite: the workspace is written to a file named workspace.json
+// and the local icon files referenced by element styles are copied alongside
+// it so that the directory can be mounted as is in the Structurizr Lite
+// container. Icons that are URLs or data URIs are left untouched, icons that
+// are local files are referenced by their base name in the written workspace.
+// Distinct icon files that share a base name are copied under names suffixed
+// with a number (e.g. "icon-2.png"). The directory is created if it does not
+// exist. WriteLite does not modify w.
+func (w *Workspace) WriteLite(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	lite := *w
+	// Structurizr Lite manages the workspace ID and revision.
+	lite.ID = 0
+	lite.Revision = 0
+	if w.Views != nil && w.Views.Configuration != nil && w.Views.Configuration.Styles != nil {
+		views := *w.Views
+		conf := *w.Views.Configuration
+		styles := *w.Views.Configuration.Styles
+		styles.Elements = make([]*ElementStyle, len(w.Views.Configuration.Styles.Elements))
+		icons := &liteIcons{dir: dir, names: make(map[string]string), used: make(map[string]bool)}
+		for i, es := range w.Views.Configuration.Styles.Elements {
+			copied := *es
+			if isLocalIcon(es.Icon) {
+				name, err := icons.copy(es.Icon)
+				if err != nil {
+					return err
+				}
+				copied.Icon = name
+			}
+			styles.Elements[i] = &copied
+		}
+		conf.Styles = &styles
+		views.Configuration = &conf
+		lite.Views = &views
+	}
+	js, err := ToWorkspaceJSONIndented(&lite, "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, LiteFilename), js, 0644)
+}
+
+// isLocalIcon returns true if icon is the path to a local file rather than a
+// URL or a data URI.
+func isLocalIcon(icon string) bool {
+	if icon == "" || strings.HasPrefix(icon, "data:") {
+		return false
+	}
+	u, err := url.Parse(icon)
+	return err != nil || u.Scheme == "" || len(u.Scheme) == 1 // Windows drive letter
+}
+
+// liteIcons copies local icon files into a Structurizr Lite directory.
+type liteIcons struct {
+	// dir is the Structurizr Lite directory.
+	dir string
+	// names maps the paths of the icons already copied to the names of
+	// the copies.
+	names map[string]string
+	// used records the names of the copies.
+	used map[string]bool
+}
+
+// copy copies the icon file at path into the directory and returns the name
+// of the copy relative to the directory. An icon referenced by multiple
+// styles is only copied once, distinct icons never share a name.
+func (li *liteIcons) copy(path string) (string, error) {
+	path = filepath.Clean(path)
+	if name, ok := li.names[path]; ok {
+		return name, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read icon: %s", err)
+	}
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	for i := 2; li.used[name]; i++ {
+		name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(filepath.Base(path), ext), i, ext)
+	}
+	if err := ioutil.WriteFile(filepath.Join(li.dir, name), b, 0644); err != nil {
+		return "", err
+	}
+	li.names[path] = name
+	li.used[name] = true
+	return name, nil
+}
@@ -0,0 +1,91 @@
+package stz
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	icons := map[string]string{
+		"a/icon.png":   "a",
+		"b/icon.png":   "b",
+		"c/icon-2.png": "c",
+	}
+	for path, content := range icons {
+		full := filepath.Join(dir, "src", path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := func(path string) string { return filepath.Join(dir, "src", path) }
+	w := &Workspace{
+		ID:    42,
+		Model: &Model{},
+		Views: &Views{Configuration: &Configuration{Styles: &Styles{Elements: []*ElementStyle{
+			{Tag: "A", Icon: src("a/icon.png")},
+			{Tag: "B", Icon: src("b/icon.png")},
+			{Tag: "C", Icon: src("c/icon-2.png")},
+			{Tag: "A2", Icon: src("a/../a/icon.png")},
+			{Tag: "URL", Icon: "https://example.com/icon.png"},
+		}}}},
+	}
+	out := filepath.Join(dir, "lite")
+
+	if err := w.WriteLite(out); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(out, LiteFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Workspace
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 0 {
+		t.Errorf("got workspace ID %d, want 0", got.ID)
+	}
+	wantIcons := map[string]struct{ icon, content string }{
+		"A":   {"icon.png", "a"},
+		"B":   {"icon-2.png", "b"},
+		"C":   {"icon-2-2.png", "c"},
+		"A2":  {"icon.png", "a"},
+		"URL": {"https://example.com/icon.png", ""},
+	}
+	styles := got.Views.Configuration.Styles.Elements
+	if len(styles) != len(wantIcons) {
+		t.Fatalf("got %d element styles, want %d", len(styles), len(wantIcons))
+	}
+	for _, es := range styles {
+		want := wantIcons[es.Tag]
+		if es.Icon != want.icon {
+			t.Errorf("style %q: got icon %q, want %q", es.Tag, es.Icon, want.icon)
+			continue
+		}
+		if want.content == "" {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(out, want.icon))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != want.content {
+			t.Errorf("style %q: got icon content %q, want %q", es.Tag, content, want.content)
+		}
+	}
+	if w.Views.Configuration.Styles.Elements[0].Icon != src("a/icon.png") {
+		t.Errorf("got original icon %q, want it unchanged", w.Views.Configuration.Styles.Elements[0].Icon)
+	}
+}
@@ -16,6 +16,8 @@ type (
 		Systems []*SoftwareSystem `json:"softwareSystems,omitempty"`
 		// DeploymentNodes list the deployment nodes.
 		DeploymentNodes []*DeploymentNode `json:"deploymentNodes,omitempty"`
+		// Properties is an arbitrary set of associated key-value pairs.
+		Properties map[string]string `json:"properties,omitempty"`
 	}
 
 	// Enterprise describes a named enterprise / organization.
@@ -28,6 +28,8 @@ type (
 		// ID of container-container relationship upon which this container
 		// instance-container instance relationship is based.
 		LinkedRelationshipID string `json:"linkedRelationshipId,omitempty"`
+		// Properties is an arbitrary set of associated key-value pairs.
+		Properties map[string]string `json:"properties,omitempty"`
 	}
 
 	// InteractionStyleKind is the enum for possible interaction styles.
@@ -23,6 +23,8 @@ type (
 		DeploymentViews []*DeploymentView `json:"deploymentViews,omitempty"`
 		// FilteredViews lists the filtered views.
 		FilteredViews []*FilteredView `json:"filteredViews,omitempty"`
+		// ImageViews lists the image views.
+		ImageViews []*ImageView `json:"imageViews,omitempty"`
 		// Configuration contains view specific configuration information.
 		Configuration *Configuration `json:"configuration,omitempty"`
 	}
@@ -106,6 +108,23 @@ type (
 		Tags []string `json:"tags,omitempty"`
 	}
 
+	// ImageView describes a view that renders an image associated with an
+	// element.
+	ImageView struct {
+		// Key used to refer to the view.
+		Key string `json:"key"`
+		// ElementID is the ID of the element the image is associated with.
+		ElementID string `json:"elementId,omitempty"`
+		// Title of the view.
+		Title string `json:"title,omitempty"`
+		// Description of view.
+		Description string `json:"description,omitempty"`
+		// Content is the URL of the image or a base64 data URI.
+		Content string `json:"content,omitempty"`
+		// ContentType is the MIME type of the image, e.g. "image/png".
+		ContentType string `json:"contentType,omitempty"`
+	}
+
 	// ViewProps contains common properties for all views.
 	ViewProps struct {
 		// Title of the view
@@ -116,6 +135,8 @@ type (
 		Key string `json:"key"`
 		// PaperSize is the paper size that should be used to render this view.
 		PaperSize PaperSizeKind `json:"paperSize,omitempty"`
+		// Dimensions is the default size of the rendered view.
+		Dimensions *Dimensions `json:"dimensions,omitempty"`
 		// AutoLayout describes the automatic layout mode for the diagram if
 		// defined.
 		AutoLayout *AutoLayout `json:"automaticLayout,omitempty"`
@@ -125,6 +146,17 @@ type (
 		RelationshipViews []*RelationshipView `json:"relationships,omitempty"`
 		// Animations describes the animation steps if any.
 		Animations []*AnimationStep `json:"animations,omitempty"`
+		// Properties of the view, used to record the legend and title
+		// rendering options.
+		Properties map[string]string `json:"properties,omitempty"`
+	}
+
+	// Dimensions describes the default size of a rendered view.
+	Dimensions struct {
+		// Width of view in pixels.
+		Width int `json:"width"`
+		// Height of view in pixels.
+		Height int `json:"height"`
 	}
 
 	// ElementView describes an instance of a model element (Person,
@@ -136,6 +168,21 @@ type (
 		X *int `json:"x,omitempty"`
 		// Vertical position of element when rendered.
 		Y *int `json:"y,omitempty"`
+		// Style overrides the global styles for the element in this view
+		// only.
+		Style *ElementViewStyle `json:"style,omitempty"`
+	}
+
+	// ElementViewStyle describes the view-scoped style of an element.
+	ElementViewStyle struct {
+		// Background color of element as HTML RGB hex string (e.g. "#ffffff")
+		Background string `json:"background,omitempty"`
+		// Stroke color of element as HTML RGB hex string (e.g. "#000000")
+		Stroke string `json:"stroke,omitempty"`
+		// Foreground (text) color of element as HTML RGB hex string (e.g. "#ffffff")
+		Color string `json:"color,omitempty"`
+		// Standard shape used to render element.
+		Shape ShapeKind `json:"shape,omitempty"`
 	}
 
 	// RelationshipView describes an instance of a model relationship in a
@@ -153,6 +200,9 @@ type (
 		Routing RoutingKind `json:"routing,omitempty"`
 		// Position of annotation along line; 0 (start) to 100 (end).
 		Position *int `json:"position,omitempty"`
+		// Response is true if the relationship view renders the response
+		// to the relationship in a dynamic view.
+		Response bool `json:"response,omitempty"`
 	}
 
 	// Vertex describes the x and y coordinate of a bend in a line.
@@ -175,6 +225,8 @@ type (
 
 	// AutoLayout describes an automatic layout.
 	AutoLayout struct {
+		// Implementation is the automatic layout implementation.
+		Implementation ImplementationKind `json:"implementation,omitempty"`
 		// Algorithm rank direction.
 		RankDirection RankDirectionKind `json:"rankDirection,omitempty"`
 		// RankSep defines the separation between ranks in pixels.
@@ -257,6 +309,10 @@ type (
 	// directions.
 	RankDirectionKind int
 
+	// ImplementationKind is the enum for possible automatic layout
+	// implementations.
+	ImplementationKind int
+
 	// ShapeKind is the enum used to represent shapes used to render elements.
 	ShapeKind int
 
@@ -315,6 +371,12 @@ const (
 	RankRightLeft
 )
 
+const (
+	ImplementationUndefined ImplementationKind = iota
+	ImplementationGraphviz
+	ImplementationDagre
+)
+
 const (
 	ShapeUndefined ShapeKind = iota
 	ShapeBox
@@ -350,6 +412,7 @@ func (v *Views) MarshalJSON() ([]byte, error) {
 	sort.Slice(v.DynamicViews, func(i, j int) bool { return v.DynamicViews[i].Key < v.DynamicViews[j].Key })
 	sort.Slice(v.DeploymentViews, func(i, j int) bool { return v.DeploymentViews[i].Key < v.DeploymentViews[j].Key })
 	sort.Slice(v.FilteredViews, func(i, j int) bool { return v.FilteredViews[i].Key < v.FilteredViews[j].Key })
+	sort.Slice(v.ImageViews, func(i, j int) bool { return v.ImageViews[i].Key < v.ImageViews[j].Key })
 	vv := _views(*v)
 	return json.Marshal(&vv)
 }
@@ -593,6 +656,34 @@ func (r *RankDirectionKind) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON replaces the constant value with the proper string value.
+func (i ImplementationKind) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString(`"`)
+	switch i {
+	case ImplementationGraphviz:
+		buf.WriteString("Graphviz")
+	case ImplementationDagre:
+		buf.WriteString("Dagre")
+	}
+	buf.WriteString(`"`)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON sets the constant from its JSON representation.
+func (i *ImplementationKind) UnmarshalJSON(data []byte) error {
+	var val string
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	switch val {
+	case "Graphviz":
+		*i = ImplementationGraphviz
+	case "Dagre":
+		*i = ImplementationDagre
+	}
+	return nil
+}
+
 // MarshalJSON replaces the constant value with the proper string value.
 func (s ShapeKind) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBufferString(`"`)
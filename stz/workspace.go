@@ -3,6 +3,7 @@ package stz
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 )
 
 type (
@@ -94,6 +95,16 @@ type (
 		Format DocFormatKind `json:"format"`
 		// ID of element (in model) that decision applies to (optional).
 		ElementID string `json:"elementId,omitempty"`
+		// Links to other decisions (e.g. superseded decisions).
+		Links []*DecisionLink `json:"links,omitempty"`
+	}
+
+	// DecisionLink is a link from a decision to another decision.
+	DecisionLink struct {
+		// ID of linked decision.
+		ID string `json:"id"`
+		// Description of link, e.g. "supersedes".
+		Description string `json:"description"`
 	}
 
 	// Image represents a Base64 encoded image (PNG/JPG/GIF).
@@ -130,6 +141,9 @@ const (
 	FormatASCIIDoc
 )
 
+// LinkSupersedes is the description of links created with Supersedes.
+const LinkSupersedes = "supersedes"
+
 const (
 	DecisionUndefined DecisionStatusKind = iota
 	DecisionProposed
@@ -139,6 +153,57 @@ const (
 	DecisionRejected
 )
 
+// Supersedes records that the decision supersedes the decisions with the given
+// IDs. Supersedes ignores IDs that are already linked.
+func (d *Decision) Supersedes(ids ...string) {
+loop:
+	for _, id := range ids {
+		for _, l := range d.Links {
+			if l.ID == id && l.Description == LinkSupersedes {
+				continue loop
+			}
+		}
+		d.Links = append(d.Links, &DecisionLink{ID: id, Description: LinkSupersedes})
+	}
+}
+
+// Decision returns the decision with the given ID, nil if there is none.
+func (doc *Documentation) Decision(id string) *Decision {
+	for _, d := range doc.Decisions {
+		if d.ID == id {
+			return d
+		}
+	}
+	return nil
+}
+
+// SupersededBy returns the decisions that supersede the decision with the
+// given ID in documentation order.
+func (doc *Documentation) SupersededBy(id string) []*Decision {
+	var res []*Decision
+	for _, d := range doc.Decisions {
+		for _, l := range d.Links {
+			if l.ID == id && l.Description == LinkSupersedes {
+				res = append(res, d)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// Validate makes sure that decision links reference existing decisions.
+func (doc *Documentation) Validate() error {
+	for _, d := range doc.Decisions {
+		for _, l := range d.Links {
+			if doc.Decision(l.ID) == nil {
+				return fmt.Errorf("decision %q links to unknown decision %q", d.ID, l.ID)
+			}
+		}
+	}
+	return nil
+}
+
 // MarshalJSON replaces the constant value with the proper string value.
 func (d DocFormatKind) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBufferString(`"`)
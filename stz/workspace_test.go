@@ -0,0 +1,40 @@
+package stz
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecisionSupersedes(t *testing.T) {
+	t.Parallel()
+	first := &Decision{ID: "1", Title: "Use MySQL", Decision: DecisionSuperseded}
+	second := &Decision{ID: "2", Title: "Use PostgreSQL", Decision: DecisionAccepted}
+	second.Supersedes("1", "1")
+	doc := &Documentation{Decisions: []*Decision{first, second}}
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(second.Links) != 1 {
+		t.Errorf("got %d links, want 1", len(second.Links))
+	}
+	if by := doc.SupersededBy("1"); len(by) != 1 || by[0] != second {
+		t.Errorf("got %d superseding decisions, want %q", len(by), second.ID)
+	}
+	if by := doc.SupersededBy("2"); len(by) != 0 {
+		t.Errorf("got %d superseding decisions, want none", len(by))
+	}
+	js, err := json.Marshal(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(js), `"links":[{"id":"1","description":"supersedes"}]`) {
+		t.Errorf("got %s, want links", js)
+	}
+
+	second.Supersedes("3")
+	if err := doc.Validate(); err == nil {
+		t.Error("expected error for unknown decision")
+	}
+}